@@ -1,18 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/archive"
 	"github.com/price-comparison/server/internal/cache"
 	"github.com/price-comparison/server/internal/config"
+	"github.com/price-comparison/server/internal/connector"
+	"github.com/price-comparison/server/internal/geocode"
 	"github.com/price-comparison/server/internal/handler"
+	"github.com/price-comparison/server/internal/jobs"
 	"github.com/price-comparison/server/internal/logger"
 	"github.com/price-comparison/server/internal/metrics"
 	"github.com/price-comparison/server/internal/middleware"
 	"github.com/price-comparison/server/internal/repository"
+	"github.com/price-comparison/server/internal/routing"
 	"github.com/price-comparison/server/internal/usecase"
 )
 
@@ -37,6 +43,21 @@ func main() {
 	storeRepo := repository.NewStoreRepository(db)
 	productRepo := repository.NewProductRepository(db)
 	priceRepo := repository.NewPriceRepository(db)
+	suggestionRepo := repository.NewSuggestionRepository(db)
+	chainRepo := repository.NewChainRepository(db)
+	areaRepo := repository.NewAreaRepository(db)
+	tileRepo := repository.NewTileRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	anomalyRepo := repository.NewAnomalyRepository(db)
+	submissionRepo := repository.NewSubmissionRepository(db)
+	connectorRepo := repository.NewConnectorRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	rollupRepo := repository.NewRollupRepository(db)
+	partitionRepo := repository.NewPartitionRepository(db)
+	priceIndexRepo := repository.NewPriceIndexRepository(db)
+	competitivenessRepo := repository.NewCompetitivenessRepository(db)
+	forecastRepo := repository.NewForecastRepository(db)
 
 	var cacheAdapter usecase.Cache
 	redisClient, err := cache.NewRedisClient(cfg.Redis)
@@ -47,17 +68,143 @@ func main() {
 	}
 	cacheTTL := time.Duration(cfg.Cache.TTLSeconds) * time.Second
 
+	// Travel-time search needs an imported road network
+	var travelRouter usecase.TravelTimeRouter
+	if cfg.Routing.Enabled {
+		travelRouter = routing.NewRouter(repository.NewRoadNetworkRepository(db), float64(cfg.Routing.SnapMeters))
+	}
+
+	// Geocoding defaults to the offline postal-code dataset
+	var geocoder usecase.Geocoder = repository.NewPostalCodeRepository(db)
+	if cfg.Geocoder.Provider == "http" {
+		if cfg.Geocoder.HTTPURL == "" {
+			log.Fatalf("GEOCODER_HTTP_URL is required when GEOCODER_PROVIDER=http")
+		}
+		geocoder = geocode.NewHTTPProvider(
+			cfg.Geocoder.HTTPURL,
+			cfg.Geocoder.HTTPAPIKey,
+			time.Duration(cfg.Geocoder.TimeoutMillis)*time.Millisecond,
+		)
+	}
+
 	// Initialize usecases
-	storeUsecase := usecase.NewStoreUsecase(storeRepo, cacheAdapter, cacheTTL)
+	storeUsecase := usecase.NewStoreUsecase(storeRepo, travelRouter, cacheAdapter, cacheTTL)
 	productUsecase := usecase.NewProductUsecase(productRepo, cacheAdapter, cacheTTL)
 	priceUsecase := usecase.NewPriceUsecase(priceRepo)
+	chainUsecase := usecase.NewChainUsecase(chainRepo, cacheAdapter, cacheTTL)
+	areaUsecase := usecase.NewAreaUsecase(areaRepo)
+	currencyUsecase := usecase.NewCurrencyUsecase(exchangeRateRepo)
+	promotionUsecase := usecase.NewPromotionUsecase(promotionRepo)
+	anomalyUsecase := usecase.NewAnomalyUsecase(anomalyRepo)
+	priceIndexUsecase := usecase.NewPriceIndexUsecase(priceIndexRepo)
+	forecastUsecase := usecase.NewForecastUsecase(forecastRepo)
+	competitivenessPolicy := usecase.CompetitivenessPolicy{
+		RadiusMeters: cfg.Competitiveness.RadiusMeters,
+		WindowDays:   cfg.Competitiveness.WindowDays,
+		MinStores:    cfg.Competitiveness.MinStores,
+		MinProducts:  cfg.Competitiveness.MinProducts,
+	}
+	if err := competitivenessPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid competitiveness config: %v", err)
+	}
+	competitivenessUsecase := usecase.NewCompetitivenessUsecase(competitivenessRepo, competitivenessPolicy)
+	submissionUsecase := usecase.NewSubmissionUsecase(submissionRepo, usecase.TrustPolicy{
+		MinApproved:   cfg.Submissions.TrustedMinApproved,
+		MinReputation: cfg.Submissions.TrustedMinReputation,
+	})
+	tileUsecase := usecase.NewTileUsecase(tileRepo, cacheAdapter, time.Duration(cfg.Tiles.CacheTTLSeconds)*time.Second)
+	geocodeUsecase := usecase.NewGeocodeUsecase(
+		geocoder,
+		cacheAdapter,
+		time.Duration(cfg.Geocoder.CacheTTLSeconds)*time.Second,
+		time.Duration(cfg.Geocoder.TimeoutMillis)*time.Millisecond,
+	)
+	suggestUsecase := usecase.NewSuggestUsecase(
+		suggestionRepo,
+		cacheAdapter,
+		time.Duration(cfg.Suggest.CacheTTLSeconds)*time.Second,
+		time.Duration(cfg.Suggest.TimeoutMillis)*time.Millisecond,
+	)
 
 	// Initialize handlers
-	storeHandler := handler.NewStoreHandler(storeUsecase, priceUsecase)
-	productHandler := handler.NewProductHandler(productUsecase, priceUsecase)
+	storeHandler := handler.NewStoreHandler(storeUsecase, priceUsecase, geocodeUsecase, areaUsecase, cfg.Prices.MinConfidence)
+	productHandler := handler.NewProductHandler(productUsecase, priceUsecase, cfg.Prices.MinConfidence)
+	suggestHandler := handler.NewSuggestHandler(suggestUsecase)
+	chainHandler := handler.NewChainHandler(chainUsecase, priceUsecase)
+	areaHandler := handler.NewAreaHandler(areaUsecase, competitivenessUsecase)
+	geocodeHandler := handler.NewGeocodeHandler(geocodeUsecase)
+	currencyHandler := handler.NewCurrencyHandler(currencyUsecase)
+	promotionHandler := handler.NewPromotionHandler(promotionUsecase)
+	anomalyHandler := handler.NewAnomalyHandler(anomalyUsecase)
+	submissionHandler := handler.NewSubmissionHandler(submissionUsecase)
+	analyticsHandler := handler.NewAnalyticsHandler(priceIndexUsecase, forecastUsecase, areaUsecase)
+	tileHandler := handler.NewTileHandler(tileUsecase, cfg.Tiles.CacheTTLSeconds, cfg.Prices.MinConfidence)
 
-	// Setup Gin router
 	appLogger := logger.New(cfg.Log.Level)
+
+	// Price feed connectors
+	location, err := time.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		log.Printf("Unknown connector timezone %q, using UTC: %v", cfg.Scheduler.Timezone, err)
+		location = time.UTC
+	}
+	scheduler := connector.NewScheduler(connectorRepo, location, appLogger)
+	for _, cc := range cfg.Scheduler.Connectors {
+		if cc.Type != "file" {
+			log.Fatalf("Unknown type %q for connector %s", cc.Type, cc.Name)
+		}
+		if err := scheduler.Add(connector.NewFileConnector(cc.Name, cc.Dir), cc.Schedule); err != nil {
+			log.Fatalf("Failed to add connector %s: %v", cc.Name, err)
+		}
+	}
+	if cfg.Scheduler.Enabled {
+		scheduler.Start(context.Background())
+	}
+	connectorUsecase := usecase.NewConnectorUsecase(connectorRepo, scheduler)
+	connectorHandler := handler.NewConnectorHandler(connectorUsecase)
+
+	// Background jobs
+	jobPool := jobs.NewPool(jobRepo, jobs.Options{
+		Workers:           cfg.Jobs.Workers,
+		PollInterval:      time.Duration(cfg.Jobs.PollIntervalMillis) * time.Millisecond,
+		VisibilityTimeout: time.Duration(cfg.Jobs.VisibilityTimeoutSeconds) * time.Second,
+		BackoffBase:       time.Duration(cfg.Jobs.RetryBackoffSeconds) * time.Second,
+		BackoffMax:        time.Duration(cfg.Jobs.RetryBackoffMaxSeconds) * time.Second,
+		MaxAttempts:       cfg.Jobs.MaxAttempts,
+	}, appLogger)
+	if err := jobPool.Register(connector.RunJobType, jobs.Handle(scheduler.HandleRunJob)); err != nil {
+		log.Fatalf("Failed to register job handler: %v", err)
+	}
+	rollupUsecase := usecase.NewRollupUsecase(rollupRepo)
+	if err := jobPool.Register(usecase.RefreshRollupsJobType, jobs.Handle(rollupUsecase.Refresh)); err != nil {
+		log.Fatalf("Failed to register job handler: %v", err)
+	}
+	if err := jobPool.Every(usecase.RefreshRollupsJobType, time.Duration(cfg.Prices.RollupRefreshSeconds)*time.Second); err != nil {
+		log.Fatalf("Failed to schedule price rollups: %v", err)
+	}
+	partitionUsecase := usecase.NewPartitionUsecase(partitionRepo, archive.NewDir(cfg.Prices.ArchiveDir), usecase.RetentionPolicy{
+		MonthsAhead:     cfg.Prices.PartitionMonthsAhead,
+		RetentionMonths: cfg.Prices.RetentionMonths,
+	})
+	if err := jobPool.Register(usecase.MaintainPricesJobType, jobs.Handle(partitionUsecase.Maintain)); err != nil {
+		log.Fatalf("Failed to register job handler: %v", err)
+	}
+	if err := jobPool.Every(usecase.MaintainPricesJobType, 24*time.Hour); err != nil {
+		log.Fatalf("Failed to schedule price partition maintenance: %v", err)
+	}
+	if err := jobPool.Register(usecase.RefreshCompetitivenessJobType, jobs.Handle(competitivenessUsecase.Refresh)); err != nil {
+		log.Fatalf("Failed to register job handler: %v", err)
+	}
+	if err := jobPool.Every(usecase.RefreshCompetitivenessJobType, time.Duration(cfg.Competitiveness.RefreshSeconds)*time.Second); err != nil {
+		log.Fatalf("Failed to schedule store competitiveness: %v", err)
+	}
+	if cfg.Jobs.Enabled {
+		jobPool.Start(context.Background())
+	}
+	jobUsecase := usecase.NewJobUsecase(jobRepo, jobPool, cfg.Jobs.MaxAttempts)
+	jobHandler := handler.NewJobHandler(jobUsecase)
+
+	// Setup Gin router
 	metrics.Init()
 
 	r := gin.New()
@@ -86,17 +233,105 @@ func main() {
 	// API routes
 	api := r.Group("/api")
 	api.Use(middleware.APIKeyAuth(cfg.Auth.APIKey))
+	// Moderation and operations need a moderator's admin key on top of the
+	// app key; submissions need the contributor's own token
+	adminAuth := middleware.AdminAuth(cfg.Auth.AdminKeys)
+	contributorAuth := middleware.ContributorAuth(cfg.Auth.ContributorTokenSecret)
 	{
 		// Store routes
 		stores := api.Group("/stores")
 		{
 			stores.GET("", storeHandler.GetAllStores)
 			stores.GET("/nearby", storeHandler.GetNearbyStores)
+			stores.GET("/clusters", storeHandler.GetStoreClusters)
+			stores.POST("/search", storeHandler.SearchStores)
 			stores.GET("/:id", storeHandler.GetStoreByID)
 			stores.GET("/:id/price-stats", storeHandler.GetStorePriceStats)
 			stores.GET("/:id/prices", storeHandler.GetStorePrices)
 		}
 
+		// Chain routes
+		chains := api.Group("/chains")
+		{
+			chains.GET("", chainHandler.GetAllChains)
+			chains.GET("/:id", chainHandler.GetChainByID)
+			chains.GET("/:id/price-stats", chainHandler.GetChainPriceStats)
+		}
+
+		// Price analytics
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/price-index", analyticsHandler.GetPriceIndex)
+			analytics.GET("/price-forecast", analyticsHandler.GetPriceForecast)
+		}
+
+		// Saved search areas
+		areas := api.Group("/areas")
+		{
+			areas.GET("", areaHandler.GetAllAreas)
+			areas.POST("", adminAuth, areaHandler.CreateArea)
+			areas.GET("/:id", areaHandler.GetAreaByID)
+			areas.GET("/:id/store-ranking", areaHandler.GetStoreRanking)
+		}
+
+		// Exchange rates and currency conversion
+		exchangeRates := api.Group("/exchange-rates")
+		{
+			exchangeRates.GET("", currencyHandler.GetExchangeRates)
+			exchangeRates.POST("", adminAuth, currencyHandler.SaveExchangeRate)
+			exchangeRates.GET("/convert", currencyHandler.Convert)
+		}
+
+		// Promotions (sale prices and multi-buys)
+		promotions := api.Group("/promotions")
+		{
+			promotions.GET("", promotionHandler.GetPromotions)
+			promotions.POST("", adminAuth, promotionHandler.CreatePromotion)
+			promotions.GET("/:id", promotionHandler.GetPromotionByID)
+		}
+
+		// Review of prices quarantined by anomaly screening
+		prices := api.Group("/prices")
+		{
+			prices.GET("/anomalies", anomalyHandler.GetAnomalies)
+			prices.POST("/:id/review", adminAuth, anomalyHandler.ReviewPrice)
+		}
+
+		// Crowdsourced price submissions and their moderation
+		submissions := api.Group("/submissions")
+		{
+			submissions.GET("", submissionHandler.GetSubmissions)
+			submissions.POST("", contributorAuth, submissionHandler.CreateSubmission)
+			submissions.GET("/:id", submissionHandler.GetSubmissionByID)
+			submissions.POST("/:id/review", adminAuth, submissionHandler.ReviewSubmission)
+		}
+
+		// Price feed connectors: schedules, run history and manual runs
+		connectors := api.Group("/connectors")
+		{
+			connectors.GET("", connectorHandler.GetConnectors)
+			connectors.GET("/runs", connectorHandler.GetConnectorRuns)
+			connectors.POST("/:name/run", adminAuth, connectorHandler.RunConnector)
+		}
+
+		// Background job queue
+		jobRoutes := api.Group("/jobs")
+		jobRoutes.Use(adminAuth)
+		{
+			jobRoutes.GET("", jobHandler.GetJobs)
+			jobRoutes.POST("", jobHandler.CreateJob)
+			jobRoutes.GET("/summary", jobHandler.GetJobSummary)
+			jobRoutes.GET("/:id", jobHandler.GetJobByID)
+			jobRoutes.POST("/:id/retry", jobHandler.RetryJob)
+		}
+
+		contributors := api.Group("/contributors")
+		{
+			contributors.GET("", submissionHandler.GetContributors)
+			contributors.GET("/:id", submissionHandler.GetContributorByID)
+			contributors.PUT("/:id/role", adminAuth, submissionHandler.SetContributorRole)
+		}
+
 		// Product routes
 		products := api.Group("/products")
 		{
@@ -106,6 +341,20 @@ func main() {
 			products.GET("/:id", productHandler.GetProductByID)
 			products.GET("/:id/prices", productHandler.GetProductPrices)
 		}
+
+		// Typeahead
+		api.GET("/suggest", suggestHandler.Suggest)
+
+		// Geocoding
+		api.GET("/geocode", geocodeHandler.Geocode)
+		api.GET("/geocode/reverse", geocodeHandler.ReverseGeocode)
+	}
+
+	// Vector tiles for the store map
+	tiles := r.Group("/tiles")
+	tiles.Use(middleware.APIKeyAuth(cfg.Auth.APIKey))
+	{
+		tiles.GET("/stores/:z/:x/:y", tileHandler.GetStoreTile)
 	}
 
 	// Start server
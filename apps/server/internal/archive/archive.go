@@ -0,0 +1,101 @@
+// Package archive writes gzip-compressed files into a directory so that a
+// file only appears under its final name once it is completely written and
+// synced to disk.
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is a directory of archive files
+type Dir struct {
+	path string
+}
+
+func NewDir(path string) *Dir {
+	return &Dir{path: path}
+}
+
+// Create starts the archive file name, creating the directory if needed.
+// Data written to the returned File is compressed into a temporary file
+// until Commit.
+func (d *Dir) Create(name string) (*File, error) {
+	if name == "" || filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid archive name %q", name)
+	}
+	if err := os.MkdirAll(d.path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(d.path, "."+name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	return &File{
+		path: filepath.Join(d.path, name),
+		tmp:  tmp,
+		gzip: gzip.NewWriter(tmp),
+	}, nil
+}
+
+// File is an archive being written. Exactly one of Commit or Abort should
+// follow the writes; calling either again does nothing.
+type File struct {
+	path string
+	tmp  *os.File
+	gzip *gzip.Writer
+	done bool
+}
+
+// Path is where the file appears once committed
+func (f *File) Path() string {
+	return f.path
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	return f.gzip.Write(p)
+}
+
+// Commit finishes the compressed stream, syncs it and moves it to Path. It
+// fails rather than replace an earlier file of that name.
+func (f *File) Commit() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	if err := f.gzip.Close(); err != nil {
+		f.discard()
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+	if err := f.tmp.Sync(); err != nil {
+		f.discard()
+		return fmt.Errorf("failed to sync archive: %w", err)
+	}
+	if err := f.tmp.Close(); err != nil {
+		os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	// A hard link, unlike a rename, never replaces an existing file
+	err := os.Link(f.tmp.Name(), f.path)
+	os.Remove(f.tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to move archive into place: %w", err)
+	}
+	return nil
+}
+
+// Abort discards whatever was written
+func (f *File) Abort() {
+	if f.done {
+		return
+	}
+	f.done = true
+	f.discard()
+}
+
+func (f *File) discard() {
+	f.tmp.Close()
+	os.Remove(f.tmp.Name())
+}
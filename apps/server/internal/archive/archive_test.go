@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitMovesCompressedFileIntoPlace(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archives")
+	file, err := NewDir(dir).Create("prices_y2024m01.jsonl.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := io.WriteString(file, "{\"id\":1}\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(file.Path()); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing at %s before commit, got %v", file.Path(), err)
+	}
+	if err := file.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file.Abort()
+
+	f, err := os.Open(file.Path())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil || string(content) != "{\"id\":1}\n" {
+		t.Fatalf("expected the written line, got %q, %v", content, err)
+	}
+	assertEntries(t, dir, 1)
+}
+
+func TestCommitKeepsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	for i, line := range []string{"first\n", "second\n"} {
+		file, err := NewDir(dir).Create("prices_y2024m01.jsonl.gz")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		io.WriteString(file, line)
+		err = file.Commit()
+		if i == 0 && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i == 1 && err == nil {
+			t.Fatalf("expected commit over an existing archive to fail")
+		}
+	}
+	assertEntries(t, dir, 1)
+}
+
+func TestAbortLeavesNothing(t *testing.T) {
+	dir := t.TempDir()
+	file, err := NewDir(dir).Create("prices_y2024m01.jsonl.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	io.WriteString(file, "partial")
+	file.Abort()
+	if err := file.Commit(); err != nil {
+		t.Fatalf("expected commit after abort to do nothing, got %v", err)
+	}
+	assertEntries(t, dir, 0)
+}
+
+func TestCreateRejectsPaths(t *testing.T) {
+	for _, name := range []string{"", "../prices.jsonl.gz", "sub/prices.jsonl.gz"} {
+		if _, err := NewDir(t.TempDir()).Create(name); err == nil {
+			t.Fatalf("expected error for %q", name)
+		}
+	}
+}
+
+func assertEntries(t *testing.T, dir string, want int) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != want {
+		t.Fatalf("expected %d files in %s, got %d", want, dir, len(entries))
+	}
+}
@@ -28,8 +28,102 @@ type CacheConfig struct {
 	TTLSeconds int
 }
 
+type SuggestConfig struct {
+	TimeoutMillis   int
+	CacheTTLSeconds int
+}
+
+type TileConfig struct {
+	CacheTTLSeconds int
+}
+
+// GeocoderConfig selects the geocoding provider: "postal" uses the imported
+// postal_codes table, "http" a Nominatim-compatible API at HTTPURL
+type GeocoderConfig struct {
+	Provider        string
+	HTTPURL         string
+	HTTPAPIKey      string
+	TimeoutMillis   int
+	CacheTTLSeconds int
+}
+
+type RoutingConfig struct {
+	Enabled    bool
+	SnapMeters int
+}
+
+// PriceConfig sets the confidence (0-1) below which prices are left out of
+// price comparisons and store min_price unless a request overrides it, and
+// how often the daily price rollups behind price statistics catch up.
+// Monthly price partitions are created PartitionMonthsAhead ahead; when
+// RetentionMonths is positive, months older than that are archived to
+// ArchiveDir and dropped.
+type PriceConfig struct {
+	MinConfidence        float64
+	RollupRefreshSeconds int
+	PartitionMonthsAhead int
+	RetentionMonths      int
+	ArchiveDir           string
+}
+
+// SubmissionConfig sets when a contributor is trusted enough for their
+// price submissions to skip moderation
+type SubmissionConfig struct {
+	TrustedMinApproved   int
+	TrustedMinReputation float64
+}
+
+// ConnectorConfig configures one price feed connector. The built-in "file"
+// type reads CSV and JSON files from Dir.
+type ConnectorConfig struct {
+	Name     string
+	Type     string
+	Dir      string
+	Schedule string
+}
+
+// SchedulerConfig lists the connectors and whether this instance runs them
+// on schedule; cron schedules are evaluated in Timezone
+type SchedulerConfig struct {
+	Enabled    bool
+	Timezone   string
+	Connectors []ConnectorConfig
+}
+
+// JobConfig sizes the background job worker pool. Failed jobs are retried
+// after RetryBackoffSeconds, doubling per attempt up to
+// RetryBackoffMaxSeconds, until MaxAttempts.
+type JobConfig struct {
+	Enabled                  bool
+	Workers                  int
+	PollIntervalMillis       int
+	VisibilityTimeoutSeconds int
+	RetryBackoffSeconds      int
+	RetryBackoffMaxSeconds   int
+	MaxAttempts              int
+}
+
+// CompetitivenessConfig sets how store competitiveness scores compare a
+// store's prices over the last WindowDays with stores within RadiusMeters:
+// products need prices at MinStores nearby stores to count, and stores need
+// MinProducts such products to be scored
+type CompetitivenessConfig struct {
+	RadiusMeters   int
+	WindowDays     int
+	MinStores      int
+	MinProducts    int
+	RefreshSeconds int
+}
+
+// AuthConfig holds the credentials the API accepts. APIKey is the shared
+// app key for every /api route. AdminKeys maps each moderator's key to
+// their name, recorded on their reviews; moderation, role changes,
+// connector runs and jobs need one. ContributorTokenSecret verifies the
+// contributor tokens the app's backend issues to signed-in users.
 type AuthConfig struct {
-	APIKey string
+	APIKey                 string
+	AdminKeys              map[string]string
+	ContributorTokenSecret string
 }
 
 type ServerConfig struct {
@@ -43,12 +137,21 @@ type LogConfig struct {
 }
 
 type Config struct {
-	DB     DBConfig
-	Redis  RedisConfig
-	Cache  CacheConfig
-	Auth   AuthConfig
-	Server ServerConfig
-	Log    LogConfig
+	DB              DBConfig
+	Redis           RedisConfig
+	Cache           CacheConfig
+	Suggest         SuggestConfig
+	Routing         RoutingConfig
+	Tiles           TileConfig
+	Geocoder        GeocoderConfig
+	Prices          PriceConfig
+	Submissions     SubmissionConfig
+	Scheduler       SchedulerConfig
+	Jobs            JobConfig
+	Competitiveness CompetitivenessConfig
+	Auth            AuthConfig
+	Server          ServerConfig
+	Log             LogConfig
 }
 
 func Load() Config {
@@ -71,8 +174,60 @@ func Load() Config {
 		Cache: CacheConfig{
 			TTLSeconds: getEnvInt("CACHE_TTL_SECONDS", 60),
 		},
+		Suggest: SuggestConfig{
+			TimeoutMillis:   getEnvInt("SUGGEST_TIMEOUT_MS", 150),
+			CacheTTLSeconds: getEnvInt("SUGGEST_CACHE_TTL_SECONDS", 300),
+		},
+		Routing: RoutingConfig{
+			Enabled:    getEnvBool("ROUTING_ENABLED", false),
+			SnapMeters: getEnvInt("ROUTING_SNAP_METERS", 300),
+		},
+		Tiles: TileConfig{
+			CacheTTLSeconds: getEnvInt("TILE_CACHE_TTL_SECONDS", 600),
+		},
+		Geocoder: GeocoderConfig{
+			Provider:        getEnv("GEOCODER_PROVIDER", "postal"),
+			HTTPURL:         getEnv("GEOCODER_HTTP_URL", ""),
+			HTTPAPIKey:      getEnv("GEOCODER_HTTP_API_KEY", ""),
+			TimeoutMillis:   getEnvInt("GEOCODER_TIMEOUT_MS", 3000),
+			CacheTTLSeconds: getEnvInt("GEOCODER_CACHE_TTL_SECONDS", 86400),
+		},
+		Prices: PriceConfig{
+			MinConfidence:        getEnvFloat("PRICE_MIN_CONFIDENCE", 0),
+			RollupRefreshSeconds: getEnvInt("PRICE_ROLLUP_REFRESH_SECONDS", 60),
+			PartitionMonthsAhead: getEnvInt("PRICE_PARTITION_MONTHS_AHEAD", 3),
+			RetentionMonths:      getEnvInt("PRICE_RETENTION_MONTHS", 0),
+			ArchiveDir:           getEnv("PRICE_ARCHIVE_DIR", "./data/price-archives"),
+		},
+		Submissions: SubmissionConfig{
+			TrustedMinApproved:   getEnvInt("SUBMISSION_TRUSTED_MIN_APPROVED", 10),
+			TrustedMinReputation: getEnvFloat("SUBMISSION_TRUSTED_MIN_REPUTATION", 0.9),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:    getEnvBool("CONNECTOR_SCHEDULER_ENABLED", true),
+			Timezone:   getEnv("CONNECTOR_TIMEZONE", "Asia/Tokyo"),
+			Connectors: loadConnectors(),
+		},
+		Jobs: JobConfig{
+			Enabled:                  getEnvBool("JOB_WORKERS_ENABLED", true),
+			Workers:                  getEnvInt("JOB_WORKERS", 4),
+			PollIntervalMillis:       getEnvInt("JOB_POLL_INTERVAL_MS", 1000),
+			VisibilityTimeoutSeconds: getEnvInt("JOB_VISIBILITY_TIMEOUT_SECONDS", 300),
+			RetryBackoffSeconds:      getEnvInt("JOB_RETRY_BACKOFF_SECONDS", 30),
+			RetryBackoffMaxSeconds:   getEnvInt("JOB_RETRY_BACKOFF_MAX_SECONDS", 3600),
+			MaxAttempts:              getEnvInt("JOB_MAX_ATTEMPTS", 5),
+		},
+		Competitiveness: CompetitivenessConfig{
+			RadiusMeters:   getEnvInt("COMPETITIVENESS_RADIUS_METERS", 5000),
+			WindowDays:     getEnvInt("COMPETITIVENESS_WINDOW_DAYS", 30),
+			MinStores:      getEnvInt("COMPETITIVENESS_MIN_STORES", 3),
+			MinProducts:    getEnvInt("COMPETITIVENESS_MIN_PRODUCTS", 10),
+			RefreshSeconds: getEnvInt("COMPETITIVENESS_REFRESH_SECONDS", 3600),
+		},
 		Auth: AuthConfig{
-			APIKey: getEnv("API_KEY", ""),
+			APIKey:                 getEnv("API_KEY", ""),
+			AdminKeys:              loadAdminKeys(),
+			ContributorTokenSecret: getEnv("CONTRIBUTOR_TOKEN_SECRET", ""),
 		},
 		Server: ServerConfig{
 			Port:         getEnv("PORT", "8080"),
@@ -85,6 +240,39 @@ func Load() Config {
 	}
 }
 
+// loadConnectors reads the connectors named in CONNECTORS, each configured
+// by CONNECTOR_<NAME>_TYPE, _DIR and _SCHEDULE (name upper-cased, dashes
+// as underscores)
+func loadConnectors() []ConnectorConfig {
+	var connectors []ConnectorConfig
+	for _, name := range splitCSV(getEnv("CONNECTORS", "")) {
+		prefix := "CONNECTOR_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		connectors = append(connectors, ConnectorConfig{
+			Name:     name,
+			Type:     getEnv(prefix+"TYPE", "file"),
+			Dir:      getEnv(prefix+"DIR", ""),
+			Schedule: getEnv(prefix+"SCHEDULE", "@hourly"),
+		})
+	}
+	return connectors
+}
+
+// loadAdminKeys reads ADMIN_API_KEYS, a comma-separated list of name:key
+// pairs such as "hanako:3f9c...,ops:8a1d..."
+func loadAdminKeys() map[string]string {
+	keys := map[string]string{}
+	for _, entry := range splitCSV(getEnv("ADMIN_API_KEYS", "")) {
+		name, key, ok := strings.Cut(entry, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			log.Printf("Ignoring an ADMIN_API_KEYS entry that is not name:key")
+			continue
+		}
+		keys[key] = name
+	}
+	return keys
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -105,6 +293,32 @@ func getEnvInt(key string, defaultValue int) int {
 	return parsed
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid float for %s: %s; using default %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid bool for %s: %s; using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 func splitCSV(value string) []string {
 	parts := strings.Split(value, ",")
 	var cleaned []string
@@ -0,0 +1,29 @@
+// Package connector runs price feeds on a schedule. A Connector produces
+// price observations; the Scheduler runs each connector on its cron
+// schedule (or on demand), stores the observations and records the run.
+package connector
+
+import (
+	"context"
+	"time"
+)
+
+// Observation is one price seen by a feed. The product is identified by
+// ProductID, or by Barcode when ProductID is 0. Price is as posted,
+// tax-inclusive unless TaxIncluded is false.
+type Observation struct {
+	StoreID     int
+	ProductID   int
+	Barcode     string
+	Price       float64
+	Currency    string
+	TaxIncluded bool
+	RecordedAt  time.Time
+}
+
+// Connector fetches the current observations of one feed. Name identifies
+// the feed in run history and as the source of the prices it produces.
+type Connector interface {
+	Name() string
+	Fetch(ctx context.Context) ([]Observation, error)
+}
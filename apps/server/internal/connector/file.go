@@ -0,0 +1,216 @@
+package connector
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileConnector reads observations from the .csv and .json files in a
+// local directory, in file name order. Both formats use the fields
+// store_id, product_id or barcode, price, and optionally currency (default
+// JPY), tax_included (default true) and recorded_at (RFC 3339 or
+// YYYY-MM-DD). CSV files need a header row; JSON files hold an array of
+// objects. recorded_at defaults to the file's modification time, so
+// re-reading an unchanged file yields duplicates the store skips.
+type FileConnector struct {
+	name string
+	dir  string
+}
+
+func NewFileConnector(name, dir string) *FileConnector {
+	return &FileConnector{name: name, dir: dir}
+}
+
+func (c *FileConnector) Name() string {
+	return c.name
+}
+
+func (c *FileConnector) Fetch(ctx context.Context) ([]Observation, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed directory: %w", err)
+	}
+
+	var observations []Observation
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".csv" && ext != ".json" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+		records, err := readFeedFile(filepath.Join(c.dir, entry.Name()), ext)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		for i, record := range records {
+			observation, err := record.observation(info.ModTime())
+			if err != nil {
+				return nil, fmt.Errorf("%s record %d: %w", entry.Name(), i+1, err)
+			}
+			observations = append(observations, observation)
+		}
+	}
+	return observations, nil
+}
+
+// feedRecord is one row of a feed file before validation
+type feedRecord struct {
+	StoreID     int      `json:"store_id"`
+	ProductID   int      `json:"product_id"`
+	Barcode     string   `json:"barcode"`
+	Price       *float64 `json:"price"`
+	Currency    string   `json:"currency"`
+	TaxIncluded *bool    `json:"tax_included"`
+	RecordedAt  string   `json:"recorded_at"`
+}
+
+func (r feedRecord) observation(defaultTime time.Time) (Observation, error) {
+	if r.StoreID <= 0 {
+		return Observation{}, errors.New("store_id must be positive")
+	}
+	barcode := strings.TrimSpace(r.Barcode)
+	if r.ProductID <= 0 && barcode == "" {
+		return Observation{}, errors.New("product_id or barcode is required")
+	}
+	if r.Price == nil || *r.Price < 0 {
+		return Observation{}, errors.New("price must be a non-negative number")
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(r.Currency))
+	if currency == "" {
+		currency = "JPY"
+	}
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return Observation{}, fmt.Errorf("invalid currency %q", r.Currency)
+	}
+
+	recordedAt := defaultTime
+	if value := strings.TrimSpace(r.RecordedAt); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			parsed, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		}
+		if err != nil {
+			return Observation{}, fmt.Errorf("invalid recorded_at %q", r.RecordedAt)
+		}
+		recordedAt = parsed
+	}
+
+	return Observation{
+		StoreID:     r.StoreID,
+		ProductID:   r.ProductID,
+		Barcode:     barcode,
+		Price:       *r.Price,
+		Currency:    currency,
+		TaxIncluded: r.TaxIncluded == nil || *r.TaxIncluded,
+		RecordedAt:  recordedAt,
+	}, nil
+}
+
+func readFeedFile(path, ext string) ([]feedRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if ext == ".json" {
+		var records []feedRecord
+		if err := json.NewDecoder(file).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return records, nil
+	}
+	return readFeedCSV(file)
+}
+
+func readFeedCSV(r io.Reader) ([]feedRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["store_id"]; !ok {
+		return nil, errors.New("CSV header has no store_id column")
+	}
+	if _, ok := columns["price"]; !ok {
+		return nil, errors.New("CSV header has no price column")
+	}
+
+	var records []feedRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		var record feedRecord
+		if record.StoreID, err = atoiOrZero(field("store_id")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid store_id", line)
+		}
+		if record.ProductID, err = atoiOrZero(field("product_id")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid product_id", line)
+		}
+		if value := field("price"); value != "" {
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid price", line)
+			}
+			record.Price = &price
+		}
+		if value := field("tax_included"); value != "" {
+			taxIncluded, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid tax_included", line)
+			}
+			record.TaxIncluded = &taxIncluded
+		}
+		record.Barcode = field("barcode")
+		record.Currency = field("currency")
+		record.RecordedAt = field("recorded_at")
+		records = append(records, record)
+	}
+}
+
+func atoiOrZero(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,69 @@
+package connector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFeedFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestFileConnectorReadsCSVAndJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFeedFile(t, dir, "a.csv", "store_id,barcode,price,tax_included,recorded_at\n"+
+		"1,4901234567890,198,true,2024-02-01T10:00:00+09:00\n"+
+		"2,4901234567890,180,false,2024-02-01\n")
+	writeFeedFile(t, dir, "b.json", `[{"store_id": 3, "product_id": 7, "price": 98.5, "currency": "usd"}]`)
+	writeFeedFile(t, dir, "notes.txt", "ignored")
+
+	observations, err := NewFileConnector("local", dir).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(observations) != 3 {
+		t.Fatalf("expected 3 observations, got %d", len(observations))
+	}
+
+	first := observations[0]
+	if first.StoreID != 1 || first.Barcode != "4901234567890" || first.Price != 198 || first.Currency != "JPY" || !first.TaxIncluded {
+		t.Fatalf("unexpected first observation: %+v", first)
+	}
+	if first.RecordedAt.Format(time.RFC3339) != "2024-02-01T10:00:00+09:00" {
+		t.Fatalf("expected recorded_at from the file, got %s", first.RecordedAt)
+	}
+	if observations[1].TaxIncluded {
+		t.Fatalf("expected tax-exclusive second observation")
+	}
+
+	last := observations[2]
+	info, _ := os.Stat(filepath.Join(dir, "b.json"))
+	if last.ProductID != 7 || last.Currency != "USD" || !last.RecordedAt.Equal(info.ModTime()) {
+		t.Fatalf("expected JSON observation recorded at the file time, got %+v", last)
+	}
+}
+
+func TestFileConnectorRejectsMalformedFiles(t *testing.T) {
+	cases := map[string]string{
+		"missing-price.csv": "store_id,product_id\n1,2\n",
+		"bad-price.csv":     "store_id,product_id,price\n1,2,abc\n",
+		"no-product.csv":    "store_id,price\n1,100\n",
+		"bad-currency.json": `[{"store_id": 1, "product_id": 2, "price": 100, "currency": "YENS"}]`,
+		"negative.json":     `[{"store_id": 1, "product_id": 2, "price": -1}]`,
+		"not-an-array.json": `{"store_id": 1}`,
+		"bad-recorded.csv":  "store_id,product_id,price,recorded_at\n1,2,100,yesterday\n",
+	}
+	for name, content := range cases {
+		dir := t.TempDir()
+		writeFeedFile(t, dir, name, content)
+		if _, err := NewFileConnector("local", dir).Fetch(context.Background()); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
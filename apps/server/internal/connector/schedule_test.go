@@ -0,0 +1,54 @@
+package connector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// 2024-02-01 is a Thursday
+	after := time.Date(2024, 2, 1, 10, 17, 30, 0, jst)
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 2, 1, 10, 30, 0, 0, jst)},
+		{"0 6 * * *", time.Date(2024, 2, 2, 6, 0, 0, 0, jst)},
+		{"@hourly", time.Date(2024, 2, 1, 11, 0, 0, 0, jst)},
+		{"30 9-17/4 * * 1-5", time.Date(2024, 2, 1, 13, 30, 0, 0, jst)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, jst)},
+		{"0 0 31 * *", time.Date(2024, 3, 31, 0, 0, 0, 0, jst)},
+		// Both day fields restricted: the 15th or any Monday
+		{"0 8 15 * 1", time.Date(2024, 2, 5, 8, 0, 0, 0, jst)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, jst)},
+	}
+	for _, tc := range cases {
+		schedule, err := ParseSchedule(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.spec, err)
+		}
+		if got := schedule.Next(after); !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %s, got %s", tc.spec, tc.want, got)
+		}
+	}
+}
+
+func TestScheduleNextNeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected no run for February 31st, got %s", next)
+	}
+}
+
+func TestParseScheduleRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/jobs"
+)
+
+// RunStore records connector runs and saves the prices they observe
+type RunStore interface {
+	StartRun(connector, trigger string) (*domain.ConnectorRun, error)
+	FinishRun(run domain.ConnectorRun) error
+	// Ingest saves observations as prices from source and returns how many
+	// were inserted, including when it fails part way
+	Ingest(source string, observations []Observation) (int, error)
+}
+
+type job struct {
+	connector Connector
+	spec      string
+	schedule  Schedule
+	running   sync.Mutex
+}
+
+// Scheduler runs connectors on their cron schedules, evaluated in its
+// location. A connector never runs twice at the same time.
+type Scheduler struct {
+	store    RunStore
+	location *time.Location
+	logger   *slog.Logger
+	jobs     []*job
+	byName   map[string]*job
+}
+
+func NewScheduler(store RunStore, location *time.Location, logger *slog.Logger) *Scheduler {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Scheduler{store: store, location: location, logger: logger, byName: make(map[string]*job)}
+}
+
+// Add registers connector to run on the cron schedule spec
+func (s *Scheduler) Add(connector Connector, spec string) error {
+	name := connector.Name()
+	if name == "" {
+		return fmt.Errorf("connector name is required")
+	}
+	if _, exists := s.byName[name]; exists {
+		return fmt.Errorf("connector %q is already registered", name)
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	j := &job{connector: connector, spec: spec, schedule: schedule}
+	s.jobs = append(s.jobs, j)
+	s.byName[name] = j
+	return nil
+}
+
+// Jobs lists the registered connectors with their next scheduled run
+func (s *Scheduler) Jobs() []domain.ConnectorJob {
+	now := time.Now().In(s.location)
+	jobs := make([]domain.ConnectorJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		info := domain.ConnectorJob{Name: j.connector.Name(), Schedule: j.spec}
+		if next := j.schedule.Next(now); !next.IsZero() {
+			info.NextRunAt = &next
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs
+}
+
+// Start runs every registered connector on its schedule until ctx is done
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		go s.loop(ctx, j)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		next := j.schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		run, err := s.run(ctx, j, domain.RunTriggerSchedule)
+		switch {
+		case err != nil:
+			s.logger.Error("connector run not started", "connector", j.connector.Name(), "error", err)
+		case run.Status == domain.ConnectorRunFailed:
+			s.logger.Error("connector run failed", "connector", run.Connector, "run_id", run.ID, "error", run.Error)
+		default:
+			s.logger.Info("connector run finished", "connector", run.Connector, "run_id", run.ID,
+				"rows_read", run.RowsRead, "rows_inserted", run.RowsInserted, "rows_skipped", run.RowsSkipped)
+		}
+	}
+}
+
+// RunNow runs the named connector immediately and returns the finished
+// run, which records whether the connector succeeded
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*domain.ConnectorRun, error) {
+	j, ok := s.byName[name]
+	if !ok {
+		return nil, domain.ErrConnectorNotFound
+	}
+	return s.run(ctx, j, domain.RunTriggerManual)
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job, trigger string) (*domain.ConnectorRun, error) {
+	if !j.running.TryLock() {
+		return nil, domain.ErrConnectorRunning
+	}
+	defer j.running.Unlock()
+
+	name := j.connector.Name()
+	run, err := s.store.StartRun(name, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	observations, err := j.connector.Fetch(ctx)
+	if err == nil {
+		run.RowsRead = len(observations)
+		run.RowsInserted, err = s.store.Ingest(name, observations)
+	}
+	if err != nil {
+		run.Status = domain.ConnectorRunFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = domain.ConnectorRunSucceeded
+		run.RowsSkipped = run.RowsRead - run.RowsInserted
+	}
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+
+	if err := s.store.FinishRun(*run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// RunJobType is the background job that runs a connector outside its
+// schedule; its payload is a RunJob
+const RunJobType = "connector.run"
+
+type RunJob struct {
+	Connector string `json:"connector"`
+}
+
+// HandleRunJob runs the connector named by job. A busy connector or a
+// failed run returns an error so the job is retried; an unknown connector
+// fails the job for good.
+func (s *Scheduler) HandleRunJob(ctx context.Context, job RunJob) error {
+	run, err := s.RunNow(ctx, job.Connector)
+	if errors.Is(err, domain.ErrConnectorNotFound) {
+		return jobs.Permanent(fmt.Errorf("%w: %q", err, job.Connector))
+	}
+	if err != nil {
+		return err
+	}
+	if run.Status == domain.ConnectorRunFailed {
+		return fmt.Errorf("connector run %d failed: %s", run.ID, run.Error)
+	}
+	return nil
+}
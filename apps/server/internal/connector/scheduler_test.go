@@ -0,0 +1,116 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type runStoreStub struct {
+	finished []domain.ConnectorRun
+	inserted int
+}
+
+func (s *runStoreStub) StartRun(connector, trigger string) (*domain.ConnectorRun, error) {
+	return &domain.ConnectorRun{ID: len(s.finished) + 1, Connector: connector, Trigger: trigger, Status: domain.ConnectorRunRunning}, nil
+}
+
+func (s *runStoreStub) FinishRun(run domain.ConnectorRun) error {
+	s.finished = append(s.finished, run)
+	return nil
+}
+
+func (s *runStoreStub) Ingest(source string, observations []Observation) (int, error) {
+	return s.inserted, nil
+}
+
+type connectorStub struct {
+	name         string
+	observations []Observation
+	err          error
+	started      chan struct{}
+	block        chan struct{}
+}
+
+func (c *connectorStub) Name() string {
+	return c.name
+}
+
+func (c *connectorStub) Fetch(ctx context.Context) ([]Observation, error) {
+	if c.block != nil {
+		close(c.started)
+		<-c.block
+	}
+	return c.observations, c.err
+}
+
+func newTestScheduler(store RunStore) *Scheduler {
+	return NewScheduler(store, time.UTC, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestSchedulerRunNowRecordsCounts(t *testing.T) {
+	store := &runStoreStub{inserted: 2}
+	scheduler := newTestScheduler(store)
+	feed := &connectorStub{name: "feed", observations: make([]Observation, 3)}
+	if err := scheduler.Add(feed, "@daily"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run, err := scheduler.RunNow(context.Background(), "feed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != domain.ConnectorRunSucceeded || run.Trigger != domain.RunTriggerManual {
+		t.Fatalf("expected a succeeded manual run, got %+v", run)
+	}
+	if run.RowsRead != 3 || run.RowsInserted != 2 || run.RowsSkipped != 1 || run.FinishedAt == nil {
+		t.Fatalf("unexpected counts: %+v", run)
+	}
+	if len(store.finished) != 1 {
+		t.Fatalf("expected the run to be recorded, got %d", len(store.finished))
+	}
+}
+
+func TestSchedulerRecordsFailedRuns(t *testing.T) {
+	store := &runStoreStub{}
+	scheduler := newTestScheduler(store)
+	_ = scheduler.Add(&connectorStub{name: "broken", err: errors.New("feed unavailable")}, "@hourly")
+
+	run, err := scheduler.RunNow(context.Background(), "broken")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != domain.ConnectorRunFailed || run.Error != "feed unavailable" {
+		t.Fatalf("expected a failed run, got %+v", run)
+	}
+}
+
+func TestSchedulerRejectsUnknownAndOverlappingRuns(t *testing.T) {
+	scheduler := newTestScheduler(&runStoreStub{})
+	feed := &connectorStub{name: "slow", started: make(chan struct{}), block: make(chan struct{})}
+	_ = scheduler.Add(feed, "@hourly")
+
+	if err := scheduler.Add(&connectorStub{name: "slow"}, "@daily"); err == nil {
+		t.Fatalf("expected error for a duplicate connector name")
+	}
+	if _, err := scheduler.RunNow(context.Background(), "missing"); !errors.Is(err, domain.ErrConnectorNotFound) {
+		t.Fatalf("expected ErrConnectorNotFound, got %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = scheduler.RunNow(context.Background(), "slow")
+		close(done)
+	}()
+	<-feed.started
+	if _, err := scheduler.RunNow(context.Background(), "slow"); !errors.Is(err, domain.ErrConnectorRunning) {
+		t.Fatalf("expected ErrConnectorRunning while a run is in progress, got %v", err)
+	}
+	close(feed.block)
+	<-done
+}
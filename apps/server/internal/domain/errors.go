@@ -0,0 +1,45 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrMixedCurrencies is returned when prices in different currencies
+	// would be aggregated without a target currency to convert into
+	ErrMixedCurrencies = errors.New("prices are recorded in multiple currencies")
+	// ErrExchangeRateNotFound is returned when no rate exists to convert
+	// between two currencies on or before the required date
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	// ErrTravelTimeUnavailable is returned when travel-time search is
+	// requested while road-network routing is disabled
+	ErrTravelTimeUnavailable = errors.New("travel-time search is not enabled on this server")
+	// ErrConnectorNotFound is returned when no connector is configured
+	// under the requested name
+	ErrConnectorNotFound = errors.New("connector not found")
+	// ErrConnectorRunning is returned when a connector is asked to run
+	// while its previous run has not finished
+	ErrConnectorRunning = errors.New("connector is already running")
+	// ErrInvalidExchangeRate is returned when an exchange rate to save
+	// fails validation
+	ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+	// ErrInvalidJob is returned when a job to enqueue fails validation
+	ErrInvalidJob = errors.New("invalid job")
+	// ErrJobNotRetryable is returned when retrying a job that has not failed
+	ErrJobNotRetryable = errors.New("only failed jobs can be retried")
+	// ErrInvalidPriceIndex is returned when a price index is requested
+	// with an unusable period, range or base period
+	ErrInvalidPriceIndex = errors.New("invalid price index request")
+	// ErrInvalidPromotion is returned when a promotion fails validation
+	ErrInvalidPromotion = errors.New("invalid promotion")
+	// ErrInvalidSearch is returned when a search query has no searchable
+	// text or contradictory filters
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrInvalidSubmission is returned when a price submission fails
+	// validation
+	ErrInvalidSubmission = errors.New("invalid price submission")
+	// ErrInvalidContributorRole is returned when a contributor role change
+	// fails validation
+	ErrInvalidContributorRole = errors.New("invalid contributor role")
+	// ErrInsufficientHistory is returned when a price series is too short
+	// to forecast
+	ErrInsufficientHistory = errors.New("not enough price history to forecast")
+)
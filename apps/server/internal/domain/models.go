@@ -1,28 +1,150 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Store represents a retail store with geographic location
 type Store struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Address   string    `json:"address"`
-	Phone     string    `json:"phone"`
-	Latitude  float64   `json:"latitude"`
-	Longitude float64   `json:"longitude"`
-	Distance  *float64  `json:"distance,omitempty"` // Distance in meters (only for nearby queries)
-	MinPrice  *float64  `json:"min_price,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                int              `json:"id"`
+	Name              string           `json:"name"`
+	Address           string           `json:"address"`
+	Phone             string           `json:"phone"`
+	Latitude          float64          `json:"latitude"`
+	Longitude         float64          `json:"longitude"`
+	Timezone          string           `json:"timezone"`
+	Chain             *Chain           `json:"chain,omitempty"`
+	OpeningHours      []OpeningPeriod  `json:"opening_hours,omitempty"`
+	HoursExceptions   []HoursException `json:"hours_exceptions,omitempty"`
+	IsOpen            *bool            `json:"is_open,omitempty"`     // Only when an open_now/open_at filter is applied
+	Distance          *float64         `json:"distance,omitempty"`    // Distance in meters (only for nearby queries)
+	TravelTime        *float64         `json:"travel_time,omitempty"` // Travel time in seconds (only for travel-time nearby queries)
+	MinPrice          *float64         `json:"min_price,omitempty"`
+	MinUnitPrice      *float64         `json:"min_unit_price,omitempty"`       // Cheapest matching price per MinUnitPriceBasis
+	MinUnitPriceBasis string           `json:"min_unit_price_basis,omitempty"` // 100g, 1l or piece; unset when matching products mix bases
+	Competitiveness   *float64         `json:"competitiveness,omitempty"`      // Price level against nearby stores; 100 is the local median, lower is cheaper
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// StoreCluster aggregates nearby stores into one map marker
+type StoreCluster struct {
+	Latitude  float64  `json:"latitude"` // Centroid of the clustered stores
+	Longitude float64  `json:"longitude"`
+	Count     int      `json:"count"`
+	MinPrice  *float64 `json:"min_price,omitempty"` // Cheapest matching price across the cluster
+	StoreID   *int     `json:"store_id,omitempty"`  // Only when the cluster holds a single store
+}
+
+// StoreRanking places a store by its competitiveness score among the
+// scored stores of an area; Rank 1 is the cheapest
+type StoreRanking struct {
+	Rank             int       `json:"rank"`
+	Store            Store     `json:"store"`
+	Score            float64   `json:"score"`
+	ProductsCompared int       `json:"products_compared"`
+	ComputedAt       time.Time `json:"computed_at"`
+}
+
+// GeocodeResult is a resolved address or postal code with its location
+type GeocodeResult struct {
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	Label      string   `json:"label"` // Full address for display
+	PostalCode string   `json:"postal_code,omitempty"`
+	Prefecture string   `json:"prefecture,omitempty"`
+	City       string   `json:"city,omitempty"`
+	Town       string   `json:"town,omitempty"`
+	Distance   *float64 `json:"distance,omitempty"` // Meters from the query point (only for reverse geocoding)
+	Source     string   `json:"source"`             // Provider that produced the result
+}
+
+// Chain is a supermarket or convenience store brand operating many stores
+type Chain struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	LogoURL    string `json:"logo_url,omitempty"`
+	WebsiteURL string `json:"website_url,omitempty"`
+	StoreCount *int   `json:"store_count,omitempty"`
+}
+
+// Area is a saved search polygon such as a ward or delivery zone
+type Area struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Geometry  json.RawMessage `json:"geometry"` // GeoJSON MultiPolygon
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// OpeningPeriod is a weekly opening window in the store's local time.
+// Weekday 0 is Sunday; Closes at or before Opens means the period runs
+// past midnight.
+type OpeningPeriod struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`  // HH:MM
+	Closes  string `json:"closes"` // HH:MM
+}
+
+// HoursException overrides the weekly schedule on a local date
+type HoursException struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Closed bool   `json:"closed"`
+	Opens  string `json:"opens,omitempty"`
+	Closes string `json:"closes,omitempty"`
+	Note   string `json:"note,omitempty"`
 }
 
 // Product represents a product item
 type Product struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Category  string    `json:"category"`
-	Barcode   string    `json:"barcode"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category"`
+	Barcode     string    `json:"barcode"`
+	PackageSize *float64  `json:"package_size,omitempty"`
+	PackageUnit string    `json:"package_unit,omitempty"` // g, kg, ml, l or piece
+	TaxClass    string    `json:"tax_class"`              // standard or reduced
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Category is a node of the product category tree. Name is in the
+// requested language when the category has a translation for it; Names
+// holds every translation by language tag.
+type Category struct {
+	ID       int               `json:"id"`
+	ParentID *int              `json:"parent_id"`
+	Slug     string            `json:"slug"`
+	Name     string            `json:"name"`
+	Names    map[string]string `json:"names"`
+	Children []Category        `json:"children,omitempty"`
+}
+
+// TextSpan marks a matched range within a string, in rune offsets
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ProductSearchResult is a product matched by a search, ranked by relevance
+type ProductSearchResult struct {
+	Product
+	Score      float64    `json:"score"`
+	Highlights []TextSpan `json:"highlights,omitempty"`
+}
+
+// ProductSearchPage holds ranked results and "did you mean" suggestions
+type ProductSearchPage struct {
+	Results     []ProductSearchResult `json:"results"`
+	Suggestions []string              `json:"suggestions,omitempty"`
+}
+
+// Suggestion is a typeahead entry: a product, category or store name
+type Suggestion struct {
+	Type  string  `json:"type"`
+	ID    *int    `json:"id,omitempty"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
 }
 
 // Price represents a price record for a product at a store
@@ -35,11 +157,346 @@ type Price struct {
 	RecordedAt time.Time `json:"recorded_at"`
 	CreatedAt  time.Time `json:"created_at"`
 
+	// TaxIncluded tells whether the amounts in this response include
+	// consumption tax at TaxRate. PostedPrice is the amount as the source
+	// showed it, tax-inclusive (税込) when PostedTaxIncluded.
+	TaxIncluded       bool    `json:"tax_included"`
+	TaxRate           float64 `json:"tax_rate"`
+	PostedPrice       float64 `json:"posted_price"`
+	PostedTaxIncluded bool    `json:"posted_tax_included"`
+
+	// Per-unit price after the best active promotion for the requested
+	// quantity, in the same currency as Price
+	EffectivePrice *float64    `json:"effective_price,omitempty"`
+	Promotions     []Promotion `json:"promotions,omitempty"`
+
+	// EffectivePrice normalized by package size: per 100g, per litre (1l)
+	// or per piece
+	UnitPrice      *float64 `json:"unit_price,omitempty"`
+	UnitPriceBasis string   `json:"unit_price_basis,omitempty"`
+
+	// As recorded, when Price was converted into a requested currency
+	OriginalPrice    *float64 `json:"original_price,omitempty"`
+	OriginalCurrency string   `json:"original_currency,omitempty"`
+
+	// Provenance: one of the PriceSource constants, and the contributor
+	// whose approved submission became this price. Source names the feed,
+	// user ("user:<external_id>") or import batch ("import:<batch>").
+	SourceType    string `json:"source_type"`
+	ContributorID *int   `json:"contributor_id,omitempty"`
+	Source        string `json:"source,omitempty"`
+
+	// Confidence in the price now, between 0 and 1: the source's initial
+	// confidence, halving every 14 days since RecordedAt
+	Confidence float64 `json:"confidence"`
+
 	// Joined data
 	Store   *Store   `json:"store,omitempty"`
 	Product *Product `json:"product,omitempty"`
 }
 
+// Price sources
+const (
+	PriceSourceScraper      = "scraper"
+	PriceSourceStoreManager = "store_manager"
+	PriceSourceCrowd        = "crowd"
+)
+
+// Price review states. Only active prices appear in listings and aggregates.
+const (
+	PriceStatusActive      = "active"
+	PriceStatusQuarantined = "quarantined"
+	PriceStatusRejected    = "rejected"
+)
+
+// PriceAnomaly is a price held back by anomaly screening. AnomalyScore is
+// the modified z-score against BaselinePrice, the product's median price
+// across stores when the price was recorded.
+type PriceAnomaly struct {
+	Price
+	Status        string     `json:"status"`
+	AnomalyScore  *float64   `json:"anomaly_score,omitempty"`
+	BaselinePrice *float64   `json:"baseline_price,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+}
+
+// Promotion discounts a product at a store between ValidFrom and ValidTo
+// (open-ended when nil). SalePrice replaces the regular unit price;
+// BundleQuantity units cost BundlePrice together ("2 for 300"). Promotion
+// prices are recorded tax-inclusive.
+type Promotion struct {
+	ID             int        `json:"id"`
+	StoreID        int        `json:"store_id"`
+	ProductID      int        `json:"product_id"`
+	SalePrice      *float64   `json:"sale_price,omitempty"`
+	BundleQuantity *int       `json:"bundle_quantity,omitempty"`
+	BundlePrice    *float64   `json:"bundle_price,omitempty"`
+	Currency       string     `json:"currency"`
+	MemberOnly     bool       `json:"member_only"`
+	TaxIncluded    bool       `json:"tax_included"`
+	Description    string     `json:"description,omitempty"`
+	ValidFrom      time.Time  `json:"valid_from"`
+	ValidTo        *time.Time `json:"valid_to,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Contributor roles. A store manager reports prices for StoreID.
+const (
+	ContributorRoleShopper      = "shopper"
+	ContributorRoleStoreManager = "store_manager"
+)
+
+// Contributor is a mobile app user who submits prices, identified by the
+// app's ExternalID. Reputation is the smoothed share of their moderated
+// submissions that were approved, between 0 and 1.
+type Contributor struct {
+	ID            int       `json:"id"`
+	ExternalID    string    `json:"external_id"`
+	DisplayName   string    `json:"display_name,omitempty"`
+	Role          string    `json:"role"`
+	StoreID       *int      `json:"store_id,omitempty"`
+	ApprovedCount int       `json:"approved_count"`
+	RejectedCount int       `json:"rejected_count"`
+	Reputation    float64   `json:"reputation"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Price submission states
+const (
+	SubmissionStatusPending  = "pending"
+	SubmissionStatusApproved = "approved"
+	SubmissionStatusRejected = "rejected"
+)
+
+// PriceSubmission is a shelf price reported by a contributor. Price is as
+// posted, tax-inclusive unless TaxIncluded is false. PriceID is the price
+// created when the submission was approved.
+type PriceSubmission struct {
+	ID            int        `json:"id"`
+	ContributorID int        `json:"contributor_id"`
+	StoreID       int        `json:"store_id"`
+	ProductID     int        `json:"product_id"`
+	Price         float64    `json:"price"`
+	Currency      string     `json:"currency"`
+	TaxIncluded   bool       `json:"tax_included"`
+	PhotoURL      string     `json:"photo_url,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	ObservedAt    time.Time  `json:"observed_at"`
+	Status        string     `json:"status"`
+	AutoApproved  bool       `json:"auto_approved"`
+	ReviewNote    string     `json:"review_note,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+	PriceID       *int       `json:"price_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	// Joined data
+	Contributor *Contributor `json:"contributor,omitempty"`
+}
+
+// SubmissionReview is a moderation decision on a pending submission.
+// SourceType is recorded on the price created by an approval.
+type SubmissionReview struct {
+	Status     string
+	SourceType string
+	Note       string
+	Auto       bool
+	// ReviewedBy names the moderator; empty for automatic approvals
+	ReviewedBy string
+}
+
+// Connector run states and what started a run
+const (
+	ConnectorRunRunning   = "running"
+	ConnectorRunSucceeded = "succeeded"
+	ConnectorRunFailed    = "failed"
+
+	RunTriggerSchedule = "schedule"
+	RunTriggerManual   = "manual"
+)
+
+// ConnectorRun is one execution of a price feed connector. RowsSkipped
+// counts observations for unknown stores or products and duplicates of
+// prices already recorded.
+type ConnectorRun struct {
+	ID           int        `json:"id"`
+	Connector    string     `json:"connector"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	RowsRead     int        `json:"rows_read"`
+	RowsInserted int        `json:"rows_inserted"`
+	RowsSkipped  int        `json:"rows_skipped"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// ConnectorJob is a configured connector and its cron schedule
+type ConnectorJob struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+}
+
+// Background job states. A failed job has used up its attempts or failed
+// permanently; it runs again only when retried.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Job is a unit of background work. Type selects the handler that decodes
+// Payload; Attempts counts the times a worker has claimed it.
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedUntil *time.Time      `json:"locked_until,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+// JobCount is the number of jobs of one type in one status
+type JobCount struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// JobQueueSummary lists the job types this instance can run and how many
+// jobs of each type are in each status
+type JobQueueSummary struct {
+	Types  []string   `json:"types"`
+	Counts []JobCount `json:"counts"`
+}
+
+// Price index periods
+const (
+	PriceIndexPeriodMonth = "month"
+	PriceIndexPeriodWeek  = "week" // Starting on Monday
+)
+
+// PeriodPrice is the average price of one product in one currency over a
+// period (a day, or a price index period), and how many prices it averages
+type PeriodPrice struct {
+	PeriodStart time.Time
+	StoreID     int
+	ProductID   int
+	Currency    string
+	AvgPrice    float64
+	Count       int
+}
+
+// PriceIndexPoint is a price index value for one period. Index is nil for
+// periods without prices and before the first priced period.
+type PriceIndexPoint struct {
+	PeriodStart  time.Time `json:"period_start"`
+	Index        *float64  `json:"index"`
+	Change       *float64  `json:"change,omitempty"` // Percent change from the previous priced period
+	Products     int       `json:"products"`         // Products priced in both this and the previous priced period
+	Observations int       `json:"observations"`     // Prices recorded in the period
+}
+
+// PriceIndex is a chained price index for a category, 100 in BasePeriod
+type PriceIndex struct {
+	Category    string            `json:"category"`
+	AreaID      int               `json:"area_id,omitempty"`
+	Period      string            `json:"period"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	BasePeriod  *time.Time        `json:"base_period,omitempty"`
+	TaxIncluded bool              `json:"tax_included"`
+	Points      []PriceIndexPoint `json:"points"`
+}
+
+// PriceForecastDay is the predicted price on one day with an 80% range
+type PriceForecastDay struct {
+	Date  time.Time `json:"date"`
+	Price float64   `json:"price"`
+	Low   float64   `json:"low"`
+	High  float64   `json:"high"`
+}
+
+// ForecastBacktest scores forecasts of past weeks made from the history
+// before them. MAPE is a percentage; Coverage is the share of prices that
+// fell inside the predicted range. NaiveMAE is the error of assuming the
+// price stays unchanged.
+type ForecastBacktest struct {
+	Folds    int     `json:"folds"`
+	MAE      float64 `json:"mae"`
+	MAPE     float64 `json:"mape"`
+	NaiveMAE float64 `json:"naive_mae"`
+	Coverage float64 `json:"coverage"`
+}
+
+// PriceForecast predicts a product's price at a store over the next days.
+// WeekdayEffects holds the price difference of each weekday from the
+// underlying level, Sunday first; LikelySaleDay is set when one forecast
+// day is clearly cheaper than the rest.
+type PriceForecast struct {
+	StoreID        int                `json:"store_id"`
+	ProductID      int                `json:"product_id"`
+	Currency       string             `json:"currency"`
+	TaxIncluded    bool               `json:"tax_included"`
+	HistoryDays    int                `json:"history_days"`
+	Observations   int                `json:"observations"` // Days with recorded prices
+	LastPrice      float64            `json:"last_price"`
+	LastObserved   time.Time          `json:"last_observed"`
+	Days           []PriceForecastDay `json:"days"`
+	LikelySaleDay  *time.Time         `json:"likely_sale_day,omitempty"`
+	WeekdayEffects []float64          `json:"weekday_effects"`
+	Backtest       ForecastBacktest   `json:"backtest"`
+}
+
+// PricePartition is one month of the prices table, holding prices recorded
+// from RangeStart up to but excluding RangeEnd
+type PricePartition struct {
+	Name       string    `json:"name"`
+	RangeStart time.Time `json:"range_start"`
+	RangeEnd   time.Time `json:"range_end"`
+}
+
+// PriceArchive records a price partition written to a compressed file and
+// dropped by the retention policy
+type PriceArchive struct {
+	ID            int       `json:"id"`
+	PartitionName string    `json:"partition_name"`
+	RangeStart    time.Time `json:"range_start"`
+	RangeEnd      time.Time `json:"range_end"`
+	RowCount      int64     `json:"row_count"`
+	Path          string    `json:"path"`
+	ArchivedAt    time.Time `json:"archived_at"`
+}
+
+// ExchangeRate states that one unit of BaseCurrency is worth Rate units of
+// QuoteCurrency from RateDate until a newer rate is published
+type ExchangeRate struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	RateDate      time.Time `json:"rate_date"`
+	Rate          float64   `json:"rate"`
+	Source        string    `json:"source,omitempty"`
+}
+
+// Conversion is the result of converting an amount between currencies
+type Conversion struct {
+	Amount          float64   `json:"amount"`
+	FromCurrency    string    `json:"from_currency"`
+	ToCurrency      string    `json:"to_currency"`
+	Date            time.Time `json:"date"`
+	ConvertedAmount float64   `json:"converted_amount"`
+}
+
 // PriceComparison represents a product with prices from multiple stores
 type PriceComparison struct {
 	Product      Product `json:"product"`
@@ -50,10 +507,11 @@ type PriceComparison struct {
 }
 
 type PriceSummary struct {
-	MinPrice *float64 `json:"min_price,omitempty"`
-	MaxPrice *float64 `json:"max_price,omitempty"`
-	AvgPrice *float64 `json:"avg_price,omitempty"`
-	Currency string   `json:"currency,omitempty"`
+	MinPrice    *float64 `json:"min_price,omitempty"`
+	MaxPrice    *float64 `json:"max_price,omitempty"`
+	AvgPrice    *float64 `json:"avg_price,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	TaxIncluded bool     `json:"tax_included"`
 }
 
 type DailyPriceStats struct {
@@ -72,3 +530,14 @@ type StorePriceStats struct {
 	Summary  PriceSummary      `json:"summary"`
 	Daily    []DailyPriceStats `json:"daily"`
 }
+
+// ChainPriceStats aggregates prices across every branch of a chain
+type ChainPriceStats struct {
+	ChainID    int               `json:"chain_id"`
+	Category   string            `json:"category,omitempty"`
+	Query      string            `json:"query,omitempty"`
+	Days       int               `json:"days"`
+	StoreCount int               `json:"store_count"`
+	Summary    PriceSummary      `json:"summary"`
+	Daily      []DailyPriceStats `json:"daily"`
+}
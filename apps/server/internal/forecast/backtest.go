@@ -0,0 +1,59 @@
+package forecast
+
+import "math"
+
+// Metrics summarize forecasts of held-out weeks against what happened.
+// MAPE is a percentage; Coverage is the share of actual values inside the
+// 80% interval. NaiveMAE is the error of repeating the last known value,
+// the baseline a model has to beat.
+type Metrics struct {
+	Folds    int
+	MAE      float64
+	MAPE     float64
+	NaiveMAE float64
+	Coverage float64
+}
+
+// Backtest refits the model on series up to each of the last folds origins,
+// horizon days apart, and scores the next horizon days. Origins leaving
+// fewer than MinObservations days to fit are skipped.
+func Backtest(series []float64, horizon, folds int) Metrics {
+	var metrics Metrics
+	var points, percentPoints, covered int
+	var absError, percentError, naiveError float64
+	for k := folds; k >= 1; k-- {
+		origin := len(series) - k*horizon
+		if origin < MinObservations {
+			continue
+		}
+		model, err := Fit(series[:origin])
+		if err != nil {
+			continue
+		}
+		metrics.Folds++
+		last := series[origin-1]
+		for h, prediction := range model.Forecast(horizon) {
+			actual := series[origin+h]
+			absError += math.Abs(actual - prediction.Value)
+			naiveError += math.Abs(actual - last)
+			if actual != 0 {
+				percentError += math.Abs((actual - prediction.Value) / actual)
+				percentPoints++
+			}
+			if actual >= prediction.Low && actual <= prediction.High {
+				covered++
+			}
+			points++
+		}
+	}
+	if points == 0 {
+		return metrics
+	}
+	metrics.MAE = absError / float64(points)
+	metrics.NaiveMAE = naiveError / float64(points)
+	metrics.Coverage = float64(covered) / float64(points)
+	if percentPoints > 0 {
+		metrics.MAPE = percentError / float64(percentPoints) * 100
+	}
+	return metrics
+}
@@ -0,0 +1,138 @@
+// Package forecast fits damped-trend Holt-Winters models with a weekly
+// season to daily price series and backtests them.
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+// Season is the length of the seasonal cycle in days: weekly sales repeat
+// on the same weekday
+const Season = 7
+
+// MinObservations is the shortest series a model can be fitted to; the
+// initial level, trend and weekday effects need two full weeks
+const MinObservations = 2 * Season
+
+// damping shrinks the trend as the horizon grows, so a few days of price
+// movement are not extrapolated indefinitely
+const damping = 0.9
+
+// intervalZ gives an 80% prediction interval under normal errors
+const intervalZ = 1.2816
+
+// ErrTooShort is returned for series shorter than MinObservations
+var ErrTooShort = errors.New("series is too short to forecast")
+
+// Smoothing parameters tried when fitting, for the level (alpha), trend
+// (beta) and weekday effects (gamma)
+var (
+	alphas = []float64{0.1, 0.2, 0.3, 0.5, 0.7, 0.9}
+	betas  = []float64{0, 0.05, 0.1}
+	gammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// Model is an additive Holt-Winters model fitted to a series. Season[i] is
+// the effect of the weekday of the i-th day of the series, modulo Season.
+type Model struct {
+	Alpha  float64
+	Beta   float64
+	Gamma  float64
+	Level  float64
+	Trend  float64
+	Season [Season]float64
+	// Sigma is the standard deviation of the one-step errors after the
+	// first week
+	Sigma float64
+	// n is the length of the fitted series, to continue the weekday cycle
+	n int
+}
+
+// Prediction is the forecast for one day with an 80% interval
+type Prediction struct {
+	Value float64
+	Low   float64
+	High  float64
+}
+
+// Fit picks the smoothing parameters with the smallest one-step squared
+// error over series, a value per consecutive day
+func Fit(series []float64) (*Model, error) {
+	if len(series) < MinObservations {
+		return nil, ErrTooShort
+	}
+	var best *Model
+	bestSSE := math.Inf(1)
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			for _, gamma := range gammas {
+				model, sse := run(series, alpha, beta, gamma)
+				if sse < bestSSE {
+					best, bestSSE = model, sse
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+func run(series []float64, alpha, beta, gamma float64) (*Model, float64) {
+	m := &Model{Alpha: alpha, Beta: beta, Gamma: gamma, n: len(series)}
+
+	first := mean(series[:Season])
+	second := mean(series[Season : 2*Season])
+	m.Level = first
+	m.Trend = (second - first) / Season
+	for i := 0; i < Season; i++ {
+		m.Season[i] = (series[i] - first + series[i+Season] - second) / 2
+	}
+
+	var sse float64
+	var count int
+	for t, y := range series {
+		i := t % Season
+		predicted := m.Level + damping*m.Trend + m.Season[i]
+		if t >= Season {
+			sse += (y - predicted) * (y - predicted)
+			count++
+		}
+		level := alpha*(y-m.Season[i]) + (1-alpha)*(m.Level+damping*m.Trend)
+		m.Trend = beta*(level-m.Level) + (1-beta)*damping*m.Trend
+		m.Season[i] = gamma*(y-level) + (1-gamma)*m.Season[i]
+		m.Level = level
+	}
+	m.Sigma = math.Sqrt(sse / float64(count))
+	return m, sse
+}
+
+// Forecast predicts the horizon days following the fitted series. Low is
+// never below zero.
+func (m *Model) Forecast(horizon int) []Prediction {
+	predictions := make([]Prediction, horizon)
+	trend := 0.0
+	factor := damping
+	for h := 1; h <= horizon; h++ {
+		trend += factor * m.Trend
+		factor *= damping
+		value := m.Level + trend + m.Season[(m.n+h-1)%Season]
+		// Errors accumulate through the level as the horizon grows
+		spread := intervalZ * m.Sigma * math.Sqrt(1+float64(h-1)*m.Alpha*m.Alpha)
+		predictions[h-1] = Prediction{Value: value, Low: math.Max(value-spread, 0), High: value + spread}
+	}
+	return predictions
+}
+
+// Effect is the weekday effect on day t of the series; t may lie past its
+// end
+func (m *Model) Effect(t int) float64 {
+	return m.Season[t%Season]
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,85 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+)
+
+// weeklySale is 200 on most days and 150 on every seventh day from offset
+func weeklySale(days, offset int) []float64 {
+	series := make([]float64, days)
+	for t := range series {
+		series[t] = 200
+		if t%Season == offset {
+			series[t] = 150
+		}
+	}
+	return series
+}
+
+func TestFitLearnsWeeklySale(t *testing.T) {
+	model, err := Fit(weeklySale(56, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	predictions := model.Forecast(Season)
+	cheapest := 0
+	for h, prediction := range predictions {
+		if prediction.Value < predictions[cheapest].Value {
+			cheapest = h
+		}
+	}
+	// Day 56 + cheapest continues the cycle; the sale falls on day 59
+	if 56+cheapest != 59 {
+		t.Fatalf("expected the sale on day 59, got day %d (%+v)", 56+cheapest, predictions)
+	}
+	if math.Abs(predictions[cheapest].Value-150) > 5 || math.Abs(predictions[0].Value-200) > 5 {
+		t.Fatalf("expected about 150 on sale and 200 otherwise, got %+v", predictions)
+	}
+	if model.Effect(59) > -40 {
+		t.Fatalf("expected a strong negative sale-day effect, got %v", model.Effect(59))
+	}
+}
+
+func TestForecastIntervalWidensAndStaysPositive(t *testing.T) {
+	series := weeklySale(42, 0)
+	for t := range series {
+		series[t] += float64(t%3) * 4
+	}
+	model, err := Fit(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	predictions := model.Forecast(Season)
+	first := predictions[0].High - predictions[0].Low
+	last := predictions[Season-1].High - predictions[Season-1].Low
+	if first <= 0 || last < first {
+		t.Fatalf("expected a widening interval, got %+v", predictions)
+	}
+	for _, prediction := range predictions {
+		if prediction.Low < 0 || prediction.Low > prediction.Value || prediction.High < prediction.Value {
+			t.Fatalf("expected value within a non-negative interval, got %+v", prediction)
+		}
+	}
+}
+
+func TestFitRejectsShortSeries(t *testing.T) {
+	if _, err := Fit(make([]float64, MinObservations-1)); err != ErrTooShort {
+		t.Fatalf("expected ErrTooShort, got %v", err)
+	}
+}
+
+func TestBacktestBeatsNaiveOnSeasonalSeries(t *testing.T) {
+	metrics := Backtest(weeklySale(70, 5), Season, 4)
+	if metrics.Folds != 4 {
+		t.Fatalf("expected four folds, got %+v", metrics)
+	}
+	if metrics.MAE >= metrics.NaiveMAE {
+		t.Fatalf("expected the model to beat the naive forecast, got %+v", metrics)
+	}
+
+	if short := Backtest(weeklySale(20, 0), Season, 4); short.Folds != 0 {
+		t.Fatalf("expected no folds without enough history, got %+v", short)
+	}
+}
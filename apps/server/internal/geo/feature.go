@@ -0,0 +1,37 @@
+package geo
+
+// FeatureCollection is a GeoJSON FeatureCollection of point features
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature with a Point geometry
+type Feature struct {
+	Type       string      `json:"type"`
+	ID         int         `json:"id"`
+	Geometry   Point       `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+// Point is a GeoJSON Point; coordinates are [longitude, latitude]
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+func NewPointFeature(id int, lon, lat float64, properties interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   Point{Type: "Point", Coordinates: [2]float64{lon, lat}},
+		Properties: properties,
+	}
+}
@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxAreaVertices bounds the size of polygons accepted from clients
+const MaxAreaVertices = 10000
+
+var ErrInvalidGeometry = errors.New("invalid geometry")
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ParseArea validates a GeoJSON Polygon or MultiPolygon geometry in WGS84
+// and returns it re-encoded in compact form, ready for ST_GeomFromGeoJSON.
+// A Feature wrapping such a geometry is also accepted.
+func ParseArea(raw []byte) (string, error) {
+	var feature struct {
+		Type     string          `json:"type"`
+		Geometry json.RawMessage `json:"geometry"`
+	}
+	if err := json.Unmarshal(raw, &feature); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+	if feature.Type == "Feature" {
+		raw = feature.Geometry
+	}
+
+	var g geometry
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+
+	var polygons [][][][2]float64
+	switch g.Type {
+	case "Polygon":
+		var polygon [][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return "", fmt.Errorf("%w: polygon coordinates: %v", ErrInvalidGeometry, err)
+		}
+		polygons = [][][][2]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return "", fmt.Errorf("%w: multipolygon coordinates: %v", ErrInvalidGeometry, err)
+		}
+	default:
+		return "", fmt.Errorf("%w: type must be Polygon or MultiPolygon, got %q", ErrInvalidGeometry, g.Type)
+	}
+
+	if err := validatePolygons(polygons); err != nil {
+		return "", err
+	}
+
+	normalized, err := json.Marshal(struct {
+		Type        string           `json:"type"`
+		Coordinates [][][][2]float64 `json:"coordinates"`
+	}{Type: "MultiPolygon", Coordinates: polygons})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+	return string(normalized), nil
+}
+
+func validatePolygons(polygons [][][][2]float64) error {
+	if len(polygons) == 0 {
+		return fmt.Errorf("%w: no polygons", ErrInvalidGeometry)
+	}
+
+	vertices := 0
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return fmt.Errorf("%w: polygon has no rings", ErrInvalidGeometry)
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return fmt.Errorf("%w: ring needs at least 4 positions", ErrInvalidGeometry)
+			}
+			if ring[0] != ring[len(ring)-1] {
+				return fmt.Errorf("%w: ring is not closed", ErrInvalidGeometry)
+			}
+			for _, position := range ring {
+				lon, lat := position[0], position[1]
+				if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+					return fmt.Errorf("%w: position [%g, %g] out of range", ErrInvalidGeometry, lon, lat)
+				}
+			}
+			vertices += len(ring)
+		}
+	}
+
+	if vertices > MaxAreaVertices {
+		return fmt.Errorf("%w: more than %d vertices", ErrInvalidGeometry, MaxAreaVertices)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package geo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAreaNormalizesPolygon(t *testing.T) {
+	raw := `{"type":"Feature","properties":{},"geometry":{"type":"Polygon","coordinates":[[[139.69,35.65],[139.71,35.65],[139.71,35.67],[139.69,35.65]]]}}`
+
+	area, err := ParseArea([]byte(raw))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(area, `{"type":"MultiPolygon","coordinates":[[[[139.69,35.65]`) {
+		t.Fatalf("expected normalized MultiPolygon, got %s", area)
+	}
+}
+
+func TestParseAreaRejectsInvalidGeometry(t *testing.T) {
+	cases := map[string]string{
+		"point":        `{"type":"Point","coordinates":[139.7,35.6]}`,
+		"open ring":    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`,
+		"short ring":   `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		"out of range": `{"type":"Polygon","coordinates":[[[0,0],[200,0],[1,1],[0,0]]]}`,
+		"not json":     `{`,
+	}
+
+	for name, raw := range cases {
+		if _, err := ParseArea([]byte(raw)); !errors.Is(err, ErrInvalidGeometry) {
+			t.Errorf("%s: expected ErrInvalidGeometry, got %v", name, err)
+		}
+	}
+}
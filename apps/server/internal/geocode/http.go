@@ -0,0 +1,158 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// HTTPSource labels results produced by HTTPProvider
+const HTTPSource = "http"
+
+// HTTPProvider geocodes through a Nominatim-compatible web API
+// (/search and /reverse with format=jsonv2). APIKey, when set, is sent as
+// the "key" query parameter used by hosted Nominatim services.
+type HTTPProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL, apiKey string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type nominatimPlace struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Error       string `json:"error"`
+	Address     struct {
+		Postcode      string `json:"postcode"`
+		Province      string `json:"province"`
+		State         string `json:"state"`
+		City          string `json:"city"`
+		Town          string `json:"town"`
+		Village       string `json:"village"`
+		Suburb        string `json:"suburb"`
+		Quarter       string `json:"quarter"`
+		Neighbourhood string `json:"neighbourhood"`
+	} `json:"address"`
+}
+
+func (p nominatimPlace) toDomain() (domain.GeocodeResult, error) {
+	lat, err := strconv.ParseFloat(p.Lat, 64)
+	if err != nil {
+		return domain.GeocodeResult{}, fmt.Errorf("invalid latitude %q from geocoder", p.Lat)
+	}
+	lon, err := strconv.ParseFloat(p.Lon, 64)
+	if err != nil {
+		return domain.GeocodeResult{}, fmt.Errorf("invalid longitude %q from geocoder", p.Lon)
+	}
+
+	postalCode, _ := NormalizePostalCode(p.Address.Postcode)
+	return domain.GeocodeResult{
+		Latitude:   lat,
+		Longitude:  lon,
+		Label:      p.DisplayName,
+		PostalCode: postalCode,
+		Prefecture: firstNonEmpty(p.Address.Province, p.Address.State),
+		City:       firstNonEmpty(p.Address.City, p.Address.Town, p.Address.Village),
+		Town:       firstNonEmpty(p.Address.Quarter, p.Address.Suburb, p.Address.Neighbourhood),
+		Source:     HTTPSource,
+	}, nil
+}
+
+// Geocode resolves a free-form address or postal code
+func (p *HTTPProvider) Geocode(ctx context.Context, address string, limit int) ([]domain.GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("q", address)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("countrycodes", "jp")
+
+	var places []nominatimPlace
+	if err := p.get(ctx, "/search", params, &places); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.GeocodeResult, 0, len(places))
+	for _, place := range places {
+		result, err := place.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ReverseGeocode returns the address at a point, or nil when there is none
+func (p *HTTPProvider) ReverseGeocode(ctx context.Context, lat, lon float64) (*domain.GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+
+	var place nominatimPlace
+	if err := p.get(ctx, "/reverse", params, &place); err != nil {
+		return nil, err
+	}
+	if place.Error != "" {
+		return nil, nil
+	}
+
+	result, err := place.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (p *HTTPProvider) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	if p.apiKey != "" {
+		params.Set("key", p.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build geocoder request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", "ja")
+	req.Header.Set("User-Agent", "price-comparison-server")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("geocoder request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode geocoder response: %w", err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
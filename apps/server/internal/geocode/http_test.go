@@ -0,0 +1,47 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPProviderGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.URL.Query().Get("q") != "渋谷駅" || r.URL.Query().Get("key") != "secret" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Write([]byte(`[{"lat":"35.658","lon":"139.7016","display_name":"渋谷駅, 渋谷区, 東京都","address":{"postcode":"150-0043","province":"東京都","city":"渋谷区"}}]`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(server.URL+"/", "secret", time.Second)
+	results, err := provider.Geocode(context.Background(), "渋谷駅", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	got := results[0]
+	if got.Latitude != 35.658 || got.Longitude != 139.7016 || got.PostalCode != "1500043" || got.Prefecture != "東京都" || got.Source != HTTPSource {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+func TestHTTPProviderReverseNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":"Unable to geocode"}`))
+	}))
+	defer server.Close()
+
+	result, err := NewHTTPProvider(server.URL, "", time.Second).ReverseGeocode(context.Background(), 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != nil {
+		t.Fatalf("expected no result, got %+v", result)
+	}
+}
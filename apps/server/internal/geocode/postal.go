@@ -0,0 +1,32 @@
+package geocode
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizePostalCode recognises Japanese postal codes written as
+// "150-0043", "〒1500043" or with full-width digits, returning the
+// seven bare digits
+func NormalizePostalCode(value string) (string, bool) {
+	normalized := strings.TrimSpace(norm.NFKC.String(value))
+	normalized = strings.TrimPrefix(normalized, "〒")
+
+	var digits strings.Builder
+	for _, r := range normalized {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '-' || r == 'ー' || r == '−' || unicode.IsSpace(r):
+		default:
+			return "", false
+		}
+	}
+
+	if digits.Len() != 7 {
+		return "", false
+	}
+	return digits.String(), true
+}
@@ -0,0 +1,24 @@
+package geocode
+
+import "testing"
+
+func TestNormalizePostalCode(t *testing.T) {
+	cases := map[string]string{
+		"150-0043":   "1500043",
+		"〒1500043":   "1500043",
+		"１５０－００４３":   "1500043",
+		" 150 0043 ": "1500043",
+	}
+	for input, want := range cases {
+		got, ok := NormalizePostalCode(input)
+		if !ok || got != want {
+			t.Errorf("NormalizePostalCode(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+
+	for _, input := range []string{"150-004", "東京都渋谷区", "150-0043 道玄坂"} {
+		if _, ok := NormalizePostalCode(input); ok {
+			t.Errorf("NormalizePostalCode(%q) should not match", input)
+		}
+	}
+}
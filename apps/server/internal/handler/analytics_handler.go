@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+const csvContentType = "text/csv; charset=utf-8"
+
+type AnalyticsHandler struct {
+	priceIndexUsecase *usecase.PriceIndexUsecase
+	forecastUsecase   *usecase.ForecastUsecase
+	areaUsecase       *usecase.AreaUsecase
+}
+
+func NewAnalyticsHandler(priceIndexUsecase *usecase.PriceIndexUsecase, forecastUsecase *usecase.ForecastUsecase, areaUsecase *usecase.AreaUsecase) *AnalyticsHandler {
+	return &AnalyticsHandler{priceIndexUsecase: priceIndexUsecase, forecastUsecase: forecastUsecase, areaUsecase: areaUsecase}
+}
+
+// GetPriceIndex handles GET /api/analytics/price-index
+// Query params: category (required), area_id, period (month|week, default: month),
+// from, to (YYYY-MM-DD or YYYY-MM, default: the last 365 days), base (default: first priced period),
+// tax; format=csv returns the points as CSV
+func (h *AnalyticsHandler) GetPriceIndex(c *gin.Context) {
+	areaID, err := parseOptionalID(c, "area_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid area_id")
+		return
+	}
+	opts := usecase.PriceIndexOptions{
+		Category: c.Query("category"),
+		AreaID:   areaID,
+		Period:   c.Query("period"),
+	}
+	dates := []struct {
+		key    string
+		target *time.Time
+	}{{"from", &opts.From}, {"to", &opts.To}, {"base", &opts.Base}}
+	for _, date := range dates {
+		if *date.target, err = parseOptionalDate(c, date.key); err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid "+date.key)
+			return
+		}
+	}
+	if opts.Tax, err = parseTaxMode(c); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+	if !requireArea(c, h.areaUsecase, areaID) {
+		return
+	}
+
+	index, err := h.priceIndexUsecase.PriceIndex(opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPriceIndex) || isCurrencyError(err) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		body, err := priceIndexCSV(index)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="price-index.csv"`)
+		c.Data(http.StatusOK, csvContentType, body)
+		return
+	}
+
+	response.OK(c, index, nil)
+}
+
+// GetPriceForecast handles GET /api/analytics/price-forecast
+// Query params: store_id, product_id (both required), history_days (default: 180, max: 730), tax
+func (h *AnalyticsHandler) GetPriceForecast(c *gin.Context) {
+	storeID, err := parseOptionalID(c, "store_id")
+	if err != nil || storeID == 0 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid store_id")
+		return
+	}
+	productID, err := parseOptionalID(c, "product_id")
+	if err != nil || productID == 0 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid product_id")
+		return
+	}
+	historyDays := 0
+	if value := c.Query("history_days"); value != "" {
+		historyDays, err = strconv.Atoi(value)
+		if err != nil || historyDays <= 0 {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid history_days")
+			return
+		}
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+
+	forecast, err := h.forecastUsecase.Forecast(usecase.PriceForecastOptions{
+		StoreID:     storeID,
+		ProductID:   productID,
+		HistoryDays: historyDays,
+		Tax:         tax,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInsufficientHistory) {
+			response.Error(c, http.StatusNotFound, response.ErrNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, forecast, nil)
+}
+
+// priceIndexCSV writes one row per period; index and change are empty
+// where the JSON has none
+func priceIndexCSV(index *domain.PriceIndex) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"period_start", "index", "change_percent", "products", "observations"})
+	for _, point := range index.Points {
+		w.Write([]string{
+			point.PeriodStart.Format("2006-01-02"),
+			formatOptionalFloat(point.Index),
+			formatOptionalFloat(point.Change),
+			strconv.Itoa(point.Products),
+			strconv.Itoa(point.Observations),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 4, 64)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/middleware"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type AnomalyHandler struct {
+	anomalyUsecase *usecase.AnomalyUsecase
+}
+
+func NewAnomalyHandler(anomalyUsecase *usecase.AnomalyUsecase) *AnomalyHandler {
+	return &AnomalyHandler{anomalyUsecase: anomalyUsecase}
+}
+
+// GetAnomalies handles GET /api/prices/anomalies
+// Query params: status (quarantined|rejected, default: quarantined), product_id, limit, offset
+func (h *AnomalyHandler) GetAnomalies(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+	productID, err := parseOptionalID(c, "product_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid product_id")
+		return
+	}
+
+	anomalies, err := h.anomalyUsecase.List(usecase.AnomalyListOptions{
+		Status:     c.Query("status"),
+		ProductID:  productID,
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+
+	response.OK(c, anomalies, &response.Meta{
+		Count:  len(anomalies),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+type reviewPriceRequest struct {
+	Action string `json:"action"`
+}
+
+// ReviewPrice handles POST /api/prices/:id/review for the moderator named
+// by the X-Admin-Key header
+// Body: {"action": "approve"} or {"action": "reject"}
+func (h *AnomalyHandler) ReviewPrice(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid price id")
+		return
+	}
+	var req reviewPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+	if req.Action != usecase.ReviewApprove && req.Action != usecase.ReviewReject {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "action must be approve or reject")
+		return
+	}
+
+	reviewed, err := h.anomalyUsecase.Review(id, req.Action, middleware.AdminName(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if reviewed == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "quarantined price not found")
+		return
+	}
+
+	response.OK(c, reviewed, nil)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/geo"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type AreaHandler struct {
+	areaUsecase            *usecase.AreaUsecase
+	competitivenessUsecase *usecase.CompetitivenessUsecase
+}
+
+func NewAreaHandler(areaUsecase *usecase.AreaUsecase, competitivenessUsecase *usecase.CompetitivenessUsecase) *AreaHandler {
+	return &AreaHandler{areaUsecase: areaUsecase, competitivenessUsecase: competitivenessUsecase}
+}
+
+type createAreaRequest struct {
+	Name     string          `json:"name"`
+	Geometry json.RawMessage `json:"geometry"`
+}
+
+// CreateArea handles POST /api/areas
+// Body: {"name": "...", "geometry": <GeoJSON Polygon/MultiPolygon or Feature>}
+func (h *AreaHandler) CreateArea(c *gin.Context) {
+	var req createAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "name is required")
+		return
+	}
+
+	geoJSON, err := geo.ParseArea(req.Geometry)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+
+	area, err := h.areaUsecase.Create(usecase.AreaCreateOptions{Name: req.Name, GeoJSON: geoJSON})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIResponse{Data: area})
+}
+
+// GetAllAreas handles GET /api/areas
+func (h *AreaHandler) GetAllAreas(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	areas, err := h.areaUsecase.List(usecase.AreaListOptions{
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, areas, &response.Meta{
+		Count:  len(areas),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetAreaByID handles GET /api/areas/:id
+func (h *AreaHandler) GetAreaByID(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid area id")
+		return
+	}
+
+	area, err := h.areaUsecase.GetByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if area == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "area not found")
+		return
+	}
+
+	response.OK(c, area, nil)
+}
+
+// GetStoreRanking handles GET /api/areas/:id/store-ranking
+// Ranks the area's stores by competitiveness, cheapest first
+// Query params: limit, offset
+func (h *AreaHandler) GetStoreRanking(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid area id")
+		return
+	}
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	area, err := h.areaUsecase.GetByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+	if area == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "area not found")
+		return
+	}
+
+	rankings, err := h.competitivenessUsecase.Ranking(id, usecase.Pagination{Limit: limit, Offset: offset})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, rankings, &response.Meta{
+		Count:  len(rankings),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// requireArea responds 404 and reports false when areaID is set but no
+// saved area has it
+func requireArea(c *gin.Context, areaUsecase *usecase.AreaUsecase, areaID int) bool {
+	if areaID == 0 {
+		return true
+	}
+	area, err := areaUsecase.GetByID(areaID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return false
+	}
+	if area == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "area not found")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type ChainHandler struct {
+	chainUsecase *usecase.ChainUsecase
+	priceUsecase *usecase.PriceUsecase
+}
+
+func NewChainHandler(chainUsecase *usecase.ChainUsecase, priceUsecase *usecase.PriceUsecase) *ChainHandler {
+	return &ChainHandler{chainUsecase: chainUsecase, priceUsecase: priceUsecase}
+}
+
+// GetAllChains handles GET /api/chains
+func (h *ChainHandler) GetAllChains(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	chains, err := h.chainUsecase.List(usecase.ChainListOptions{
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, chains, &response.Meta{
+		Count:  len(chains),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetChainByID handles GET /api/chains/:id
+func (h *ChainHandler) GetChainByID(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid chain id")
+		return
+	}
+
+	chain, err := h.chainUsecase.GetByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if chain == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "chain not found")
+		return
+	}
+
+	response.OK(c, chain, nil)
+}
+
+// GetChainPriceStats handles GET /api/chains/:id/price-stats
+// Query params: category, q, days (default: 14, max: 1830), currency
+func (h *ChainHandler) GetChainPriceStats(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid chain id")
+		return
+	}
+
+	days := 0
+	if daysParam := c.Query("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid days")
+			return
+		}
+		days = parsed
+	}
+	currency, err := parseCurrency(c, "currency")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid currency")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+
+	stats, err := h.priceUsecase.GetChainPriceStats(usecase.ChainPriceStatsOptions{
+		ChainID:  id,
+		Category: c.Query("category"),
+		Query:    c.Query("q"),
+		Currency: currency,
+		Tax:      tax,
+		Days:     days,
+	})
+	if err != nil {
+		if isCurrencyError(err) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, stats, nil)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type ConnectorHandler struct {
+	connectorUsecase *usecase.ConnectorUsecase
+}
+
+func NewConnectorHandler(connectorUsecase *usecase.ConnectorUsecase) *ConnectorHandler {
+	return &ConnectorHandler{connectorUsecase: connectorUsecase}
+}
+
+// GetConnectors handles GET /api/connectors
+func (h *ConnectorHandler) GetConnectors(c *gin.Context) {
+	jobs := h.connectorUsecase.Jobs()
+	response.OK(c, jobs, &response.Meta{Count: len(jobs)})
+}
+
+// GetConnectorRuns handles GET /api/connectors/runs
+// Query params: connector, limit, offset
+func (h *ConnectorHandler) GetConnectorRuns(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	runs, err := h.connectorUsecase.ListRuns(usecase.ConnectorRunListOptions{
+		Connector:  c.Query("connector"),
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, runs, &response.Meta{
+		Count:  len(runs),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// RunConnector handles POST /api/connectors/:name/run
+// Runs the connector now and responds with the finished run
+func (h *ConnectorHandler) RunConnector(c *gin.Context) {
+	// A client hanging up should not abort the import half way
+	run, err := h.connectorUsecase.Run(context.WithoutCancel(c.Request.Context()), c.Param("name"))
+	if errors.Is(err, domain.ErrConnectorNotFound) {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrConnectorRunning) {
+		response.Error(c, http.StatusConflict, response.ErrConflict, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, run, nil)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type CurrencyHandler struct {
+	currencyUsecase *usecase.CurrencyUsecase
+}
+
+func NewCurrencyHandler(currencyUsecase *usecase.CurrencyUsecase) *CurrencyHandler {
+	return &CurrencyHandler{currencyUsecase: currencyUsecase}
+}
+
+// GetExchangeRates handles GET /api/exchange-rates
+// Query params: base, quote, limit, offset
+func (h *CurrencyHandler) GetExchangeRates(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+	base, err := parseCurrency(c, "base")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid base currency")
+		return
+	}
+	quote, err := parseCurrency(c, "quote")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid quote currency")
+		return
+	}
+
+	rates, err := h.currencyUsecase.ListRates(usecase.ExchangeRateListOptions{
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+		Base:       base,
+		Quote:      quote,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, rates, &response.Meta{
+		Count:  len(rates),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+type saveExchangeRateRequest struct {
+	BaseCurrency  string  `json:"base_currency"`
+	QuoteCurrency string  `json:"quote_currency"`
+	RateDate      string  `json:"rate_date"` // YYYY-MM-DD
+	Rate          float64 `json:"rate"`
+	Source        string  `json:"source"`
+}
+
+// SaveExchangeRate handles POST /api/exchange-rates
+// Body: {"base_currency": "USD", "quote_currency": "JPY", "rate_date": "2024-01-31", "rate": 147.5}
+func (h *CurrencyHandler) SaveExchangeRate(c *gin.Context) {
+	var req saveExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+	rateDate, err := time.Parse("2006-01-02", req.RateDate)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid rate_date")
+		return
+	}
+
+	rate, err := h.currencyUsecase.SaveRate(domain.ExchangeRate{
+		BaseCurrency:  req.BaseCurrency,
+		QuoteCurrency: req.QuoteCurrency,
+		RateDate:      rateDate,
+		Rate:          req.Rate,
+		Source:        req.Source,
+	})
+	if errors.Is(err, domain.ErrInvalidExchangeRate) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to save exchange rate")
+		return
+	}
+
+	response.OK(c, rate, nil)
+}
+
+// Convert handles GET /api/exchange-rates/convert
+// Query params: amount, from, to, date (YYYY-MM-DD, default: today)
+func (h *CurrencyHandler) Convert(c *gin.Context) {
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid amount")
+		return
+	}
+	from, err := parseCurrency(c, "from")
+	if err != nil || from == "" {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid from currency")
+		return
+	}
+	to, err := parseCurrency(c, "to")
+	if err != nil || to == "" {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid to currency")
+		return
+	}
+
+	var date *time.Time
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsed, err := time.Parse("2006-01-02", dateParam)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid date")
+			return
+		}
+		date = &parsed
+	}
+
+	conversion, err := h.currencyUsecase.Convert(usecase.ConvertOptions{Amount: amount, From: from, To: to, Date: date})
+	if err != nil {
+		if isCurrencyError(err) {
+			response.Error(c, http.StatusNotFound, response.ErrNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, conversion, nil)
+}
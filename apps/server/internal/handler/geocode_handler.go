@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type GeocodeHandler struct {
+	geocodeUsecase *usecase.GeocodeUsecase
+}
+
+func NewGeocodeHandler(geocodeUsecase *usecase.GeocodeUsecase) *GeocodeHandler {
+	return &GeocodeHandler{geocodeUsecase: geocodeUsecase}
+}
+
+// Geocode handles GET /api/geocode
+// Query params: q (address or postal code), limit (default: 5, max: 20)
+func (h *GeocodeHandler) Geocode(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "q is required")
+		return
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := h.geocodeUsecase.Geocode(usecase.GeocodeOptions{Query: q, Limit: limit})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, results, &response.Meta{Count: len(results)})
+}
+
+// ReverseGeocode handles GET /api/geocode/reverse
+// Query params: lat, lon
+func (h *GeocodeHandler) ReverseGeocode(c *gin.Context) {
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid latitude")
+		return
+	}
+	lon, err := strconv.ParseFloat(c.Query("lon"), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid longitude")
+		return
+	}
+
+	result, err := h.geocodeUsecase.Reverse(usecase.ReverseGeocodeOptions{Latitude: lat, Longitude: lon})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if result == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "no address near this location")
+		return
+	}
+
+	response.OK(c, result, nil)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type JobHandler struct {
+	jobUsecase *usecase.JobUsecase
+}
+
+func NewJobHandler(jobUsecase *usecase.JobUsecase) *JobHandler {
+	return &JobHandler{jobUsecase: jobUsecase}
+}
+
+type createJobRequest struct {
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	RunAt       *time.Time      `json:"run_at"`
+	MaxAttempts int             `json:"max_attempts"`
+}
+
+// CreateJob handles POST /api/jobs
+// Body: {"type": "connector.run", "payload": {"connector": "local-feed"},
+// "run_at": "2024-02-01T03:00:00+09:00", "max_attempts": 3}
+func (h *JobHandler) CreateJob(c *gin.Context) {
+	var req createJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+
+	job := domain.Job{Type: req.Type, Payload: req.Payload, MaxAttempts: req.MaxAttempts}
+	if req.RunAt != nil {
+		job.RunAt = *req.RunAt
+	}
+
+	created, err := h.jobUsecase.Enqueue(job)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidJob) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to enqueue job")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIResponse{Data: created})
+}
+
+// GetJobs handles GET /api/jobs
+// Query params: status (queued|running|succeeded|failed), type, limit, offset
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	found, err := h.jobUsecase.List(usecase.JobListOptions{
+		Status:     c.Query("status"),
+		Type:       c.Query("type"),
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+
+	response.OK(c, found, &response.Meta{
+		Count:  len(found),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetJobSummary handles GET /api/jobs/summary
+func (h *JobHandler) GetJobSummary(c *gin.Context) {
+	summary, err := h.jobUsecase.Summary()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, summary, nil)
+}
+
+// GetJobByID handles GET /api/jobs/:id
+func (h *JobHandler) GetJobByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid job id")
+		return
+	}
+
+	job, err := h.jobUsecase.GetByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if job == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "job not found")
+		return
+	}
+
+	response.OK(c, job, nil)
+}
+
+// RetryJob handles POST /api/jobs/:id/retry
+// Requeues a failed job with a fresh set of attempts
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid job id")
+		return
+	}
+
+	job, err := h.jobUsecase.Retry(id)
+	if errors.Is(err, domain.ErrJobNotRetryable) {
+		response.Error(c, http.StatusConflict, response.ErrConflict, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if job == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "job not found")
+		return
+	}
+
+	response.OK(c, job, nil)
+}
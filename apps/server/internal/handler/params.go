@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
 	"github.com/price-comparison/server/internal/query"
 	"github.com/price-comparison/server/internal/usecase"
 )
@@ -98,6 +101,106 @@ func parseUserLocation(c *gin.Context) (*query.GeoPoint, error) {
 	return &query.GeoPoint{Lat: lat, Lon: lon}, nil
 }
 
+func parseOptionalFloat(c *gin.Context, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// parsePathID reads a positive ID from the :key path param
+func parsePathID(c *gin.Context, key string) (int, error) {
+	id, err := strconv.Atoi(c.Param(key))
+	if err != nil || id <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
+
+// parseOptionalID reads a positive ID; 0 when the param is absent
+func parseOptionalID(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
+
+// parseOptionalDate reads a YYYY-MM-DD date, or YYYY-MM for the first of
+// the month; the zero time when the param is absent
+func parseOptionalDate(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01", value)
+}
+
+// parseMultiValue collects a repeatable query param; each occurrence may
+// also hold comma-separated values (category=a&category=b or category=a,b)
+func parseMultiValue(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		values = append(values, splitAndTrim(raw)...)
+	}
+	return values
+}
+
+func parseIDList(c *gin.Context, key string) ([]int, error) {
+	var ids []int
+	for _, raw := range parseMultiValue(c, key) {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return nil, strconv.ErrSyntax
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseOpenAt reads open_now=true (current time) or open_at=<RFC3339>
+func parseOpenAt(c *gin.Context) (*time.Time, error) {
+	openNow := c.Query("open_now")
+	openAt := c.Query("open_at")
+	if openNow != "" && openAt != "" {
+		return nil, errors.New("open_now and open_at are mutually exclusive")
+	}
+
+	if openNow != "" {
+		enabled, err := strconv.ParseBool(openNow)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, nil
+		}
+		now := time.Now()
+		return &now, nil
+	}
+
+	if openAt != "" {
+		parsed, err := time.Parse(time.RFC3339, openAt)
+		if err != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+
+	return nil, nil
+}
+
 func splitAndTrim(value string) []string {
 	raw := strings.Split(value, ",")
 	out := make([]string, 0, len(raw))
@@ -109,3 +212,66 @@ func splitAndTrim(value string) []string {
 	}
 	return out
 }
+
+// parseCurrency reads an optional ISO 4217 currency code
+func parseCurrency(c *gin.Context, key string) (string, error) {
+	value := strings.ToUpper(strings.TrimSpace(c.Query(key)))
+	if value == "" {
+		return "", nil
+	}
+	if len(value) != 3 || strings.Trim(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", strconv.ErrSyntax
+	}
+	return value, nil
+}
+
+// parseTaxMode reads tax=included|excluded; empty leaves the default
+// (tax-inclusive) to the usecase
+func parseTaxMode(c *gin.Context) (query.TaxMode, error) {
+	mode := query.TaxMode(strings.ToLower(strings.TrimSpace(c.Query("tax"))))
+	switch mode {
+	case "", query.TaxIncluded, query.TaxExcluded:
+		return mode, nil
+	default:
+		return "", strconv.ErrSyntax
+	}
+}
+
+// parseMinConfidence reads min_confidence (0 to 1), falling back to the
+// configured threshold when absent
+func parseMinConfidence(c *gin.Context, defaultValue float64) (float64, error) {
+	raw := c.Query("min_confidence")
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || value > 1 {
+		return 0, strconv.ErrRange
+	}
+	return value, nil
+}
+
+// isCurrencyError reports whether err stems from prices that cannot be put
+// into a single currency, which the client can fix by choosing a currency
+func isCurrencyError(err error) bool {
+	return errors.Is(err, domain.ErrMixedCurrencies) || errors.Is(err, domain.ErrExchangeRateNotFound)
+}
+
+// parsePurchase reads quantity (default 1) and member for effective-price
+// comparisons
+func parsePurchase(c *gin.Context) (quantity int, member bool, err error) {
+	quantity = 1
+	if raw := c.Query("quantity"); raw != "" {
+		quantity, err = strconv.Atoi(raw)
+		if err != nil || quantity < 1 || quantity > usecase.MaxPurchaseQuantity {
+			return 0, false, strconv.ErrRange
+		}
+	}
+	if raw := c.Query("member"); raw != "" {
+		member, err = strconv.ParseBool(raw)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	return quantity, member, nil
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
 	"github.com/price-comparison/server/internal/response"
 	"github.com/price-comparison/server/internal/usecase"
 )
@@ -12,12 +14,16 @@ import (
 type ProductHandler struct {
 	productUsecase *usecase.ProductUsecase
 	priceUsecase   *usecase.PriceUsecase
+	minConfidence  float64
 }
 
-func NewProductHandler(productUsecase *usecase.ProductUsecase, priceUsecase *usecase.PriceUsecase) *ProductHandler {
+// NewProductHandler compares only prices with at least minConfidence
+// unless a request asks for another threshold
+func NewProductHandler(productUsecase *usecase.ProductUsecase, priceUsecase *usecase.PriceUsecase, minConfidence float64) *ProductHandler {
 	return &ProductHandler{
 		productUsecase: productUsecase,
 		priceUsecase:   priceUsecase,
+		minConfidence:  minConfidence,
 	}
 }
 
@@ -33,8 +39,12 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
 		Sort:       usecase.Sort{Field: sortField, Order: sortOrder},
 	})
+	if errors.Is(err, domain.ErrInvalidSearch) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to search products")
 		return
 	}
 
@@ -69,6 +79,7 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 }
 
 // SearchProducts handles GET /api/products/search?q=keyword
+// Query params: category, min_price, max_price; sort defaults to relevance
 func (h *ProductHandler) SearchProducts(c *gin.Context) {
 	keyword := c.Query("q")
 	if keyword == "" {
@@ -82,8 +93,27 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		return
 	}
 	sortField, sortOrder := parseSort(c)
-	products, err := h.productUsecase.Search(usecase.ProductSearchOptions{
+
+	minPrice, err := parseOptionalFloat(c, "min_price")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid min_price")
+		return
+	}
+	maxPrice, err := parseOptionalFloat(c, "max_price")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid max_price")
+		return
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "min_price must not exceed max_price")
+		return
+	}
+
+	page, err := h.productUsecase.Search(usecase.ProductSearchOptions{
 		Keyword:    keyword,
+		Category:   c.Query("category"),
+		MinPrice:   minPrice,
+		MaxPrice:   maxPrice,
 		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
 		Sort:       usecase.Sort{Field: sortField, Order: sortOrder},
 	})
@@ -92,16 +122,17 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		return
 	}
 
-	response.OK(c, products, &response.Meta{
-		Count:  len(products),
-		Limit:  limit,
-		Offset: offset,
+	response.OK(c, page.Results, &response.Meta{
+		Count:       len(page.Results),
+		Limit:       limit,
+		Offset:      offset,
+		Suggestions: page.Suggestions,
 	})
 }
 
 // GetCategories handles GET /api/products/categories
 func (h *ProductHandler) GetCategories(c *gin.Context) {
-	categories, err := h.productUsecase.ListCategories()
+	categories, err := h.productUsecase.ListCategories(c.Query("lang"))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
@@ -127,12 +158,42 @@ func (h *ProductHandler) GetProductPrices(c *gin.Context) {
 		return
 	}
 	sortField, sortOrder := parseSort(c)
+	currency, err := parseCurrency(c, "currency")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid currency")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+	quantity, member, err := parsePurchase(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid quantity or member")
+		return
+	}
+	minConfidence, err := parseMinConfidence(c, h.minConfidence)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid min_confidence")
+		return
+	}
+
 	prices, err := h.priceUsecase.ListByProduct(usecase.PriceListOptions{
-		ProductID:  id,
-		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
-		Sort:       usecase.Sort{Field: sortField, Order: sortOrder},
+		ProductID:     id,
+		Currency:      currency,
+		Tax:           tax,
+		MinConfidence: minConfidence,
+		Quantity:      quantity,
+		Member:        member,
+		Pagination:    usecase.Pagination{Limit: limit, Offset: offset},
+		Sort:          usecase.Sort{Field: sortField, Order: sortOrder},
 	})
 	if err != nil {
+		if isCurrencyError(err) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
 	}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type PromotionHandler struct {
+	promotionUsecase *usecase.PromotionUsecase
+}
+
+func NewPromotionHandler(promotionUsecase *usecase.PromotionUsecase) *PromotionHandler {
+	return &PromotionHandler{promotionUsecase: promotionUsecase}
+}
+
+type createPromotionRequest struct {
+	StoreID        int        `json:"store_id"`
+	ProductID      int        `json:"product_id"`
+	SalePrice      *float64   `json:"sale_price"`
+	BundleQuantity *int       `json:"bundle_quantity"`
+	BundlePrice    *float64   `json:"bundle_price"`
+	Currency       string     `json:"currency"`
+	MemberOnly     bool       `json:"member_only"`
+	Description    string     `json:"description"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidTo        *time.Time `json:"valid_to"`
+}
+
+// CreatePromotion handles POST /api/promotions
+// Body: {"store_id": 1, "product_id": 2, "sale_price": 198, "valid_to": "2024-02-04T23:59:59+09:00"}
+// or a multi-buy: {"store_id": 1, "product_id": 2, "bundle_quantity": 2, "bundle_price": 300}
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req createPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+
+	promotion := domain.Promotion{
+		StoreID:        req.StoreID,
+		ProductID:      req.ProductID,
+		SalePrice:      req.SalePrice,
+		BundleQuantity: req.BundleQuantity,
+		BundlePrice:    req.BundlePrice,
+		Currency:       req.Currency,
+		MemberOnly:     req.MemberOnly,
+		Description:    req.Description,
+		ValidTo:        req.ValidTo,
+	}
+	if req.ValidFrom != nil {
+		promotion.ValidFrom = *req.ValidFrom
+	}
+
+	created, err := h.promotionUsecase.Create(promotion)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPromotion) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to create promotion")
+		return
+	}
+	if created == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "store or product not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIResponse{Data: created})
+}
+
+// GetPromotions handles GET /api/promotions
+// Query params: store_id, product_id, active (default: true), tax, limit, offset
+func (h *PromotionHandler) GetPromotions(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+	storeID, err := parseOptionalID(c, "store_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid store_id")
+		return
+	}
+	productID, err := parseOptionalID(c, "product_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid product_id")
+		return
+	}
+	activeOnly, err := strconv.ParseBool(c.DefaultQuery("active", "true"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid active")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+
+	promotions, err := h.promotionUsecase.List(usecase.PromotionListOptions{
+		StoreID:    storeID,
+		ProductID:  productID,
+		ActiveOnly: activeOnly,
+		Tax:        tax,
+		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, promotions, &response.Meta{
+		Count:  len(promotions),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetPromotionByID handles GET /api/promotions/:id
+func (h *PromotionHandler) GetPromotionByID(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid promotion id")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+
+	promotion, err := h.promotionUsecase.GetByID(id, tax)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if promotion == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "promotion not found")
+		return
+	}
+
+	response.OK(c, promotion, nil)
+}
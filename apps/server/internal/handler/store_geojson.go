@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/geo"
+	"github.com/price-comparison/server/internal/response"
+)
+
+const geoJSONContentType = "application/geo+json"
+
+// storeFeatureProperties is the flattened store shape used for map rendering
+type storeFeatureProperties struct {
+	Name            string   `json:"name"`
+	Address         string   `json:"address"`
+	Phone           string   `json:"phone,omitempty"`
+	ChainID         *int     `json:"chain_id,omitempty"`
+	ChainName       string   `json:"chain_name,omitempty"`
+	MinPrice        *float64 `json:"min_price,omitempty"`
+	MinUnitPrice    *float64 `json:"min_unit_price,omitempty"`
+	UnitPriceBasis  string   `json:"min_unit_price_basis,omitempty"`
+	Competitiveness *float64 `json:"competitiveness,omitempty"`
+	Distance        *float64 `json:"distance,omitempty"`
+	TravelTime      *float64 `json:"travel_time,omitempty"`
+	IsOpen          *bool    `json:"is_open,omitempty"`
+}
+
+func storeFeatureCollection(stores []domain.Store) geo.FeatureCollection {
+	features := make([]geo.Feature, 0, len(stores))
+	for _, store := range stores {
+		properties := storeFeatureProperties{
+			Name:            store.Name,
+			Address:         store.Address,
+			Phone:           store.Phone,
+			MinPrice:        store.MinPrice,
+			MinUnitPrice:    store.MinUnitPrice,
+			UnitPriceBasis:  store.MinUnitPriceBasis,
+			Competitiveness: store.Competitiveness,
+			Distance:        store.Distance,
+			TravelTime:      store.TravelTime,
+			IsOpen:          store.IsOpen,
+		}
+		if store.Chain != nil {
+			chainID := store.Chain.ID
+			properties.ChainID = &chainID
+			properties.ChainName = store.Chain.Name
+		}
+		features = append(features, geo.NewPointFeature(store.ID, store.Longitude, store.Latitude, properties))
+	}
+	return geo.NewFeatureCollection(features)
+}
+
+// respondStores writes a store listing in the format selected by the
+// "format" query param: the standard envelope, or "geojson" for a bare
+// FeatureCollection that map libraries can load directly
+func respondStores(c *gin.Context, stores []domain.Store, meta *response.Meta) {
+	if c.Query("format") == "geojson" {
+		c.Header("Content-Type", geoJSONContentType)
+		c.JSON(http.StatusOK, storeFeatureCollection(stores))
+		return
+	}
+
+	response.OK(c, stores, meta)
+}
@@ -1,25 +1,39 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/geo"
 	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/routing"
 	"github.com/price-comparison/server/internal/usecase"
 )
 
 type StoreHandler struct {
-	storeUsecase *usecase.StoreUsecase
-	priceUsecase *usecase.PriceUsecase
+	storeUsecase   *usecase.StoreUsecase
+	priceUsecase   *usecase.PriceUsecase
+	geocodeUsecase *usecase.GeocodeUsecase
+	areaUsecase    *usecase.AreaUsecase
+	minConfidence  float64
 }
 
-func NewStoreHandler(storeUsecase *usecase.StoreUsecase, priceUsecase *usecase.PriceUsecase) *StoreHandler {
-	return &StoreHandler{storeUsecase: storeUsecase, priceUsecase: priceUsecase}
+// NewStoreHandler computes min_price from prices with at least
+// minConfidence unless a request asks for another threshold
+func NewStoreHandler(storeUsecase *usecase.StoreUsecase, priceUsecase *usecase.PriceUsecase, geocodeUsecase *usecase.GeocodeUsecase, areaUsecase *usecase.AreaUsecase, minConfidence float64) *StoreHandler {
+	return &StoreHandler{storeUsecase: storeUsecase, priceUsecase: priceUsecase, geocodeUsecase: geocodeUsecase, areaUsecase: areaUsecase, minConfidence: minConfidence}
 }
 
 // GetNearbyStores handles GET /api/stores/nearby
-// Query params: lat (latitude), lon (longitude), radius (meters, default: 5000)
+// Query params: lat (latitude), lon (longitude) or address (address or postal
+// code, geocoded server-side), radius (meters, default: 5000),
+// open_now (bool) or open_at (RFC3339); mode (walking|cycling|driving) with
+// minutes (default: 15) searches by road-network travel time instead of radius;
+// format=geojson returns a FeatureCollection
 func (h *StoreHandler) GetNearbyStores(c *gin.Context) {
 	latStr := c.Query("lat")
 	lonStr := c.Query("lon")
@@ -30,88 +44,303 @@ func (h *StoreHandler) GetNearbyStores(c *gin.Context) {
 		return
 	}
 
-	if latStr == "" || lonStr == "" {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "lat and lon are required")
-		return
+	var lat, lon float64
+	var location *domain.GeocodeResult
+	if address := c.Query("address"); address != "" && latStr == "" && lonStr == "" {
+		location, err = h.geocodeUsecase.Resolve(address)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+			return
+		}
+		if location == nil {
+			response.Error(c, http.StatusNotFound, response.ErrNotFound, "address not found")
+			return
+		}
+		lat, lon = location.Latitude, location.Longitude
+	} else {
+		if latStr == "" || lonStr == "" {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "lat and lon (or address) are required")
+			return
+		}
+
+		lat, err = strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid latitude")
+			return
+		}
+
+		lon, err = strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid longitude")
+			return
+		}
 	}
 
-	lat, err := strconv.ParseFloat(latStr, 64)
-	if err != nil {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid latitude")
+	radius, err := strconv.Atoi(radiusStr)
+	if err != nil || radius <= 0 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid radius")
 		return
 	}
 
-	lon, err := strconv.ParseFloat(lonStr, 64)
+	openAt, err := parseOpenAt(c)
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid longitude")
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid open_now/open_at")
 		return
 	}
 
-	radius, err := strconv.Atoi(radiusStr)
-	if err != nil || radius <= 0 {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid radius")
-		return
+	var travelMode routing.Mode
+	travelMinutes := 0
+	if modeParam := c.Query("mode"); modeParam != "" {
+		travelMode, err = routing.ParseMode(modeParam)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid mode")
+			return
+		}
+		travelMinutes, err = strconv.Atoi(c.DefaultQuery("minutes", "15"))
+		if err != nil || travelMinutes <= 0 || travelMinutes > usecase.MaxTravelMinutes {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid minutes")
+			return
+		}
 	}
 
 	stores, err := h.storeUsecase.Nearby(usecase.StoreNearbyOptions{
-		Latitude:   lat,
-		Longitude:  lon,
-		Radius:     radius,
-		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
+		Latitude:      lat,
+		Longitude:     lon,
+		Radius:        radius,
+		OpenAt:        openAt,
+		TravelMode:    travelMode,
+		TravelMinutes: travelMinutes,
+		Pagination:    usecase.Pagination{Limit: limit, Offset: offset},
 	})
+	if errors.Is(err, domain.ErrTravelTimeUnavailable) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
 	}
 
-	response.OK(c, stores, &response.Meta{
-		Count:  len(stores),
-		Limit:  limit,
-		Offset: offset,
+	respondStores(c, stores, &response.Meta{
+		Count:    len(stores),
+		Limit:    limit,
+		Offset:   offset,
+		Location: location,
 	})
 }
 
 // GetAllStores handles GET /api/stores
+// Repeatable filters: category (slug or name, subcategories included), chain_id,
+// product_id (store must stock all);
+// single-valued: q, min_price, max_price, recorded_within_days, min_confidence, open_now/open_at,
+// bbox, area_id, user_lat, user_lon; format=geojson returns a FeatureCollection
 func (h *StoreHandler) GetAllStores(c *gin.Context) {
-	limit, offset, err := parsePagination(c)
+	opts, err := parseStoreListOptions(c, h.minConfidence)
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
 		return
 	}
-	sortField, sortOrder := parseSort(c)
 
-	bounds, err := parseBounds(c)
+	h.listStores(c, opts)
+}
+
+// storeSearchRequest is the body of POST /api/stores/search
+type storeSearchRequest struct {
+	Area   json.RawMessage `json:"area"`
+	AreaID int             `json:"area_id"`
+}
+
+// SearchStores handles POST /api/stores/search
+// Body: {"area": <GeoJSON Polygon/MultiPolygon or Feature>, "area_id": <saved area>};
+// all GET /api/stores query params are accepted as well
+func (h *StoreHandler) SearchStores(c *gin.Context) {
+	opts, err := parseStoreListOptions(c, h.minConfidence)
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid bbox")
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
 		return
 	}
 
-	userLocation, err := parseUserLocation(c)
+	var req storeSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+	if len(req.Area) > 0 {
+		area, err := geo.ParseArea(req.Area)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
+		opts.AreaGeoJSON = area
+	}
+	if req.AreaID < 0 {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid area_id")
+		return
+	}
+	if req.AreaID > 0 {
+		opts.AreaID = req.AreaID
+	}
+
+	h.listStores(c, opts)
+}
+
+// GetStoreClusters handles GET /api/stores/clusters
+// Query params: bbox and zoom (0-22) are required; accepts the same filters as
+// GET /api/stores. Each cluster's min_price reflects the active product filter.
+func (h *StoreHandler) GetStoreClusters(c *gin.Context) {
+	opts, err := parseStoreListOptions(c, h.minConfidence)
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid user location")
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+	if opts.Bounds == nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "bbox is required")
 		return
 	}
 
-	stores, err := h.storeUsecase.List(usecase.StoreListOptions{
-		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
-		Sort:       usecase.Sort{Field: sortField, Order: sortOrder},
-		Query:      c.Query("q"),
-		Category:   c.Query("category"),
-		Bounds:     bounds,
-		UserLocation: userLocation,
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > usecase.MaxTileZoom {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid zoom")
+		return
+	}
+	if !requireArea(c, h.areaUsecase, opts.AreaID) {
+		return
+	}
+
+	clusters, err := h.storeUsecase.Clusters(usecase.StoreClusterOptions{
+		StoreListOptions: opts,
+		Zoom:             zoom,
 	})
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
 	}
 
-	response.OK(c, stores, &response.Meta{
+	response.OK(c, clusters, &response.Meta{Count: len(clusters)})
+}
+
+func (h *StoreHandler) listStores(c *gin.Context, opts usecase.StoreListOptions) {
+	if !requireArea(c, h.areaUsecase, opts.AreaID) {
+		return
+	}
+
+	stores, err := h.storeUsecase.List(opts)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	respondStores(c, stores, &response.Meta{
 		Count:  len(stores),
-		Limit:  limit,
-		Offset: offset,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
 	})
 }
 
+// parseStoreListOptions reads the store listing filters from the query
+// string, defaulting min_confidence to minConfidence; the returned error is
+// a client-facing message
+func parseStoreListOptions(c *gin.Context, minConfidence float64) (usecase.StoreListOptions, error) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid pagination")
+	}
+	sortField, sortOrder := parseSort(c)
+
+	bounds, err := parseBounds(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid bbox")
+	}
+
+	userLocation, err := parseUserLocation(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid user location")
+	}
+
+	minPrice, err := parseOptionalFloat(c, "min_price")
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid min_price")
+	}
+	maxPrice, err := parseOptionalFloat(c, "max_price")
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid max_price")
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		return usecase.StoreListOptions{}, errors.New("min_price must not exceed max_price")
+	}
+
+	productIDs, err := parseIDList(c, "product_id")
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid product_id")
+	}
+
+	chainIDs, err := parseIDList(c, "chain_id")
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid chain_id")
+	}
+
+	recordedWithinDays := 0
+	if daysParam := c.Query("recorded_within_days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 {
+			return usecase.StoreListOptions{}, errors.New("invalid recorded_within_days")
+		}
+		recordedWithinDays = parsed
+	}
+
+	openAt, err := parseOpenAt(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid open_now/open_at")
+	}
+
+	currency, err := parseCurrency(c, "currency")
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid currency")
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid tax")
+	}
+	minConfidence, err = parseMinConfidence(c, minConfidence)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid min_confidence")
+	}
+	quantity, member, err := parsePurchase(c)
+	if err != nil {
+		return usecase.StoreListOptions{}, errors.New("invalid quantity or member")
+	}
+
+	areaID := 0
+	if areaParam := c.Query("area_id"); areaParam != "" {
+		parsed, err := strconv.Atoi(areaParam)
+		if err != nil || parsed <= 0 {
+			return usecase.StoreListOptions{}, errors.New("invalid area_id")
+		}
+		areaID = parsed
+	}
+
+	return usecase.StoreListOptions{
+		Pagination:         usecase.Pagination{Limit: limit, Offset: offset},
+		Sort:               usecase.Sort{Field: sortField, Order: sortOrder},
+		Query:              c.Query("q"),
+		Categories:         parseMultiValue(c, "category"),
+		MinPrice:           minPrice,
+		MaxPrice:           maxPrice,
+		ProductIDs:         productIDs,
+		ChainIDs:           chainIDs,
+		RecordedWithinDays: recordedWithinDays,
+		Currency:           currency,
+		Tax:                tax,
+		MinConfidence:      minConfidence,
+		Quantity:           quantity,
+		Member:             member,
+		OpenAt:             openAt,
+		Bounds:             bounds,
+		AreaID:             areaID,
+		UserLocation:       userLocation,
+	}, nil
+}
+
 // GetStoreByID handles GET /api/stores/:id
 func (h *StoreHandler) GetStoreByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -151,14 +380,37 @@ func (h *StoreHandler) GetStorePrices(c *gin.Context) {
 	}
 	sortField, sortOrder := parseSort(c)
 	category := c.Query("category")
+	currency, err := parseCurrency(c, "currency")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid currency")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+	quantity, member, err := parsePurchase(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid quantity or member")
+		return
+	}
 
 	prices, err := h.priceUsecase.ListByStore(usecase.StorePriceListOptions{
-		StoreID:   id,
-		Category:  category,
+		StoreID:    id,
+		Category:   category,
+		Currency:   currency,
+		Tax:        tax,
+		Quantity:   quantity,
+		Member:     member,
 		Pagination: usecase.Pagination{Limit: limit, Offset: offset},
-		Sort:      usecase.Sort{Field: sortField, Order: sortOrder},
+		Sort:       usecase.Sort{Field: sortField, Order: sortOrder},
 	})
 	if err != nil {
+		if isCurrencyError(err) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
 	}
@@ -190,14 +442,30 @@ func (h *StoreHandler) GetStorePriceStats(c *gin.Context) {
 		}
 		days = parsed
 	}
+	currency, err := parseCurrency(c, "currency")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid currency")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
 
 	stats, err := h.priceUsecase.GetStorePriceStats(usecase.StorePriceStatsOptions{
 		StoreID:  id,
 		Category: category,
 		Query:    query,
+		Currency: currency,
+		Tax:      tax,
 		Days:     days,
 	})
 	if err != nil {
+		if isCurrencyError(err) {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
 		return
 	}
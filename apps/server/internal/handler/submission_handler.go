@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/middleware"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type SubmissionHandler struct {
+	submissionUsecase *usecase.SubmissionUsecase
+}
+
+func NewSubmissionHandler(submissionUsecase *usecase.SubmissionUsecase) *SubmissionHandler {
+	return &SubmissionHandler{submissionUsecase: submissionUsecase}
+}
+
+type submissionContributor struct {
+	DisplayName string `json:"display_name"`
+}
+
+type createSubmissionRequest struct {
+	Contributor submissionContributor `json:"contributor"`
+	StoreID     int                   `json:"store_id"`
+	ProductID   int                   `json:"product_id"`
+	Price       float64               `json:"price"`
+	Currency    string                `json:"currency"`
+	TaxIncluded *bool                 `json:"tax_included"`
+	PhotoURL    string                `json:"photo_url"`
+	Note        string                `json:"note"`
+	ObservedAt  *time.Time            `json:"observed_at"`
+}
+
+// CreateSubmission handles POST /api/submissions for the contributor named
+// by the X-Contributor-Token header
+// Body: {"contributor": {"display_name": "たろう"},
+// "store_id": 1, "product_id": 2, "price": 198, "tax_included": true,
+// "photo_url": "https://...", "observed_at": "2024-02-01T10:00:00+09:00"}
+func (h *SubmissionHandler) CreateSubmission(c *gin.Context) {
+	var req createSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+
+	submission := domain.PriceSubmission{
+		StoreID:     req.StoreID,
+		ProductID:   req.ProductID,
+		Price:       req.Price,
+		Currency:    req.Currency,
+		TaxIncluded: req.TaxIncluded == nil || *req.TaxIncluded,
+		PhotoURL:    req.PhotoURL,
+		Note:        req.Note,
+	}
+	if req.ObservedAt != nil {
+		submission.ObservedAt = *req.ObservedAt
+	}
+
+	created, err := h.submissionUsecase.Submit(middleware.ContributorID(c), req.Contributor.DisplayName, submission)
+	if errors.Is(err, domain.ErrInvalidSubmission) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to create submission")
+		return
+	}
+	if created == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "store or product not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIResponse{Data: created})
+}
+
+// GetSubmissions handles GET /api/submissions
+// Query params: status (pending|approved|rejected, default: pending), store_id, contributor_id, limit, offset
+func (h *SubmissionHandler) GetSubmissions(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+	storeID, err := parseOptionalID(c, "store_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid store_id")
+		return
+	}
+	contributorID, err := parseOptionalID(c, "contributor_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid contributor_id")
+		return
+	}
+
+	submissions, err := h.submissionUsecase.List(usecase.SubmissionListOptions{
+		Status:        c.Query("status"),
+		StoreID:       storeID,
+		ContributorID: contributorID,
+		Pagination:    usecase.Pagination{Limit: limit, Offset: offset},
+	})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+
+	response.OK(c, submissions, &response.Meta{
+		Count:  len(submissions),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetSubmissionByID handles GET /api/submissions/:id
+func (h *SubmissionHandler) GetSubmissionByID(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid submission id")
+		return
+	}
+
+	submission, err := h.submissionUsecase.GetByID(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if submission == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "submission not found")
+		return
+	}
+
+	response.OK(c, submission, nil)
+}
+
+type reviewSubmissionRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+// ReviewSubmission handles POST /api/submissions/:id/review for the
+// moderator named by the X-Admin-Key header
+// Body: {"action": "approve"} or {"action": "reject", "note": "写真と価格が一致しない"}
+func (h *SubmissionHandler) ReviewSubmission(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid submission id")
+		return
+	}
+	var req reviewSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+	if req.Action != usecase.ReviewApprove && req.Action != usecase.ReviewReject {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "action must be approve or reject")
+		return
+	}
+
+	reviewed, err := h.submissionUsecase.Review(id, req.Action, req.Note, middleware.AdminName(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if reviewed == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "pending submission not found")
+		return
+	}
+
+	response.OK(c, reviewed, nil)
+}
+
+// GetContributors handles GET /api/contributors
+// Query params: limit, offset
+func (h *SubmissionHandler) GetContributors(c *gin.Context) {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid pagination")
+		return
+	}
+
+	contributors, err := h.submissionUsecase.ListContributors(usecase.Pagination{Limit: limit, Offset: offset})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	response.OK(c, contributors, &response.Meta{
+		Count:  len(contributors),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetContributorByID handles GET /api/contributors/:id
+func (h *SubmissionHandler) GetContributorByID(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid contributor id")
+		return
+	}
+
+	contributor, err := h.submissionUsecase.GetContributor(id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if contributor == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "contributor not found")
+		return
+	}
+
+	response.OK(c, contributor, nil)
+}
+
+type contributorRoleRequest struct {
+	Role    string `json:"role"`
+	StoreID int    `json:"store_id"`
+}
+
+// SetContributorRole handles PUT /api/contributors/:id/role
+// Body: {"role": "store_manager", "store_id": 1} or {"role": "shopper"}
+func (h *SubmissionHandler) SetContributorRole(c *gin.Context) {
+	id, err := parsePathID(c, "id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid contributor id")
+		return
+	}
+	var req contributorRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid request body")
+		return
+	}
+
+	contributor, err := h.submissionUsecase.SetContributorRole(id, req.Role, req.StoreID)
+	if errors.Is(err, domain.ErrInvalidContributorRole) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to update contributor")
+		return
+	}
+
+	if contributor == nil {
+		response.Error(c, http.StatusNotFound, response.ErrNotFound, "contributor or store not found")
+		return
+	}
+
+	response.OK(c, contributor, nil)
+}
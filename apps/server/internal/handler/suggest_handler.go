@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+type SuggestHandler struct {
+	suggestUsecase *usecase.SuggestUsecase
+}
+
+func NewSuggestHandler(suggestUsecase *usecase.SuggestUsecase) *SuggestHandler {
+	return &SuggestHandler{suggestUsecase: suggestUsecase}
+}
+
+// Suggest handles GET /api/suggest?q=prefix
+// Query params: q (prefix), limit (default: 8, max: 20)
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	prefix := c.Query("q")
+	if prefix == "" {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "q is required")
+		return
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.suggestUsecase.Suggest(usecase.SuggestOptions{Query: prefix, Limit: limit})
+	if errors.Is(err, domain.ErrInvalidSearch) {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, "failed to suggest")
+		return
+	}
+
+	response.OK(c, suggestions, &response.Meta{Count: len(suggestions)})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/query"
+	"github.com/price-comparison/server/internal/response"
+	"github.com/price-comparison/server/internal/usecase"
+)
+
+const mvtContentType = "application/vnd.mapbox-vector-tile"
+
+type TileHandler struct {
+	tileUsecase   *usecase.TileUsecase
+	maxAge        int
+	minConfidence float64
+}
+
+// NewTileHandler serves tiles with a Cache-Control max-age of maxAgeSeconds,
+// computing min_price from prices with at least minConfidence by default
+func NewTileHandler(tileUsecase *usecase.TileUsecase, maxAgeSeconds int, minConfidence float64) *TileHandler {
+	return &TileHandler{tileUsecase: tileUsecase, maxAge: maxAgeSeconds, minConfidence: minConfidence}
+}
+
+// GetStoreTile handles GET /tiles/stores/:z/:x/:y.mvt
+// Query params: q, category (repeatable), chain_id (repeatable), min_price, max_price, tax, min_confidence.
+// Features carry min_price for the product filter in effect.
+func (h *TileHandler) GetStoreTile(c *gin.Context) {
+	tile, err := parseTileCoord(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, err.Error())
+		return
+	}
+
+	minPrice, err := parseOptionalFloat(c, "min_price")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid min_price")
+		return
+	}
+	maxPrice, err := parseOptionalFloat(c, "max_price")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid max_price")
+		return
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "min_price must not exceed max_price")
+		return
+	}
+
+	chainIDs, err := parseIDList(c, "chain_id")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid chain_id")
+		return
+	}
+	tax, err := parseTaxMode(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid tax")
+		return
+	}
+	minConfidence, err := parseMinConfidence(c, h.minConfidence)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, response.ErrInvalidArgument, "invalid min_confidence")
+		return
+	}
+
+	mvt, err := h.tileUsecase.StoreTile(usecase.StoreTileOptions{
+		Tile:          tile,
+		Query:         c.Query("q"),
+		Categories:    parseMultiValue(c, "category"),
+		MinPrice:      minPrice,
+		MaxPrice:      maxPrice,
+		ChainIDs:      chainIDs,
+		Tax:           tax,
+		MinConfidence: minConfidence,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, response.ErrInternal, err.Error())
+		return
+	}
+
+	if h.maxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", h.maxAge))
+	}
+	c.Data(http.StatusOK, mvtContentType, mvt)
+}
+
+// parseTileCoord reads z/x/y path params; y must carry the .mvt extension
+func parseTileCoord(c *gin.Context) (query.TileCoord, error) {
+	yParam, ok := strings.CutSuffix(c.Param("y"), ".mvt")
+	if !ok {
+		return query.TileCoord{}, fmt.Errorf("tile must be requested as .mvt")
+	}
+
+	z, errZ := strconv.Atoi(c.Param("z"))
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(yParam)
+	if errZ != nil || errX != nil || errY != nil {
+		return query.TileCoord{}, fmt.Errorf("invalid tile coordinates")
+	}
+
+	tile := query.TileCoord{Z: z, X: x, Y: y}
+	if !usecase.ValidTile(tile) {
+		return query.TileCoord{}, fmt.Errorf("tile out of range")
+	}
+	return tile, nil
+}
@@ -0,0 +1,80 @@
+// Package jobs runs background work from a durable queue. Jobs are rows in
+// Postgres; a Pool of workers claims ready jobs, hands each to the Handler
+// registered for its type and retries failures with exponential backoff.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// ErrLeaseLost is returned when finishing a job whose visibility timeout
+// passed while it ran, so another worker may have claimed it since
+var ErrLeaseLost = errors.New("job lease expired before it finished")
+
+// Store is the job queue table
+type Store interface {
+	// EnqueueOnce queues a job of jobType with an empty payload unless one
+	// is already queued or running
+	EnqueueOnce(jobType string, maxAttempts int) error
+	// Claim marks the oldest ready job of one of types as running, leased
+	// for visibility, and returns it; nil when none is ready. A running job
+	// whose lease expired is ready again while it has attempts left.
+	Claim(types []string, visibility time.Duration) (*domain.Job, error)
+	Complete(job domain.Job) error
+	// Fail records message against job and requeues it to run after delay,
+	// or marks it failed when retry is false or its attempts are used up
+	Fail(job domain.Job, message string, delay time.Duration, retry bool) error
+	// FailExpired marks running jobs whose lease expired on their last
+	// attempt as failed and returns how many there were
+	FailExpired() (int, error)
+}
+
+// Handler does the work of one job. Returning an error schedules a retry
+// unless the error is Permanent.
+type Handler func(ctx context.Context, job domain.Job) error
+
+// Handle adapts fn, which takes the job payload decoded as T, to a Handler.
+// A payload that does not decode fails the job without retrying.
+func Handle[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job domain.Job) error {
+		var payload T
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return Permanent(fmt.Errorf("invalid %s payload: %w", job.Type, err))
+			}
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,226 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// Options tunes a Pool. Zero values take the defaults below.
+type Options struct {
+	Workers           int
+	PollInterval      time.Duration
+	VisibilityTimeout time.Duration
+	// Retries wait BackoffBase, doubling with each attempt up to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// MaxAttempts is given to the jobs the pool queues itself
+	MaxAttempts int
+}
+
+const (
+	defaultWorkers           = 4
+	defaultPollInterval      = time.Second
+	defaultVisibilityTimeout = 5 * time.Minute
+	defaultBackoffBase       = 30 * time.Second
+	defaultBackoffMax        = time.Hour
+	defaultMaxAttempts       = 5
+)
+
+// Pool runs queued jobs on a fixed number of workers. Each worker claims
+// only job types with a registered handler, so instances running different
+// handlers can share one queue.
+type Pool struct {
+	store    Store
+	options  Options
+	logger   *slog.Logger
+	handlers map[string]Handler
+	every    []recurringJob
+}
+
+func NewPool(store Store, options Options, logger *slog.Logger) *Pool {
+	if options.Workers <= 0 {
+		options.Workers = defaultWorkers
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultPollInterval
+	}
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = defaultVisibilityTimeout
+	}
+	if options.BackoffBase <= 0 {
+		options.BackoffBase = defaultBackoffBase
+	}
+	if options.BackoffMax < options.BackoffBase {
+		options.BackoffMax = max(defaultBackoffMax, options.BackoffBase)
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	return &Pool{store: store, options: options, logger: logger, handlers: make(map[string]Handler)}
+}
+
+type recurringJob struct {
+	jobType  string
+	interval time.Duration
+}
+
+// Register sets the handler for jobType
+func (p *Pool) Register(jobType string, handler Handler) error {
+	if jobType == "" {
+		return fmt.Errorf("job type is required")
+	}
+	if _, exists := p.handlers[jobType]; exists {
+		return fmt.Errorf("job type %q is already registered", jobType)
+	}
+	p.handlers[jobType] = handler
+	return nil
+}
+
+// Every queues a jobType job when the pool starts and then each interval,
+// unless the previous one is still queued or running. jobType must be
+// registered.
+func (p *Pool) Every(jobType string, interval time.Duration) error {
+	if !p.Handles(jobType) {
+		return fmt.Errorf("job type %q is not registered", jobType)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("interval for %q must be positive", jobType)
+	}
+	p.every = append(p.every, recurringJob{jobType: jobType, interval: interval})
+	return nil
+}
+
+// Types lists the registered job types in name order
+func (p *Pool) Types() []string {
+	types := make([]string, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Handles reports whether jobType has a registered handler
+func (p *Pool) Handles(jobType string) bool {
+	_, ok := p.handlers[jobType]
+	return ok
+}
+
+// Start runs the workers, and fails jobs whose lease expired on their last
+// attempt, until ctx is done
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.options.Workers; i++ {
+		go p.work(ctx)
+	}
+	go p.reap(ctx)
+	for _, recurring := range p.every {
+		go p.repeat(ctx, recurring)
+	}
+}
+
+func (p *Pool) work(ctx context.Context) {
+	for {
+		found, err := p.RunOne(ctx)
+		if err != nil {
+			p.logger.Error("job claim failed", "error", err)
+		}
+		if found && err == nil {
+			continue
+		}
+		if !sleep(ctx, p.options.PollInterval) {
+			return
+		}
+	}
+}
+
+func (p *Pool) reap(ctx context.Context) {
+	for sleep(ctx, p.options.PollInterval) {
+		count, err := p.store.FailExpired()
+		if err != nil {
+			p.logger.Error("failed to expire jobs", "error", err)
+		} else if count > 0 {
+			p.logger.Warn("jobs failed after their last attempt timed out", "count", count)
+		}
+	}
+}
+
+func (p *Pool) repeat(ctx context.Context, recurring recurringJob) {
+	for {
+		if err := p.store.EnqueueOnce(recurring.jobType, p.options.MaxAttempts); err != nil {
+			p.logger.Error("failed to queue recurring job", "type", recurring.jobType, "error", err)
+		}
+		if !sleep(ctx, recurring.interval) {
+			return
+		}
+	}
+}
+
+// RunOne claims a ready job and runs it, reporting whether there was one.
+// Job failures are recorded on the job rather than returned.
+func (p *Pool) RunOne(ctx context.Context) (bool, error) {
+	if len(p.handlers) == 0 {
+		return false, nil
+	}
+	job, err := p.store.Claim(p.Types(), p.options.VisibilityTimeout)
+	if err != nil || job == nil {
+		return false, err
+	}
+	p.run(ctx, *job)
+	return true, nil
+}
+
+func (p *Pool) run(ctx context.Context, job domain.Job) {
+	// The handler must give up before its lease does
+	jobCtx, cancel := context.WithTimeout(ctx, p.options.VisibilityTimeout)
+	err := p.call(jobCtx, job)
+	cancel()
+
+	if err == nil {
+		if err := p.store.Complete(job); err != nil {
+			p.logger.Error("failed to complete job", "job_id", job.ID, "type", job.Type, "error", err)
+		}
+		return
+	}
+
+	retry := !IsPermanent(err)
+	p.logger.Warn("job failed", "job_id", job.ID, "type", job.Type, "attempt", job.Attempts, "retry", retry, "error", err)
+	if err := p.store.Fail(job, err.Error(), p.Backoff(job.Attempts), retry); err != nil {
+		p.logger.Error("failed to record job failure", "job_id", job.ID, "type", job.Type, "error", err)
+	}
+}
+
+// call runs the job's handler, turning a panic into an error
+func (p *Pool) call(ctx context.Context, job domain.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return p.handlers[job.Type](ctx, job)
+}
+
+// Backoff is the delay before retrying a job that failed on attempt
+func (p *Pool) Backoff(attempt int) time.Duration {
+	delay := p.options.BackoffBase
+	for i := 1; i < attempt && delay < p.options.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, p.options.BackoffMax)
+}
+
+// sleep waits for d, reporting false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
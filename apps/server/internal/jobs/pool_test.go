@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type failure struct {
+	message string
+	delay   time.Duration
+	retry   bool
+}
+
+type storeStub struct {
+	queue     []domain.Job
+	claimed   []string
+	completed []domain.Job
+	failures  []failure
+}
+
+func (s *storeStub) EnqueueOnce(jobType string, maxAttempts int) error {
+	s.queue = append(s.queue, domain.Job{Type: jobType, MaxAttempts: maxAttempts})
+	return nil
+}
+
+func (s *storeStub) Claim(types []string, visibility time.Duration) (*domain.Job, error) {
+	s.claimed = types
+	if len(s.queue) == 0 {
+		return nil, nil
+	}
+	job := s.queue[0]
+	s.queue = s.queue[1:]
+	job.Status = domain.JobStatusRunning
+	job.Attempts++
+	return &job, nil
+}
+
+func (s *storeStub) Complete(job domain.Job) error {
+	s.completed = append(s.completed, job)
+	return nil
+}
+
+func (s *storeStub) Fail(job domain.Job, message string, delay time.Duration, retry bool) error {
+	s.failures = append(s.failures, failure{message: message, delay: delay, retry: retry})
+	return nil
+}
+
+func (s *storeStub) FailExpired() (int, error) {
+	return 0, nil
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func newTestPool(store Store) *Pool {
+	return NewPool(store, Options{BackoffBase: time.Second, BackoffMax: 10 * time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestPoolRunsTypedHandlers(t *testing.T) {
+	store := &storeStub{queue: []domain.Job{{ID: 1, Type: "greet", Payload: json.RawMessage(`{"name": "hanako"}`)}}}
+	pool := newTestPool(store)
+	var greeted string
+	_ = pool.Register("greet", Handle(func(ctx context.Context, payload greeting) error {
+		greeted = payload.Name
+		return nil
+	}))
+	_ = pool.Register("audit", Handle(func(ctx context.Context, payload greeting) error { return nil }))
+
+	found, err := pool.RunOne(context.Background())
+	if err != nil || !found {
+		t.Fatalf("expected a job to run, got found=%v err=%v", found, err)
+	}
+	if greeted != "hanako" {
+		t.Fatalf("expected the decoded payload, got %q", greeted)
+	}
+	if len(store.completed) != 1 || len(store.failures) != 0 {
+		t.Fatalf("expected the job to complete, got %+v", store)
+	}
+	if len(store.claimed) != 2 || store.claimed[0] != "audit" || store.claimed[1] != "greet" {
+		t.Fatalf("expected claims limited to registered types, got %v", store.claimed)
+	}
+
+	if found, err := pool.RunOne(context.Background()); found || err != nil {
+		t.Fatalf("expected an empty queue, got found=%v err=%v", found, err)
+	}
+}
+
+func TestPoolRetriesFailuresWithBackoff(t *testing.T) {
+	store := &storeStub{queue: []domain.Job{
+		{ID: 1, Type: "flaky", Attempts: 2},
+		{ID: 2, Type: "flaky", Payload: json.RawMessage(`{"name": 1}`)},
+		{ID: 3, Type: "panics"},
+	}}
+	pool := newTestPool(store)
+	_ = pool.Register("flaky", Handle(func(ctx context.Context, payload greeting) error {
+		return errors.New("upstream timeout")
+	}))
+	_ = pool.Register("panics", func(ctx context.Context, job domain.Job) error {
+		panic("boom")
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.RunOne(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(store.failures) != 3 {
+		t.Fatalf("expected 3 failures, got %d", len(store.failures))
+	}
+	if f := store.failures[0]; f.message != "upstream timeout" || !f.retry || f.delay != 4*time.Second {
+		t.Fatalf("expected a retry after 4s on the third attempt, got %+v", f)
+	}
+	if f := store.failures[1]; f.retry {
+		t.Fatalf("expected an undecodable payload to fail without retrying, got %+v", f)
+	}
+	if f := store.failures[2]; f.message != "job panicked: boom" || !f.retry {
+		t.Fatalf("expected a recovered panic to be retried, got %+v", f)
+	}
+}
+
+func TestPoolBackoff(t *testing.T) {
+	pool := newTestPool(&storeStub{})
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, expected := range want {
+		if got := pool.Backoff(i + 1); got != expected {
+			t.Fatalf("attempt %d: expected %s, got %s", i+1, expected, got)
+		}
+	}
+	if got := pool.Backoff(200); got != 10*time.Second {
+		t.Fatalf("expected the backoff to stay capped, got %s", got)
+	}
+}
+
+func TestPoolRejectsDuplicateTypes(t *testing.T) {
+	pool := newTestPool(&storeStub{})
+	handler := func(ctx context.Context, job domain.Job) error { return nil }
+	if err := pool.Register("greet", handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pool.Register("greet", handler); err == nil {
+		t.Fatalf("expected error for a duplicate job type")
+	}
+	if err := pool.Register("", handler); err == nil {
+		t.Fatalf("expected error for an empty job type")
+	}
+	if err := pool.Every("refresh", time.Minute); err == nil {
+		t.Fatalf("expected error scheduling an unregistered job type")
+	}
+	if err := pool.Every("greet", time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
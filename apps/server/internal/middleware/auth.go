@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"strings"
 
@@ -32,3 +35,81 @@ func APIKeyAuth(apiKey string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Context keys under which AdminAuth and ContributorAuth store who made
+// the request
+const (
+	adminNameKey     = "admin_name"
+	contributorIDKey = "contributor_external_id"
+)
+
+// AdminAuth admits requests carrying one of keys (key to moderator name) in
+// X-Admin-Key. With no keys configured the routes it guards are closed.
+func AdminAuth(keys map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := keys[c.GetHeader("X-Admin-Key")]
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, response.ErrUnauthorized, "invalid admin key")
+			c.Abort()
+			return
+		}
+
+		c.Set(adminNameKey, name)
+		c.Next()
+	}
+}
+
+// AdminName is the moderator AdminAuth admitted the request for
+func AdminName(c *gin.Context) string {
+	return c.GetString(adminNameKey)
+}
+
+// ContributorAuth admits requests carrying a contributor token in
+// X-Contributor-Token: "<external_id>.<signature>", where signature is the
+// hex HMAC-SHA256 of external_id under secret. The app's backend issues
+// tokens to its signed-in users, so the contributor a submission is
+// credited to is never taken from the request body. With no secret
+// configured the routes it guards are closed.
+func ContributorAuth(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		externalID, ok := verifyContributorToken(secret, c.GetHeader("X-Contributor-Token"))
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, response.ErrUnauthorized, "invalid contributor token")
+			c.Abort()
+			return
+		}
+
+		c.Set(contributorIDKey, externalID)
+		c.Next()
+	}
+}
+
+// ContributorID is the external ID of the contributor ContributorAuth
+// admitted the request for
+func ContributorID(c *gin.Context) string {
+	return c.GetString(contributorIDKey)
+}
+
+// SignContributorToken issues the token ContributorAuth accepts for
+// externalID
+func SignContributorToken(secret, externalID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(externalID))
+	return externalID + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifyContributorToken(secret, token string) (string, bool) {
+	if secret == "" {
+		return "", false
+	}
+	dot := strings.LastIndex(token, ".")
+	if dot <= 0 {
+		return "", false
+	}
+	externalID := token[:dot]
+	expected := SignContributorToken(secret, externalID)
+	if !hmac.Equal([]byte(expected), []byte(token)) {
+		return "", false
+	}
+	return externalID, true
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import "testing"
+
+func TestVerifyContributorToken(t *testing.T) {
+	token := SignContributorToken("secret", "app.user-1")
+
+	externalID, ok := verifyContributorToken("secret", token)
+	if !ok || externalID != "app.user-1" {
+		t.Fatalf("expected app.user-1 from a signed token, got %q (%t)", externalID, ok)
+	}
+
+	forged := "app-user-2" + token[len("app.user-1"):]
+	for _, invalid := range []string{"", "app.user-1", forged, token + "0"} {
+		if _, ok := verifyContributorToken("secret", invalid); ok {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+	if _, ok := verifyContributorToken("", token); ok {
+		t.Fatalf("expected every token to be rejected without a secret")
+	}
+}
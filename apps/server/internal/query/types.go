@@ -1,5 +1,7 @@
 package query
 
+import "time"
+
 type Bounds struct {
 	MinLat float64
 	MinLon float64
@@ -12,9 +14,83 @@ type GeoPoint struct {
 	Lon float64
 }
 
+// TaxMode selects whether price amounts include consumption tax. Stored
+// prices are tax-inclusive; the zero value means TaxIncluded.
+type TaxMode string
+
+const (
+	TaxIncluded TaxMode = "included"
+	TaxExcluded TaxMode = "excluded"
+)
+
+// Included reports whether amounts include tax
+func (t TaxMode) Included() bool {
+	return t != TaxExcluded
+}
+
+// StoreFilters narrows store listings. Multi-value fields are OR-ed within
+// the field (any of the categories) except ProductIDs, where a store must
+// stock every listed product; separate fields are AND-ed together.
 type StoreFilters struct {
-	Query        string
-	Category     string
-	Bounds       *Bounds
+	Query              string
+	Categories         []string
+	MinPrice           *float64
+	MaxPrice           *float64
+	ProductIDs         []int
+	ChainIDs           []int
+	RecordedWithinDays int
+	// Currency converts prices before computing min_price and applying the
+	// price range; empty compares prices as recorded
+	Currency string
+	// Tax selects tax-inclusive or tax-exclusive prices for min_price and
+	// the price range
+	Tax TaxMode
+	// MinConfidence leaves prices with a lower confidence out of min_price
+	// and the price range; 0 counts every price
+	MinConfidence float64
+	// Purchase prices min_price and the price range as effective prices,
+	// after quantity discounts and the latest price's active promotions
+	Purchase Purchase
+	OpenAt   *time.Time
+	Bounds   *Bounds
+	// AreaGeoJSON is a validated GeoJSON MultiPolygon; AreaID refers to a
+	// saved area. Stores must fall inside both when both are set.
+	AreaGeoJSON  string
+	AreaID       int
 	UserLocation *GeoPoint
 }
+
+// HasPriceFilters reports whether the store must have matching price rows
+func (f StoreFilters) HasPriceFilters() bool {
+	return f.Query != "" ||
+		len(f.Categories) > 0 ||
+		f.MinPrice != nil ||
+		f.MaxPrice != nil ||
+		f.RecordedWithinDays > 0
+}
+
+type ProductSearchFilters struct {
+	Keyword  string
+	Category string
+	MinPrice *float64
+	MaxPrice *float64
+}
+
+// NearbyFilters narrows radius searches
+type NearbyFilters struct {
+	OpenAt *time.Time
+}
+
+// TileCoord addresses a Web Mercator (XYZ) map tile
+type TileCoord struct {
+	Z int
+	X int
+	Y int
+}
+
+// Purchase describes the basket a price comparison is for: promotions are
+// applied to Quantity units, and member-only promotions only when Member
+type Purchase struct {
+	Quantity int
+	Member   bool
+}
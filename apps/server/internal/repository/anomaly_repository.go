@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// AnomalyRepository reviews prices flagged by the prices_screen_anomaly
+// trigger
+type AnomalyRepository struct {
+	db *sql.DB
+}
+
+func NewAnomalyRepository(db *sql.DB) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+const anomalyColumns = `
+	p.id, p.store_id, p.product_id, p.price, p.currency, p.posted_price, p.tax_included, p.tax_rate,
+	p.recorded_at, p.created_at, p.source_type, p.contributor_id, p.source,
+	price_confidence(p.base_confidence, p.recorded_at), p.status, p.anomaly_score, p.baseline_price, p.reviewed_at, p.reviewed_by,
+	s.id, s.name, s.address, pr.id, pr.name, pr.category, pr.barcode
+`
+
+// FindByStatus lists held-back prices, most anomalous first, optionally for
+// one product
+func (r *AnomalyRepository) FindByStatus(status string, productID int, limit, offset int) ([]domain.PriceAnomaly, error) {
+	args := &argList{}
+	where := fmt.Sprintf("WHERE p.status = %s", args.add(status))
+	if productID > 0 {
+		where += fmt.Sprintf(" AND p.product_id = %s", args.add(productID))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM prices p
+		INNER JOIN stores s ON p.store_id = s.id
+		INNER JOIN products pr ON p.product_id = pr.id
+		%s
+		ORDER BY abs(p.anomaly_score) DESC NULLS LAST, p.recorded_at DESC
+		LIMIT %s OFFSET %s
+	`, anomalyColumns, where, args.add(limit), args.add(offset))
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	var anomalies []domain.PriceAnomaly
+	for rows.Next() {
+		anomaly, err := scanPriceAnomaly(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price anomaly: %w", err)
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	return anomalies, nil
+}
+
+// Review moves a quarantined price to status (active or rejected); a
+// rejected crowdsourced price counts against its contributor's reputation.
+// reviewer is recorded as reviewed_by. It returns nil when no quarantined
+// price has the ID.
+func (r *AnomalyRepository) Review(id int, status, reviewer string) (*domain.PriceAnomaly, error) {
+	query := fmt.Sprintf(`
+		WITH reviewed AS (
+			UPDATE prices
+			SET status = $2, reviewed_at = CURRENT_TIMESTAMP, reviewed_by = $3
+			WHERE id = $1 AND status = 'quarantined'
+			RETURNING *
+		),
+		penalized AS (
+			UPDATE contributors c
+			SET rejected_count = c.rejected_count + 1,
+				reputation = contributor_reputation(c.approved_count, c.rejected_count + 1),
+				updated_at = CURRENT_TIMESTAMP
+			FROM reviewed
+			WHERE c.id = reviewed.contributor_id AND reviewed.status = 'rejected'
+		)
+		SELECT %s
+		FROM reviewed p
+		INNER JOIN stores s ON p.store_id = s.id
+		INNER JOIN products pr ON p.product_id = pr.id
+	`, anomalyColumns)
+
+	anomaly, err := scanPriceAnomaly(r.db.QueryRow(query, id, status, nullableString(reviewer)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to review price: %w", err)
+	}
+	return &anomaly, nil
+}
+
+func scanPriceAnomaly(row rowScanner) (domain.PriceAnomaly, error) {
+	var anomaly domain.PriceAnomaly
+	var store domain.Store
+	var product domain.Product
+	var score sql.NullFloat64
+	var baseline sql.NullFloat64
+	var reviewedAt sql.NullTime
+	var reviewedBy sql.NullString
+	var provenance provenanceValues
+	err := row.Scan(
+		&anomaly.ID,
+		&anomaly.StoreID,
+		&anomaly.ProductID,
+		&anomaly.Price.Price,
+		&anomaly.Currency,
+		&anomaly.PostedPrice,
+		&anomaly.PostedTaxIncluded,
+		&anomaly.TaxRate,
+		&anomaly.RecordedAt,
+		&anomaly.CreatedAt,
+		&provenance.sourceType,
+		&provenance.contributorID,
+		&provenance.source,
+		&provenance.confidence,
+		&anomaly.Status,
+		&score,
+		&baseline,
+		&reviewedAt,
+		&reviewedBy,
+		&store.ID,
+		&store.Name,
+		&store.Address,
+		&product.ID,
+		&product.Name,
+		&product.Category,
+		&product.Barcode,
+	)
+	if err != nil {
+		return anomaly, err
+	}
+	anomaly.TaxIncluded = true
+	provenance.apply(&anomaly.Price)
+	if score.Valid {
+		anomaly.AnomalyScore = &score.Float64
+	}
+	if baseline.Valid {
+		anomaly.BaselinePrice = &baseline.Float64
+	}
+	if reviewedAt.Valid {
+		anomaly.ReviewedAt = &reviewedAt.Time
+	}
+	anomaly.ReviewedBy = reviewedBy.String
+	anomaly.Store = &store
+	anomaly.Product = &product
+	return anomaly, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type AreaRepository struct {
+	db *sql.DB
+}
+
+func NewAreaRepository(db *sql.DB) *AreaRepository {
+	return &AreaRepository{db: db}
+}
+
+// Create stores a validated GeoJSON MultiPolygon, repairing self-intersections
+func (r *AreaRepository) Create(name, geoJSON string) (*domain.Area, error) {
+	query := `
+		INSERT INTO areas (name, geom)
+		VALUES ($1, ST_Multi(ST_CollectionExtract(ST_MakeValid(ST_SetSRID(ST_GeomFromGeoJSON($2), 4326)), 3)))
+		RETURNING id, name, ST_AsGeoJSON(geom), created_at
+	`
+
+	area, err := scanArea(r.db.QueryRow(query, name, geoJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create area: %w", err)
+	}
+	return &area, nil
+}
+
+// FindAll returns saved areas
+func (r *AreaRepository) FindAll(limit, offset int) ([]domain.Area, error) {
+	query := `
+		SELECT id, name, ST_AsGeoJSON(geom), created_at
+		FROM areas
+		ORDER BY name
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query areas: %w", err)
+	}
+	defer rows.Close()
+
+	var areas []domain.Area
+	for rows.Next() {
+		area, err := scanArea(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan area: %w", err)
+		}
+		areas = append(areas, area)
+	}
+
+	return areas, nil
+}
+
+// FindByID finds an area by its ID
+func (r *AreaRepository) FindByID(id int) (*domain.Area, error) {
+	query := `
+		SELECT id, name, ST_AsGeoJSON(geom), created_at
+		FROM areas
+		WHERE id = $1
+	`
+
+	area, err := scanArea(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find area: %w", err)
+	}
+	return &area, nil
+}
+
+func scanArea(row rowScanner) (domain.Area, error) {
+	var area domain.Area
+	var geometry string
+	if err := row.Scan(&area.ID, &area.Name, &geometry, &area.CreatedAt); err != nil {
+		return area, err
+	}
+	area.Geometry = []byte(geometry)
+	return area, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type ChainRepository struct {
+	db *sql.DB
+}
+
+func NewChainRepository(db *sql.DB) *ChainRepository {
+	return &ChainRepository{db: db}
+}
+
+// FindAll returns all chains with their branch counts
+func (r *ChainRepository) FindAll(limit, offset int) ([]domain.Chain, error) {
+	query := `
+		SELECT c.id, c.name, c.slug, c.logo_url, c.website_url, COUNT(s.id)
+		FROM chains c
+		LEFT JOIN stores s ON s.chain_id = c.id
+		GROUP BY c.id
+		ORDER BY c.name
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chains: %w", err)
+	}
+	defer rows.Close()
+
+	var chains []domain.Chain
+	for rows.Next() {
+		chain, err := scanChain(rows)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains, nil
+}
+
+// FindByID finds a chain by its ID
+func (r *ChainRepository) FindByID(id int) (*domain.Chain, error) {
+	query := `
+		SELECT c.id, c.name, c.slug, c.logo_url, c.website_url, COUNT(s.id)
+		FROM chains c
+		LEFT JOIN stores s ON s.chain_id = c.id
+		WHERE c.id = $1
+		GROUP BY c.id
+	`
+
+	chain, err := scanChain(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &chain, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanChain(row rowScanner) (domain.Chain, error) {
+	var chain domain.Chain
+	var logoURL, websiteURL sql.NullString
+	var storeCount int
+	err := row.Scan(&chain.ID, &chain.Name, &chain.Slug, &logoURL, &websiteURL, &storeCount)
+	if err == sql.ErrNoRows {
+		return chain, err
+	}
+	if err != nil {
+		return chain, fmt.Errorf("failed to scan chain: %w", err)
+	}
+	chain.LogoURL = logoURL.String
+	chain.WebsiteURL = websiteURL.String
+	chain.StoreCount = &storeCount
+	return chain, nil
+}
+
+// chainColumns and chainJoin embed a store's chain into store queries that
+// alias stores as "s"; scan the columns into chainColumnValues
+const (
+	chainColumns = "c.id, c.name, c.slug, c.logo_url"
+	chainJoin    = "LEFT JOIN chains c ON c.id = s.chain_id"
+)
+
+type chainColumnValues struct {
+	id      sql.NullInt64
+	name    sql.NullString
+	slug    sql.NullString
+	logoURL sql.NullString
+}
+
+func (v chainColumnValues) toDomain() *domain.Chain {
+	if !v.id.Valid {
+		return nil
+	}
+	return &domain.Chain{
+		ID:      int(v.id.Int64),
+		Name:    v.name.String,
+		Slug:    v.slug.String,
+		LogoURL: v.logoURL.String,
+	}
+}
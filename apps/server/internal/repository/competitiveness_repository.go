@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// CompetitivenessRepository maintains and ranks the store competitiveness
+// scores in store_competitiveness
+type CompetitivenessRepository struct {
+	db *sql.DB
+}
+
+func NewCompetitivenessRepository(db *sql.DB) *CompetitivenessRepository {
+	return &CompetitivenessRepository{db: db}
+}
+
+// Refresh recomputes every score and returns how many stores were scored.
+// It returns 0 while another refresh is running.
+func (r *CompetitivenessRepository) Refresh(radiusMeters float64, windowDays, minStores, minProducts int) (int, error) {
+	var scored int
+	err := r.db.QueryRow(
+		"SELECT refresh_store_competitiveness($1, $2, $3, $4)",
+		radiusMeters, windowDays, minStores, minProducts,
+	).Scan(&scored)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh store competitiveness: %w", err)
+	}
+	return scored, nil
+}
+
+// FindRanking ranks the scored stores inside an area, cheapest first
+func (r *CompetitivenessRepository) FindRanking(areaID int, limit, offset int) ([]domain.StoreRanking, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			RANK() OVER (ORDER BY sc.score),
+			s.id,
+			s.name,
+			s.address,
+			s.phone,
+			ST_Y(s.location::geometry) as latitude,
+			ST_X(s.location::geometry) as longitude,
+			s.timezone,
+			%s,
+			s.created_at,
+			s.updated_at,
+			sc.score,
+			sc.products_compared,
+			sc.computed_at
+		FROM store_competitiveness sc
+		JOIN stores s ON s.id = sc.store_id
+		%s
+		WHERE EXISTS (SELECT 1 FROM areas a WHERE a.id = $1 AND ST_Intersects(a.geom, s.location::geometry))
+		ORDER BY sc.score, s.id
+		LIMIT $2 OFFSET $3
+	`, chainColumns, chainJoin)
+
+	rows, err := r.db.Query(query, areaID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store ranking: %w", err)
+	}
+	defer rows.Close()
+
+	rankings := []domain.StoreRanking{}
+	for rows.Next() {
+		var ranking domain.StoreRanking
+		var phone sql.NullString
+		var chain chainColumnValues
+		err := rows.Scan(
+			&ranking.Rank,
+			&ranking.Store.ID,
+			&ranking.Store.Name,
+			&ranking.Store.Address,
+			&phone,
+			&ranking.Store.Latitude,
+			&ranking.Store.Longitude,
+			&ranking.Store.Timezone,
+			&chain.id,
+			&chain.name,
+			&chain.slug,
+			&chain.logoURL,
+			&ranking.Store.CreatedAt,
+			&ranking.Store.UpdatedAt,
+			&ranking.Score,
+			&ranking.ProductsCompared,
+			&ranking.ComputedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store ranking: %w", err)
+		}
+		ranking.Store.Phone = phone.String
+		ranking.Store.Chain = chain.toDomain()
+		score := ranking.Score
+		ranking.Store.Competitiveness = &score
+		rankings = append(rankings, ranking)
+	}
+
+	return rankings, rows.Err()
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/connector"
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// ingestBatchSize bounds the observations inserted per statement
+const ingestBatchSize = 1000
+
+// ConnectorRepository stores connector run history and the prices that
+// connectors observe
+type ConnectorRepository struct {
+	db *sql.DB
+}
+
+func NewConnectorRepository(db *sql.DB) *ConnectorRepository {
+	return &ConnectorRepository{db: db}
+}
+
+const connectorRunColumns = `
+	id, connector, trigger, status, rows_read, rows_inserted, rows_skipped, error, started_at, finished_at
+`
+
+func (r *ConnectorRepository) StartRun(connectorName, trigger string) (*domain.ConnectorRun, error) {
+	run, err := scanConnectorRun(r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO connector_runs (connector, trigger)
+		VALUES ($1, $2)
+		RETURNING %s
+	`, connectorRunColumns), connectorName, trigger))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start connector run: %w", err)
+	}
+	return &run, nil
+}
+
+func (r *ConnectorRepository) FinishRun(run domain.ConnectorRun) error {
+	_, err := r.db.Exec(`
+		UPDATE connector_runs
+		SET status = $2, rows_read = $3, rows_inserted = $4, rows_skipped = $5, error = $6, finished_at = $7
+		WHERE id = $1
+	`, run.ID, run.Status, run.RowsRead, run.RowsInserted, run.RowsSkipped, nullableString(run.Error), run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish connector run: %w", err)
+	}
+	return nil
+}
+
+// Ingest inserts observations as scraped prices from source. Observations
+// for unknown stores or products, and those duplicating a recorded price
+// (same store, product and time), are skipped.
+func (r *ConnectorRepository) Ingest(source string, observations []connector.Observation) (int, error) {
+	inserted := 0
+	for start := 0; start < len(observations); start += ingestBatchSize {
+		end := start + ingestBatchSize
+		if end > len(observations) {
+			end = len(observations)
+		}
+		count, err := r.ingestBatch(source, observations[start:end])
+		if err != nil {
+			return inserted, err
+		}
+		inserted += count
+	}
+	return inserted, nil
+}
+
+func (r *ConnectorRepository) ingestBatch(source string, observations []connector.Observation) (int, error) {
+	n := len(observations)
+	storeIDs := make([]int64, n)
+	productIDs := make([]int64, n)
+	barcodes := make([]string, n)
+	prices := make([]float64, n)
+	currencies := make([]string, n)
+	taxIncluded := make([]bool, n)
+	recordedAt := make([]string, n)
+	for i, observation := range observations {
+		storeIDs[i] = int64(observation.StoreID)
+		productIDs[i] = int64(observation.ProductID)
+		barcodes[i] = observation.Barcode
+		prices[i] = observation.Price
+		currencies[i] = observation.Currency
+		taxIncluded[i] = observation.TaxIncluded
+		// recorded_at is a UTC timestamp without time zone; casting a
+		// string with an offset to timestamp would drop the offset
+		recordedAt[i] = observation.RecordedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	var count int
+	err := r.db.QueryRow(`
+		WITH input AS (
+			SELECT *
+			FROM unnest($1::int[], $2::int[], $3::text[], $4::numeric[], $5::text[], $6::bool[], $7::timestamp[])
+				AS t(store_id, product_id, barcode, price, currency, tax_included, recorded_at)
+		),
+		resolved AS (
+			SELECT
+				i.store_id,
+				CASE
+					WHEN i.product_id > 0 THEN (SELECT id FROM products WHERE id = i.product_id)
+					ELSE (SELECT id FROM products WHERE barcode = i.barcode ORDER BY id LIMIT 1)
+				END AS product_id,
+				i.price, i.currency, i.tax_included, i.recorded_at
+			FROM input i
+			WHERE EXISTS (SELECT 1 FROM stores s WHERE s.id = i.store_id)
+		),
+		inserted AS (
+			INSERT INTO prices (store_id, product_id, price, currency, tax_included, recorded_at, source_type, source)
+			SELECT store_id, product_id, price, currency, tax_included, recorded_at, 'scraper', $8
+			FROM resolved
+			WHERE product_id IS NOT NULL
+			ON CONFLICT (store_id, product_id, recorded_at) DO NOTHING
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM inserted
+	`,
+		pq.Array(storeIDs),
+		pq.Array(productIDs),
+		pq.Array(barcodes),
+		pq.Array(prices),
+		pq.Array(currencies),
+		pq.Array(taxIncluded),
+		pq.Array(recordedAt),
+		source,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to ingest observations: %w", err)
+	}
+	return count, nil
+}
+
+// FindRuns lists runs, newest first, optionally for one connector
+func (r *ConnectorRepository) FindRuns(connectorName string, limit, offset int) ([]domain.ConnectorRun, error) {
+	args := &argList{}
+	where := ""
+	if connectorName != "" {
+		where = fmt.Sprintf("WHERE connector = %s", args.add(connectorName))
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM connector_runs
+		%s
+		ORDER BY started_at DESC, id DESC
+		LIMIT %s OFFSET %s
+	`, connectorRunColumns, where, args.add(limit), args.add(offset)), args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connector runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []domain.ConnectorRun
+	for rows.Next() {
+		run, err := scanConnectorRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connector run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read connector runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func scanConnectorRun(row rowScanner) (domain.ConnectorRun, error) {
+	var run domain.ConnectorRun
+	var runError sql.NullString
+	var finishedAt sql.NullTime
+	err := row.Scan(
+		&run.ID,
+		&run.Connector,
+		&run.Trigger,
+		&run.Status,
+		&run.RowsRead,
+		&run.RowsInserted,
+		&run.RowsSkipped,
+		&runError,
+		&run.StartedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return run, err
+	}
+	run.Error = runError.String
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return run, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -36,3 +37,10 @@ func NewDatabase(cfg Config) (*sql.DB, error) {
 	log.Println("Successfully connected to database")
 	return db, nil
 }
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
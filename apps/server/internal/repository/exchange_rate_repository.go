@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// FindAll lists rates newest first, optionally restricted to one base and/or
+// quote currency
+func (r *ExchangeRateRepository) FindAll(base, quote string, limit, offset int) ([]domain.ExchangeRate, error) {
+	args := &argList{}
+	where := "WHERE true"
+	if base != "" {
+		where += fmt.Sprintf(" AND base_currency = %s", args.add(base))
+	}
+	if quote != "" {
+		where += fmt.Sprintf(" AND quote_currency = %s", args.add(quote))
+	}
+	limitArg := args.add(limit)
+	offsetArg := args.add(offset)
+
+	query := fmt.Sprintf(`
+		SELECT base_currency, quote_currency, rate_date, rate, COALESCE(source, '')
+		FROM exchange_rates
+		%s
+		ORDER BY rate_date DESC, base_currency, quote_currency
+		LIMIT %s OFFSET %s
+	`, where, limitArg, offsetArg)
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []domain.ExchangeRate{}
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.BaseCurrency, &rate.QuoteCurrency, &rate.RateDate, &rate.Rate, &rate.Source); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Upsert stores a rate, replacing any rate for the same pair and date
+func (r *ExchangeRateRepository) Upsert(rate domain.ExchangeRate) error {
+	query := `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate_date, rate, source)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (base_currency, quote_currency, rate_date)
+		DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+	`
+	if _, err := r.db.Exec(query, rate.BaseCurrency, rate.QuoteCurrency, rate.RateDate, rate.Rate, rate.Source); err != nil {
+		return fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	return nil
+}
+
+// Convert converts amount with the rate in effect on date; nil means no rate
+// is available for the pair
+func (r *ExchangeRateRepository) Convert(amount float64, from, to string, date time.Time) (*float64, error) {
+	var converted sql.NullFloat64
+	err := r.db.QueryRow("SELECT convert_price($1, $2, $3, $4::date)", amount, from, to, date).Scan(&converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert amount: %w", err)
+	}
+	if !converted.Valid {
+		return nil, nil
+	}
+	return &converted.Float64, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+// ForecastRepository reads the daily price series that forecasts are
+// fitted to from the daily product rollups
+type ForecastRepository struct {
+	db *sql.DB
+}
+
+func NewForecastRepository(db *sql.DB) *ForecastRepository {
+	return &ForecastRepository{db: db}
+}
+
+// FindDailyPrices averages a product's prices at a store per day and
+// currency from the day from onwards, oldest first
+func (r *ForecastRepository) FindDailyPrices(storeID, productID int, from time.Time, tax query.TaxMode) ([]domain.PeriodPrice, error) {
+	measure := "price"
+	if !tax.Included() {
+		measure = "excl"
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT d.day, d.product_id, d.currency, d.%s_sum / d.price_count, d.price_count
+		FROM price_daily_product d
+		WHERE d.store_id = $1 AND d.product_id = $2 AND d.day >= $3::date
+		ORDER BY d.day, d.currency
+	`, measure), storeID, productID, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []domain.PeriodPrice
+	for rows.Next() {
+		var price domain.PeriodPrice
+		if err := rows.Scan(&price.PeriodStart, &price.ProductID, &price.Currency, &price.AvgPrice, &price.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily price: %w", err)
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
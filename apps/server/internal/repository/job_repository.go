@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/jobs"
+)
+
+// JobRepository is the background job queue
+type JobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `
+	id, type, payload, status, attempts, max_attempts, run_at, locked_until,
+	last_error, created_at, updated_at, finished_at
+`
+
+// Enqueue adds job to the queue, to run at job.RunAt or now when zero
+func (r *JobRepository) Enqueue(job domain.Job) (*domain.Job, error) {
+	// lib/pq would send []byte as bytea, which jsonb does not accept
+	payload := string(job.Payload)
+	if payload == "" {
+		payload = "{}"
+	}
+	var runAt interface{}
+	if !job.RunAt.IsZero() {
+		runAt = job.RunAt
+	}
+
+	created, err := scanJob(r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO jobs (type, payload, run_at, max_attempts)
+		VALUES ($1, $2::jsonb, COALESCE($3::timestamptz::timestamp, CURRENT_TIMESTAMP), $4)
+		RETURNING %s
+	`, jobColumns), job.Type, payload, runAt, job.MaxAttempts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return &created, nil
+}
+
+// EnqueueOnce queues an empty-payload job of jobType unless one is already
+// waiting or running. Two callers racing may both queue one, which is
+// harmless for the periodic jobs that use it.
+func (r *JobRepository) EnqueueOnce(jobType string, maxAttempts int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO jobs (type, max_attempts)
+		SELECT $1::text, $2::int
+		WHERE NOT EXISTS (
+			SELECT 1 FROM jobs WHERE type = $1 AND status IN ('queued', 'running')
+		)
+	`, jobType, maxAttempts)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// Claim leases the oldest ready job of one of types. SKIP LOCKED lets
+// concurrent workers pass over rows another worker is claiming.
+func (r *JobRepository) Claim(types []string, visibility time.Duration) (*domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(fmt.Sprintf(`
+		UPDATE jobs
+		SET status = 'running',
+			attempts = attempts + 1,
+			locked_until = CURRENT_TIMESTAMP + $2::float8 * interval '1 second',
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id
+			FROM jobs
+			WHERE type = ANY($1)
+				AND (
+					(status = 'queued' AND run_at <= CURRENT_TIMESTAMP)
+					OR (status = 'running' AND locked_until < CURRENT_TIMESTAMP AND attempts < max_attempts)
+				)
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, jobColumns), pq.Array(types), visibility.Seconds()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return &job, nil
+}
+
+// Complete marks a claimed job succeeded. The attempt number fences out a
+// worker whose lease expired and whose job was claimed again.
+func (r *JobRepository) Complete(job domain.Job) error {
+	result, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = 'succeeded', locked_until = NULL, updated_at = CURRENT_TIMESTAMP, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'running' AND attempts = $2
+	`, job.ID, job.Attempts)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return leaseHeld(result)
+}
+
+func (r *JobRepository) Fail(job domain.Job, message string, delay time.Duration, retry bool) error {
+	result, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = CASE WHEN $3 AND attempts < max_attempts THEN 'queued' ELSE 'failed' END,
+			run_at = CASE
+				WHEN $3 AND attempts < max_attempts THEN CURRENT_TIMESTAMP + $4::float8 * interval '1 second'
+				ELSE run_at
+			END,
+			finished_at = CASE WHEN $3 AND attempts < max_attempts THEN NULL ELSE CURRENT_TIMESTAMP END,
+			last_error = $5,
+			locked_until = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'running' AND attempts = $2
+	`, job.ID, job.Attempts, retry, delay.Seconds(), message)
+	if err != nil {
+		return fmt.Errorf("failed to fail job: %w", err)
+	}
+	return leaseHeld(result)
+}
+
+func (r *JobRepository) FailExpired() (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = 'failed',
+			last_error = 'visibility timeout expired on the last attempt',
+			locked_until = NULL,
+			updated_at = CURRENT_TIMESTAMP,
+			finished_at = CURRENT_TIMESTAMP
+		WHERE status = 'running' AND locked_until < CURRENT_TIMESTAMP AND attempts >= max_attempts
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire jobs: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire jobs: %w", err)
+	}
+	return int(count), nil
+}
+
+// FindAll lists jobs, newest first, optionally narrowed to a status and type
+func (r *JobRepository) FindAll(status, jobType string, limit, offset int) ([]domain.Job, error) {
+	args := &argList{}
+	var conditions []string
+	if status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = %s", args.add(status)))
+	}
+	if jobType != "" {
+		conditions = append(conditions, fmt.Sprintf("type = %s", args.add(jobType)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM jobs
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %s OFFSET %s
+	`, jobColumns, where, args.add(limit), args.add(offset)), args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var found []domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		found = append(found, job)
+	}
+
+	return found, nil
+}
+
+// FindByID finds a job by its ID, returning nil when missing
+func (r *JobRepository) FindByID(id int64) (*domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		FROM jobs
+		WHERE id = $1
+	`, jobColumns), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+	return &job, nil
+}
+
+// Retry requeues a failed job to run now with a fresh set of attempts. It
+// returns nil when no failed job has the ID.
+func (r *JobRepository) Retry(id int64) (*domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(fmt.Sprintf(`
+		UPDATE jobs
+		SET status = 'queued', attempts = 0, run_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP, finished_at = NULL
+		WHERE id = $1 AND status = 'failed'
+		RETURNING %s
+	`, jobColumns), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+	return &job, nil
+}
+
+// CountByStatus counts jobs by type and status
+func (r *JobRepository) CountByStatus() ([]domain.JobCount, error) {
+	rows, err := r.db.Query(`
+		SELECT type, status, COUNT(*)
+		FROM jobs
+		GROUP BY type, status
+		ORDER BY type, status
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.JobCount
+	for rows.Next() {
+		var count domain.JobCount
+		if err := rows.Scan(&count.Type, &count.Status, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan job count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func leaseHeld(result sql.Result) error {
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return jobs.ErrLeaseLost
+	}
+	return nil
+}
+
+func scanJob(row rowScanner) (domain.Job, error) {
+	var job domain.Job
+	var payload []byte
+	var lockedUntil sql.NullTime
+	var lastError sql.NullString
+	var finishedAt sql.NullTime
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&lockedUntil,
+		&lastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return job, err
+	}
+	job.Payload = json.RawMessage(payload)
+	if lockedUntil.Valid {
+		job.LockedUntil = &lockedUntil.Time
+	}
+	job.LastError = lastError.String
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
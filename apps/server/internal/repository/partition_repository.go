@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/archive"
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// PartitionRepository manages the monthly partitions of the prices table
+type PartitionRepository struct {
+	db *sql.DB
+}
+
+func NewPartitionRepository(db *sql.DB) *PartitionRepository {
+	return &PartitionRepository{db: db}
+}
+
+// EnsurePartitions creates the partitions for this month and monthsAhead
+// following ones, and for months with prices waiting in the default
+// partition, returning how many it created
+func (r *PartitionRepository) EnsurePartitions(monthsAhead int) (int, error) {
+	var created int
+	if err := r.db.QueryRow("SELECT ensure_price_partitions($1)", monthsAhead).Scan(&created); err != nil {
+		return 0, fmt.Errorf("failed to create price partitions: %w", err)
+	}
+	return created, nil
+}
+
+// FindPartitions lists the monthly partitions, oldest first
+func (r *PartitionRepository) FindPartitions() ([]domain.PricePartition, error) {
+	rows, err := r.db.Query("SELECT partition_name, range_start, range_end FROM price_partitions()")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []domain.PricePartition
+	for rows.Next() {
+		var partition domain.PricePartition
+		if err := rows.Scan(&partition.Name, &partition.RangeStart, &partition.RangeEnd); err != nil {
+			return nil, fmt.Errorf("failed to scan price partition: %w", err)
+		}
+		partitions = append(partitions, partition)
+	}
+
+	return partitions, rows.Err()
+}
+
+// ArchivePartition writes every price in partition to file as a JSON line,
+// commits the file, then detaches and drops the partition and records the
+// archive. Writes to the partition are blocked until it is gone; the daily
+// rollups keep the month's statistics.
+func (r *PartitionRepository) ArchivePartition(partition domain.PricePartition, file *archive.File) (*domain.PriceArchive, error) {
+	table := pq.QuoteIdentifier(partition.Name)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin archive: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf("LOCK TABLE %s IN SHARE MODE", table)); err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", partition.Name, err)
+	}
+
+	rowCount, err := writePartition(tx, table, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", partition.Name, err)
+	}
+	if err := file.Commit(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE prices DETACH PARTITION %s", table)); err != nil {
+		return nil, fmt.Errorf("failed to detach %s: %w", partition.Name, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s", table)); err != nil {
+		return nil, fmt.Errorf("failed to drop %s: %w", partition.Name, err)
+	}
+
+	archived := domain.PriceArchive{}
+	err = tx.QueryRow(`
+		INSERT INTO price_archives (partition_name, range_start, range_end, row_count, path)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, partition_name, range_start, range_end, row_count, path, archived_at
+	`, partition.Name, partition.RangeStart, partition.RangeEnd, rowCount, file.Path()).Scan(
+		&archived.ID,
+		&archived.PartitionName,
+		&archived.RangeStart,
+		&archived.RangeEnd,
+		&archived.RowCount,
+		&archived.Path,
+		&archived.ArchivedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record archive of %s: %w", partition.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit archive of %s: %w", partition.Name, err)
+	}
+	return &archived, nil
+}
+
+func writePartition(tx *sql.Tx, table string, file *archive.File) (int64, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT row_to_json(p)::text FROM %s p ORDER BY p.recorded_at, p.id", table))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		var line []byte
+		if err := rows.Scan(&line); err != nil {
+			return count, err
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/geocode"
+)
+
+const (
+	// PostalSource labels results produced by the offline postal dataset
+	PostalSource = "postal"
+	// reverseGeocodeMaxMeters bounds how far a point may be from the nearest
+	// postal-code centroid
+	reverseGeocodeMaxMeters = 3000
+)
+
+// PostalCodeRepository geocodes against the imported postal_codes table
+type PostalCodeRepository struct {
+	db *sql.DB
+}
+
+func NewPostalCodeRepository(db *sql.DB) *PostalCodeRepository {
+	return &PostalCodeRepository{db: db}
+}
+
+const postalCodeColumns = `
+	postal_code,
+	prefecture,
+	city,
+	town,
+	ST_Y(location::geometry) as latitude,
+	ST_X(location::geometry) as longitude
+`
+
+// Geocode resolves a postal code exactly, or an address by the most
+// specific town it starts with, falling back to towns containing it
+func (r *PostalCodeRepository) Geocode(ctx context.Context, address string, limit int) ([]domain.GeocodeResult, error) {
+	if postalCode, ok := geocode.NormalizePostalCode(address); ok {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM postal_codes
+			WHERE postal_code = $1
+			ORDER BY town
+			LIMIT $2
+		`, postalCodeColumns)
+		return r.query(ctx, query, postalCode, limit)
+	}
+
+	compact := strings.Join(strings.Fields(address), "")
+	// Towns the input starts with are found by looking up each prefix of the
+	// input (btree), towns containing it by the trigram index. LIKE
+	// wildcards are escaped after normalizing, which folds full-width ％ and
+	// ＿ into them
+	query := fmt.Sprintf(`
+		WITH input AS (
+			SELECT search_normalize($1) AS text
+		),
+		pattern AS (
+			SELECT replace(replace(replace(text, '\', '\\'), '%%', '\%%'), '_', '\_') AS text
+			FROM input
+		),
+		prefixes AS (
+			SELECT left(input.text, n) AS prefix
+			FROM input, generate_series(1, length(input.text)) n
+		),
+		matched AS (
+			SELECT pc.*, true AS is_prefix
+			FROM postal_codes pc
+			WHERE pc.search_address IN (SELECT prefix FROM prefixes)
+			UNION ALL
+			SELECT pc.*, false AS is_prefix
+			FROM postal_codes pc
+			WHERE pc.search_address LIKE '%%' || (SELECT text FROM pattern) || '%%'
+				AND pc.search_address NOT IN (SELECT prefix FROM prefixes)
+		)
+		SELECT %s
+		FROM matched
+		ORDER BY
+			is_prefix DESC,
+			length(search_address) DESC,
+			postal_code
+		LIMIT $2
+	`, postalCodeColumns)
+	return r.query(ctx, query, compact, limit)
+}
+
+// ReverseGeocode returns the nearest postal-code area to a point
+func (r *PostalCodeRepository) ReverseGeocode(ctx context.Context, lat, lon float64) (*domain.GeocodeResult, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, ST_Distance(location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) as distance
+		FROM postal_codes
+		WHERE ST_DWithin(location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+		ORDER BY location <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
+		LIMIT 1
+	`, postalCodeColumns)
+
+	var result domain.GeocodeResult
+	var distance float64
+	err := r.db.QueryRowContext(ctx, query, lon, lat, reverseGeocodeMaxMeters).Scan(
+		&result.PostalCode,
+		&result.Prefecture,
+		&result.City,
+		&result.Town,
+		&result.Latitude,
+		&result.Longitude,
+		&distance,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reverse geocode: %w", err)
+	}
+	result.Label = result.Prefecture + result.City + result.Town
+	result.Distance = &distance
+	result.Source = PostalSource
+	return &result, nil
+}
+
+func (r *PostalCodeRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.GeocodeResult, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to geocode: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.GeocodeResult{}
+	for rows.Next() {
+		var result domain.GeocodeResult
+		if err := rows.Scan(
+			&result.PostalCode,
+			&result.Prefecture,
+			&result.City,
+			&result.Town,
+			&result.Latitude,
+			&result.Longitude,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan geocode result: %w", err)
+		}
+		result.Label = result.Prefecture + result.City + result.Town
+		result.Source = PostalSource
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read geocode results: %w", err)
+	}
+
+	return results, nil
+}
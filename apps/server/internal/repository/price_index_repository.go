@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+// PriceIndexRepository reads the per-period product prices behind price
+// indexes from the daily product rollups
+type PriceIndexRepository struct {
+	db *sql.DB
+}
+
+func NewPriceIndexRepository(db *sql.DB) *PriceIndexRepository {
+	return &PriceIndexRepository{db: db}
+}
+
+// FindPeriodPrices averages the prices of each product in category (or a
+// category below it) at each store per period ("month" or "week") and
+// currency, over the days from through to.
+// With an areaID only stores inside the area count.
+func (r *PriceIndexRepository) FindPeriodPrices(category string, areaID int, period string, from, to time.Time, tax query.TaxMode) ([]domain.PeriodPrice, error) {
+	args := &argList{}
+	measure := "price"
+	if !tax.Included() {
+		measure = "excl"
+	}
+	where := fmt.Sprintf(
+		"WHERE %s AND d.day >= %s::date AND d.day <= %s::date",
+		rollupCategoryClause([]string{category}, args), args.add(from.Format("2006-01-02")), args.add(to.Format("2006-01-02")),
+	)
+	if areaID > 0 {
+		where += fmt.Sprintf(
+			" AND EXISTS (SELECT 1 FROM stores s JOIN areas a ON ST_Intersects(a.geom, s.location::geometry) WHERE s.id = d.store_id AND a.id = %s)",
+			args.add(areaID),
+		)
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT
+			date_trunc(%s, d.day::timestamp)::date AS period_start,
+			d.store_id,
+			d.product_id,
+			d.currency,
+			SUM(d.%s_sum) / SUM(d.price_count),
+			SUM(d.price_count)
+		FROM price_daily_product d
+		%s
+		GROUP BY 1, 2, 3, 4
+		ORDER BY 1, 2, 3, 4
+	`, args.add(period), measure, where), args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query period prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []domain.PeriodPrice
+	for rows.Next() {
+		var price domain.PeriodPrice
+		if err := rows.Scan(&price.PeriodStart, &price.StoreID, &price.ProductID, &price.Currency, &price.AvgPrice, &price.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan period price: %w", err)
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, rows.Err()
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
 )
 
 type PriceRepository struct {
@@ -16,17 +19,42 @@ func NewPriceRepository(db *sql.DB) *PriceRepository {
 	return &PriceRepository{db: db}
 }
 
-// FindByProductID finds all prices for a specific product
-func (r *PriceRepository) FindByProductID(productID int, limit, offset int, sortField, sortOrder string) ([]domain.Price, error) {
-	query := `
+// FindByProductID finds all prices for a specific product with the
+// promotions active now and the effective price for the purchase. When
+// currency is set, prices are converted into it at the rate in effect on
+// recorded_at. Prices with a confidence below minConfidence are left out.
+func (r *PriceRepository) FindByProductID(productID int, currency string, tax query.TaxMode, minConfidence float64, purchase query.Purchase, limit, offset int, sortField, sortOrder string) ([]domain.Price, error) {
+	args := &argList{}
+	where := fmt.Sprintf("WHERE p.product_id = %s AND p.status = 'active'", args.add(productID))
+	if minConfidence > 0 {
+		where += " AND " + confidenceClause(minConfidence, args)
+	}
+	exprs := priceExpressions(currency, tax, purchase, args)
+	orderBy := priceOrderColumn(sortField)
+	limitArg := args.add(limit)
+	offsetArg := args.add(offset)
+
+	query := fmt.Sprintf(`
 		SELECT
 			p.id,
 			p.store_id,
 			p.product_id,
-			p.price,
+			%s as price,
 			p.currency,
+			p.posted_price,
+			p.tax_included,
+			p.tax_rate,
+			%s as converted_price,
+			%s as effective_price,
+			%s as unit_price,
+			unit_price_basis(pr.package_unit) as unit_price_basis,
 			p.recorded_at,
 			p.created_at,
+			p.source_type,
+			p.contributor_id,
+			p.source,
+			price_confidence(p.base_confidence, p.recorded_at) as confidence,
+			price_is_latest(p.store_id, p.product_id, p.recorded_at) as is_latest,
 			s.id,
 			s.name,
 			s.address,
@@ -36,22 +64,28 @@ func (r *PriceRepository) FindByProductID(productID int, limit, offset int, sort
 			s.updated_at
 		FROM prices p
 		INNER JOIN stores s ON p.store_id = s.id
-		WHERE p.product_id = $1
-		ORDER BY %s %s
-		LIMIT $2 OFFSET $3
-	`
+		INNER JOIN products pr ON p.product_id = pr.id
+		%s
+		ORDER BY %s %s NULLS LAST
+		LIMIT %s OFFSET %s
+	`, exprs.price, exprs.converted, exprs.effective, exprs.unit, where, orderBy, sortOrder, limitArg, offsetArg)
 
-	orderedQuery := fmt.Sprintf(query, sortField, sortOrder)
-	rows, err := r.db.Query(orderedQuery, productID, limit, offset)
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query prices: %w", err)
 	}
 	defer rows.Close()
 
 	var prices []domain.Price
+	var latest []bool
 	for rows.Next() {
 		var price domain.Price
 		var store domain.Store
+		var converted sql.NullFloat64
+		var effective sql.NullFloat64
+		var unit unitPriceValues
+		var provenance provenanceValues
+		var isLatest bool
 
 		err := rows.Scan(
 			&price.ID,
@@ -59,8 +93,20 @@ func (r *PriceRepository) FindByProductID(productID int, limit, offset int, sort
 			&price.ProductID,
 			&price.Price,
 			&price.Currency,
+			&price.PostedPrice,
+			&price.PostedTaxIncluded,
+			&price.TaxRate,
+			&converted,
+			&effective,
+			&unit.price,
+			&unit.basis,
 			&price.RecordedAt,
 			&price.CreatedAt,
+			&provenance.sourceType,
+			&provenance.contributorID,
+			&provenance.source,
+			&provenance.confidence,
+			&isLatest,
 			&store.ID,
 			&store.Name,
 			&store.Address,
@@ -72,60 +118,93 @@ func (r *PriceRepository) FindByProductID(productID int, limit, offset int, sort
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan price: %w", err)
 		}
+		if err := applyConversion(&price, currency, converted); err != nil {
+			return nil, err
+		}
+		if effective.Valid {
+			price.EffectivePrice = &effective.Float64
+		}
+		price.TaxIncluded = tax.Included()
+		unit.apply(&price)
+		provenance.apply(&price)
 
 		price.Store = &store
 		prices = append(prices, price)
+		latest = append(latest, isLatest)
 	}
 
+	if err := r.attachActivePromotions(prices, latest, tax); err != nil {
+		return nil, err
+	}
 	return prices, nil
 }
 
-// FindByStoreID finds all prices for a specific store (optionally filtered by category)
-func (r *PriceRepository) FindByStoreID(storeID int, category string, limit, offset int, sortField, sortOrder string) ([]domain.Price, error) {
-	orderBy := "p.price"
-	if sortField == "recorded_at" {
-		orderBy = "p.recorded_at"
+// FindByStoreID finds all prices for a specific store (optionally filtered by
+// category and its subcategories) with active promotions, converted into currency when set
+func (r *PriceRepository) FindByStoreID(storeID int, category string, currency string, tax query.TaxMode, purchase query.Purchase, limit, offset int, sortField, sortOrder string) ([]domain.Price, error) {
+	orderBy := priceOrderColumn(sortField)
+
+	args := &argList{}
+	exprs := priceExpressions(currency, tax, purchase, args)
+	where := fmt.Sprintf("WHERE p.store_id = %s AND p.status = 'active'", args.add(storeID))
+	if category != "" {
+		where += " AND " + categoryClause([]string{category}, args)
 	}
+	limitArg := args.add(limit)
+	offsetArg := args.add(offset)
 
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			p.id,
 			p.store_id,
 			p.product_id,
-			p.price,
+			%s as price,
 			p.currency,
+			p.posted_price,
+			p.tax_included,
+			p.tax_rate,
+			%s as converted_price,
+			%s as effective_price,
+			%s as unit_price,
+			unit_price_basis(pr.package_unit) as unit_price_basis,
 			p.recorded_at,
 			p.created_at,
+			p.source_type,
+			p.contributor_id,
+			p.source,
+			price_confidence(p.base_confidence, p.recorded_at) as confidence,
+			price_is_latest(p.store_id, p.product_id, p.recorded_at) as is_latest,
 			pr.id,
 			pr.name,
 			pr.category,
-			pr.barcode
+			pr.barcode,
+			pr.package_size,
+			pr.package_unit,
+			pr.tax_class
 		FROM prices p
 		INNER JOIN products pr ON p.product_id = pr.id
-		WHERE p.store_id = $1
-	`
+		%s
+		ORDER BY %s %s NULLS LAST
+		LIMIT %s OFFSET %s
+	`, exprs.price, exprs.converted, exprs.effective, exprs.unit, where, orderBy, sortOrder, limitArg, offsetArg)
 
-	args := []interface{}{storeID}
-	if category != "" {
-		query += " AND pr.category = $2"
-		args = append(args, category)
-		query += fmt.Sprintf(" ORDER BY %s %s LIMIT $3 OFFSET $4", orderBy, sortOrder)
-		args = append(args, limit, offset)
-	} else {
-		query += fmt.Sprintf(" ORDER BY %s %s LIMIT $2 OFFSET $3", orderBy, sortOrder)
-		args = append(args, limit, offset)
-	}
-
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query prices by store: %w", err)
 	}
 	defer rows.Close()
 
 	var prices []domain.Price
+	var latest []bool
 	for rows.Next() {
 		var price domain.Price
 		var product domain.Product
+		var converted sql.NullFloat64
+		var effective sql.NullFloat64
+		var unit unitPriceValues
+		var provenance provenanceValues
+		var isLatest bool
+		var pkg packageColumnValues
 
 		err := rows.Scan(
 			&price.ID,
@@ -133,24 +212,106 @@ func (r *PriceRepository) FindByStoreID(storeID int, category string, limit, off
 			&price.ProductID,
 			&price.Price,
 			&price.Currency,
+			&price.PostedPrice,
+			&price.PostedTaxIncluded,
+			&price.TaxRate,
+			&converted,
+			&effective,
+			&unit.price,
+			&unit.basis,
 			&price.RecordedAt,
 			&price.CreatedAt,
+			&provenance.sourceType,
+			&provenance.contributorID,
+			&provenance.source,
+			&provenance.confidence,
+			&isLatest,
 			&product.ID,
 			&product.Name,
 			&product.Category,
 			&product.Barcode,
+			&pkg.size,
+			&pkg.unit,
+			&product.TaxClass,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan price: %w", err)
 		}
+		if err := applyConversion(&price, currency, converted); err != nil {
+			return nil, err
+		}
+		if effective.Valid {
+			price.EffectivePrice = &effective.Float64
+		}
+		price.TaxIncluded = tax.Included()
+		unit.apply(&price)
+		provenance.apply(&price)
+		pkg.apply(&product)
 
 		price.Product = &product
 		prices = append(prices, price)
+		latest = append(latest, isLatest)
 	}
 
+	if err := r.attachActivePromotions(prices, latest, tax); err != nil {
+		return nil, err
+	}
 	return prices, nil
 }
 
+// attachActivePromotions loads the promotions active now for each listed
+// (store, product) pair, including member-only ones. Only prices marked
+// latest get them, as only those have promotions in their effective price.
+func (r *PriceRepository) attachActivePromotions(prices []domain.Price, latest []bool, tax query.TaxMode) error {
+	var storeIDs, productIDs []int
+	for i, price := range prices {
+		if latest[i] {
+			storeIDs = append(storeIDs, price.StoreID)
+			productIDs = append(productIDs, price.ProductID)
+		}
+	}
+	if len(storeIDs) == 0 {
+		return nil
+	}
+
+	promotionQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM promotions pm
+		JOIN products pr ON pr.id = pm.product_id
+		WHERE (pm.store_id, pm.product_id) IN (SELECT * FROM unnest($1::int[], $2::int[]))
+		  AND %s
+		ORDER BY pm.valid_from, pm.id
+	`, promotionColumns(tax), activePromotionCondition)
+
+	rows, err := r.db.Query(promotionQuery, pq.Array(storeIDs), pq.Array(productIDs))
+	if err != nil {
+		return fmt.Errorf("failed to query active promotions: %w", err)
+	}
+	defer rows.Close()
+
+	type pair struct{ storeID, productID int }
+	active := make(map[pair][]domain.Promotion)
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan promotion: %w", err)
+		}
+		promotion.TaxIncluded = tax.Included()
+		key := pair{promotion.StoreID, promotion.ProductID}
+		active[key] = append(active[key], promotion)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read promotions: %w", err)
+	}
+
+	for i := range prices {
+		if latest[i] {
+			prices[i].Promotions = active[pair{prices[i].StoreID, prices[i].ProductID}]
+		}
+	}
+	return nil
+}
+
 // FindRecentByStoreIDs finds recent prices for multiple stores
 func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]domain.Price, error) {
 	if len(storeIDs) == 0 {
@@ -166,13 +327,17 @@ func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]dom
 			p.currency,
 			p.recorded_at,
 			p.created_at,
+			p.source_type,
+			p.contributor_id,
+			p.source,
+			price_confidence(p.base_confidence, p.recorded_at) as confidence,
 			pr.id,
 			pr.name,
 			pr.category,
 			pr.barcode
 		FROM prices p
 		INNER JOIN products pr ON p.product_id = pr.id
-		WHERE p.store_id = ANY($1)
+		WHERE p.store_id = ANY($1) AND p.status = 'active'
 		ORDER BY p.recorded_at DESC
 		LIMIT $2
 	`
@@ -187,6 +352,7 @@ func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]dom
 	for rows.Next() {
 		var price domain.Price
 		var product domain.Product
+		var provenance provenanceValues
 
 		err := rows.Scan(
 			&price.ID,
@@ -196,6 +362,10 @@ func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]dom
 			&price.Currency,
 			&price.RecordedAt,
 			&price.CreatedAt,
+			&provenance.sourceType,
+			&provenance.contributorID,
+			&provenance.source,
+			&provenance.confidence,
 			&product.ID,
 			&product.Name,
 			&product.Category,
@@ -204,6 +374,7 @@ func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]dom
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan price: %w", err)
 		}
+		provenance.apply(&price)
 
 		price.Product = &product
 		prices = append(prices, price)
@@ -212,45 +383,132 @@ func (r *PriceRepository) FindRecentByStoreIDs(storeIDs []int, limit int) ([]dom
 	return prices, nil
 }
 
-func (r *PriceRepository) FindStorePriceStats(storeID int, category string, query string, days int) (domain.StorePriceStats, error) {
+func (r *PriceRepository) FindStorePriceStats(storeID int, category string, query string, currency string, tax query.TaxMode, days int) (domain.StorePriceStats, error) {
+	if days <= 0 {
+		days = 14
+	}
+
+	summary, daily, err := r.findPriceStats("d.store_id = $1", storeID, category, query, currency, tax, days)
+	if err != nil {
+		return domain.StorePriceStats{}, err
+	}
+
+	return domain.StorePriceStats{
+		StoreID:  storeID,
+		Category: category,
+		Query:    query,
+		Days:     days,
+		Summary:  summary,
+		Daily:    daily,
+	}, nil
+}
+
+// FindChainPriceStats aggregates prices across all branches of a chain
+func (r *PriceRepository) FindChainPriceStats(chainID int, category string, query string, currency string, tax query.TaxMode, days int) (domain.ChainPriceStats, error) {
 	if days <= 0 {
 		days = 14
 	}
 
-	args := []interface{}{storeID, days}
-	where := "WHERE p.store_id = $1 AND p.recorded_at >= NOW() - ($2 * INTERVAL '1 day')"
+	var storeCount int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM stores WHERE chain_id = $1", chainID).Scan(&storeCount); err != nil {
+		return domain.ChainPriceStats{}, fmt.Errorf("failed to count chain stores: %w", err)
+	}
+
+	summary, daily, err := r.findPriceStats("s.chain_id = $1", chainID, category, query, currency, tax, days)
+	if err != nil {
+		return domain.ChainPriceStats{}, err
+	}
+
+	return domain.ChainPriceStats{
+		ChainID:    chainID,
+		Category:   category,
+		Query:      query,
+		Days:       days,
+		StoreCount: storeCount,
+		Summary:    summary,
+		Daily:      daily,
+	}, nil
+}
+
+// findPriceStats computes the summary and daily series for the last days
+// days (today included) from the daily rollups. scope is a condition on d
+// (the rollup) or s (stores) bound to $1. Prices are converted into
+// currency when set; otherwise they must share one currency.
+func (r *PriceRepository) findPriceStats(scope string, scopeArg interface{}, category string, query string, currency string, tax query.TaxMode, days int) (domain.PriceSummary, []domain.DailyPriceStats, error) {
+	args := &argList{}
+	args.add(scopeArg)
+	where := fmt.Sprintf("WHERE %s AND d.day > CURRENT_DATE - %s::int", scope, args.add(days))
 	if category != "" {
-		args = append(args, category)
-		where += fmt.Sprintf(" AND pr.category = $%d", len(args))
+		where += " AND " + rollupCategoryClause([]string{category}, args)
 	}
+	// Name searches need per-product rows; otherwise the category rollup
+	// has fewer rows to read
+	from := "price_daily_category d"
 	if query != "" {
-		args = append(args, "%"+query+"%")
-		where += fmt.Sprintf(" AND pr.name ILIKE $%d", len(args))
+		from = "price_daily_product d JOIN products pr ON pr.id = d.product_id"
+		where += fmt.Sprintf(" AND pr.name ILIKE %s", args.add("%"+query+"%"))
+	}
+
+	measure := "price"
+	if !tax.Included() {
+		measure = "excl"
+	}
+	currencyArg := ""
+	if currency != "" {
+		currencyArg = args.add(currency)
+	}
+	amount := func(column string) string {
+		expr := fmt.Sprintf("d.%s_%s", measure, column)
+		if currencyArg != "" {
+			expr = fmt.Sprintf("convert_price(%s, d.currency, %s, d.day)", expr, currencyArg)
+		}
+		return expr
 	}
 
 	withClause := fmt.Sprintf(`
 		WITH filtered AS (
-			SELECT p.price, p.currency, p.recorded_at::date AS day
-			FROM prices p
-			JOIN products pr ON pr.id = p.product_id
+			SELECT d.day, d.currency, d.price_count AS count, %s AS total, %s AS min_price, %s AS max_price
+			FROM %s
+			JOIN stores s ON s.id = d.store_id
 			%s
 		)
-	`, where)
+	`, amount("sum"), amount("min"), amount("max"), from, where)
+
+	currencyQuery := withClause + `
+		SELECT
+			array_agg(DISTINCT currency ORDER BY currency),
+			array_agg(DISTINCT currency ORDER BY currency) FILTER (WHERE total IS NULL)
+		FROM filtered
+	`
+	var currencies pq.StringArray
+	var unconvertible pq.StringArray
+	if err := r.db.QueryRow(currencyQuery, args.values...).Scan(&currencies, &unconvertible); err != nil {
+		return domain.PriceSummary{}, nil, fmt.Errorf("failed to query price currencies: %w", err)
+	}
+	if len(unconvertible) > 0 {
+		return domain.PriceSummary{}, nil, fmt.Errorf("%w: %s to %s", domain.ErrExchangeRateNotFound, strings.Join(unconvertible, ", "), currency)
+	}
+	if currency == "" && len(currencies) > 1 {
+		return domain.PriceSummary{}, nil, fmt.Errorf("%w (%s); specify a currency to convert into", domain.ErrMixedCurrencies, strings.Join(currencies, ", "))
+	}
 
 	summaryQuery := withClause + `
-		SELECT MIN(price), MAX(price), AVG(price), MIN(currency)
+		SELECT MIN(min_price), MAX(max_price), SUM(total) / SUM(count), MIN(currency)
 		FROM filtered
 	`
 
 	var minPrice sql.NullFloat64
 	var maxPrice sql.NullFloat64
 	var avgPrice sql.NullFloat64
-	var currency sql.NullString
-	if err := r.db.QueryRow(summaryQuery, args...).Scan(&minPrice, &maxPrice, &avgPrice, &currency); err != nil {
-		return domain.StorePriceStats{}, fmt.Errorf("failed to query price summary: %w", err)
+	var summaryCurrency sql.NullString
+	if err := r.db.QueryRow(summaryQuery, args.values...).Scan(&minPrice, &maxPrice, &avgPrice, &summaryCurrency); err != nil {
+		return domain.PriceSummary{}, nil, fmt.Errorf("failed to query price summary: %w", err)
 	}
 
-	summary := domain.PriceSummary{Currency: currency.String}
+	summary := domain.PriceSummary{Currency: summaryCurrency.String, TaxIncluded: tax.Included()}
+	if currency != "" {
+		summary.Currency = currency
+	}
 	if minPrice.Valid {
 		summary.MinPrice = &minPrice.Float64
 	}
@@ -262,15 +520,15 @@ func (r *PriceRepository) FindStorePriceStats(storeID int, category string, quer
 	}
 
 	dailyQuery := withClause + `
-		SELECT day, AVG(price), MIN(price), MAX(price), COUNT(*)
+		SELECT day, SUM(total) / SUM(count), MIN(min_price), MAX(max_price), SUM(count)
 		FROM filtered
 		GROUP BY day
 		ORDER BY day
 	`
 
-	rows, err := r.db.Query(dailyQuery, args...)
+	rows, err := r.db.Query(dailyQuery, args.values...)
 	if err != nil {
-		return domain.StorePriceStats{}, fmt.Errorf("failed to query daily stats: %w", err)
+		return domain.PriceSummary{}, nil, fmt.Errorf("failed to query daily stats: %w", err)
 	}
 	defer rows.Close()
 
@@ -282,7 +540,7 @@ func (r *PriceRepository) FindStorePriceStats(storeID int, category string, quer
 		var max float64
 		var count int
 		if err := rows.Scan(&day, &avg, &min, &max, &count); err != nil {
-			return domain.StorePriceStats{}, fmt.Errorf("failed to scan daily stats: %w", err)
+			return domain.PriceSummary{}, nil, fmt.Errorf("failed to scan daily stats: %w", err)
 		}
 		daily = append(daily, domain.DailyPriceStats{
 			Date:     day,
@@ -293,12 +551,113 @@ func (r *PriceRepository) FindStorePriceStats(storeID int, category string, quer
 		})
 	}
 
-	return domain.StorePriceStats{
-		StoreID:  storeID,
-		Category: category,
-		Query:    query,
-		Days:     days,
-		Summary:  summary,
-		Daily:    daily,
-	}, nil
+	return summary, daily, nil
+}
+
+// priceSelectExprs are the computed price columns of a price listing, over
+// prices aliased p and products aliased pr
+type priceSelectExprs struct {
+	price     string // p.price in the requested tax mode
+	converted string // price in the requested currency, or NULL
+	effective string // per-unit price after promotions in the output currency
+	unit      string // effective price per unit_price_basis
+}
+
+// effectivePriceExpr is the per-unit price of the price row aliased p when
+// buying purchase.Quantity units, after the promotions active now if it is
+// the latest price of its store and product
+func effectivePriceExpr(purchase query.Purchase, args *argList) string {
+	return fmt.Sprintf(
+		"effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, %s, %s)",
+		args.add(max(purchase.Quantity, 1)),
+		args.add(purchase.Member),
+	)
+}
+
+func priceExpressions(currency string, tax query.TaxMode, purchase query.Purchase, args *argList) priceSelectExprs {
+	effective := effectivePriceExpr(purchase, args)
+	converted := "NULL::numeric"
+	if currency != "" {
+		currencyArg := args.add(currency)
+		converted = fmt.Sprintf("ROUND(%s, 2)", taxAdjusted(
+			fmt.Sprintf("convert_price(p.price, p.currency, %s, p.recorded_at::date)", currencyArg), tax))
+		effective = fmt.Sprintf("convert_price(%s, p.currency, %s, p.recorded_at::date)", effective, currencyArg)
+	}
+	effective = taxAdjusted(effective, tax)
+	return priceSelectExprs{
+		price:     taxAdjusted("p.price", tax),
+		converted: converted,
+		effective: fmt.Sprintf("ROUND(%s, 2)", effective),
+		unit:      fmt.Sprintf("ROUND(unit_price(%s, pr.package_size, pr.package_unit), 2)", effective),
+	}
+}
+
+// taxAdjusted puts amount, a tax-inclusive amount for the price row aliased
+// p, into the requested tax mode using the row's tax rate
+func taxAdjusted(amount string, tax query.TaxMode) string {
+	if !tax.Included() {
+		return fmt.Sprintf("exclude_tax(%s, p.tax_rate)", amount)
+	}
+	return amount
+}
+
+// priceOrderColumn maps a normalized price sort field to a listing column;
+// "price" compares what the buyer actually pays, and unit prices are
+// grouped by basis so only prices per the same unit are compared
+func priceOrderColumn(sortField string) string {
+	switch sortField {
+	case "recorded_at":
+		return "p.recorded_at"
+	case "unit_price":
+		return "unit_price_basis NULLS LAST, unit_price"
+	default:
+		return "effective_price"
+	}
+}
+
+type unitPriceValues struct {
+	price sql.NullFloat64
+	basis sql.NullString
+}
+
+func (v unitPriceValues) apply(price *domain.Price) {
+	if v.price.Valid && v.basis.Valid {
+		unitPrice := v.price.Float64
+		price.UnitPrice = &unitPrice
+		price.UnitPriceBasis = v.basis.String
+	}
+}
+
+type provenanceValues struct {
+	sourceType    string
+	contributorID sql.NullInt64
+	source        sql.NullString
+	confidence    float64
+}
+
+func (v provenanceValues) apply(price *domain.Price) {
+	price.SourceType = v.sourceType
+	price.Source = v.source.String
+	price.Confidence = v.confidence
+	if v.contributorID.Valid {
+		contributorID := int(v.contributorID.Int64)
+		price.ContributorID = &contributorID
+	}
+}
+
+// applyConversion replaces a scanned price with its converted amount, keeping
+// the recorded value as the original
+func applyConversion(price *domain.Price, currency string, converted sql.NullFloat64) error {
+	if currency == "" || price.Currency == currency {
+		return nil
+	}
+	if !converted.Valid {
+		return fmt.Errorf("%w: %s to %s on %s", domain.ErrExchangeRateNotFound, price.Currency, currency, price.RecordedAt.Format("2006-01-02"))
+	}
+	original := price.Price
+	price.OriginalPrice = &original
+	price.OriginalCurrency = price.Currency
+	price.Price = converted.Float64
+	price.Currency = currency
+	return nil
 }
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/lib/pq"
 	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
 )
 
 type ProductRepository struct {
@@ -18,7 +22,7 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 // FindAll returns all products
 func (r *ProductRepository) FindAll(limit, offset int, sortField, sortOrder string) ([]domain.Product, error) {
 	query := `
-		SELECT id, name, category, barcode, created_at
+		SELECT id, name, category, barcode, package_size, package_unit, tax_class, created_at
 		FROM products
 		ORDER BY %s %s
 		LIMIT $1 OFFSET $2
@@ -34,16 +38,21 @@ func (r *ProductRepository) FindAll(limit, offset int, sortField, sortOrder stri
 	var products []domain.Product
 	for rows.Next() {
 		var product domain.Product
+		var pkg packageColumnValues
 		err := rows.Scan(
 			&product.ID,
 			&product.Name,
 			&product.Category,
 			&product.Barcode,
+			&pkg.size,
+			&pkg.unit,
+			&product.TaxClass,
 			&product.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
+		pkg.apply(&product)
 		products = append(products, product)
 	}
 
@@ -53,17 +62,21 @@ func (r *ProductRepository) FindAll(limit, offset int, sortField, sortOrder stri
 // FindByID finds a product by its ID
 func (r *ProductRepository) FindByID(id int) (*domain.Product, error) {
 	query := `
-		SELECT id, name, category, barcode, created_at
+		SELECT id, name, category, barcode, package_size, package_unit, tax_class, created_at
 		FROM products
 		WHERE id = $1
 	`
 
 	var product domain.Product
+	var pkg packageColumnValues
 	err := r.db.QueryRow(query, id).Scan(
 		&product.ID,
 		&product.Name,
 		&product.Category,
 		&product.Barcode,
+		&pkg.size,
+		&pkg.unit,
+		&product.TaxClass,
 		&product.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -72,53 +85,144 @@ func (r *ProductRepository) FindByID(id int) (*domain.Product, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to find product: %w", err)
 	}
+	pkg.apply(&product)
 
 	return &product, nil
 }
 
-// Search searches products by name
-func (r *ProductRepository) Search(keyword string, limit, offset int, sortField, sortOrder string) ([]domain.Product, error) {
-	query := `
-		SELECT id, name, category, barcode, created_at
-		FROM products
-		WHERE name ILIKE '%' || $1 || '%'
-		ORDER BY %s %s
-		LIMIT $2 OFFSET $3
-	`
+// Search ranks products against a normalized keyword using trigram similarity
+// and prefix matching on the search_name column
+func (r *ProductRepository) Search(filters query.ProductSearchFilters, limit, offset int, sortField, sortOrder string) ([]domain.ProductSearchResult, error) {
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
-	orderedQuery := fmt.Sprintf(query, sortField, sortOrder)
-	rows, err := r.db.Query(orderedQuery, keyword, limit, offset)
+	keywordArg := addArg(filters.Keyword)
+	prefixArg := addArg(escapeLike(filters.Keyword) + "%")
+
+	conditions := []string{fmt.Sprintf(
+		"(pr.search_name %% %[1]s OR %[1]s <%% pr.search_name OR strpos(pr.search_name, %[1]s) > 0)",
+		keywordArg,
+	)}
+	if filters.Category != "" {
+		conditions = append(conditions, fmt.Sprintf("pr.category_id IN (SELECT category_subtree(%s))", addArg(pq.Array([]string{filters.Category}))))
+	}
+	if filters.MinPrice != nil || filters.MaxPrice != nil {
+		priceClause := ""
+		if filters.MinPrice != nil {
+			priceClause += fmt.Sprintf(" AND p.price >= %s", addArg(*filters.MinPrice))
+		}
+		if filters.MaxPrice != nil {
+			priceClause += fmt.Sprintf(" AND p.price <= %s", addArg(*filters.MaxPrice))
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM prices p WHERE p.product_id = pr.id AND p.status = 'active'%s)",
+			priceClause,
+		))
+	}
+
+	orderClause := fmt.Sprintf("score %s, pr.name ASC", sortOrder)
+	switch sortField {
+	case "name":
+		orderClause = fmt.Sprintf("pr.name %s", sortOrder)
+	case "created_at":
+		orderClause = fmt.Sprintf("pr.created_at %s", sortOrder)
+	}
+
+	limitArg := addArg(limit)
+	offsetArg := addArg(offset)
+
+	query := fmt.Sprintf(`
+		SELECT
+			pr.id,
+			pr.name,
+			pr.category,
+			pr.barcode,
+			pr.package_size,
+			pr.package_unit,
+			pr.tax_class,
+			pr.created_at,
+			GREATEST(similarity(pr.search_name, %[1]s), word_similarity(%[1]s, pr.search_name))
+				+ CASE
+					WHEN pr.search_name LIKE %[2]s THEN 1.0
+					WHEN strpos(pr.search_name, %[1]s) > 0 THEN 0.5
+					ELSE 0
+				END AS score
+		FROM products pr
+		WHERE %[3]s
+		ORDER BY %[4]s
+		LIMIT %[5]s OFFSET %[6]s
+	`, keywordArg, prefixArg, strings.Join(conditions, " AND "), orderClause, limitArg, offsetArg)
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search products: %w", err)
 	}
 	defer rows.Close()
 
-	var products []domain.Product
+	var results []domain.ProductSearchResult
 	for rows.Next() {
-		var product domain.Product
+		var result domain.ProductSearchResult
+		var pkg packageColumnValues
 		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Category,
-			&product.Barcode,
-			&product.CreatedAt,
+			&result.ID,
+			&result.Name,
+			&result.Category,
+			&result.Barcode,
+			&pkg.size,
+			&pkg.unit,
+			&result.TaxClass,
+			&result.CreatedAt,
+			&result.Score,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
-		products = append(products, product)
+		pkg.apply(&result.Product)
+		results = append(results, result)
 	}
 
-	return products, nil
+	return results, nil
 }
 
-// ListCategories returns distinct product categories
-func (r *ProductRepository) ListCategories() ([]string, error) {
+// SuggestNames returns product names loosely similar to a normalized keyword,
+// used for "did you mean" hints when a search has no matches
+func (r *ProductRepository) SuggestNames(keyword string, limit int) ([]string, error) {
 	query := `
-		SELECT DISTINCT category
+		SELECT name
 		FROM products
-		WHERE category IS NOT NULL AND category <> ''
-		ORDER BY category
+		WHERE similarity(search_name, $1) > 0.1
+		ORDER BY similarity(search_name, $1) DESC, name
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, keyword, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// ListCategories returns every category of the category tree, parents
+// and children alike, in name order
+func (r *ProductRepository) ListCategories() ([]domain.Category, error) {
+	query := `
+		SELECT id, parent_id, slug, name, names
+		FROM categories
+		ORDER BY name, id
 	`
 
 	rows, err := r.db.Query(query)
@@ -127,14 +231,37 @@ func (r *ProductRepository) ListCategories() ([]string, error) {
 	}
 	defer rows.Close()
 
-	var categories []string
+	var categories []domain.Category
 	for rows.Next() {
-		var category string
-		if err := rows.Scan(&category); err != nil {
+		var category domain.Category
+		var parentID sql.NullInt64
+		var names []byte
+		if err := rows.Scan(&category.ID, &parentID, &category.Slug, &category.Name, &names); err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			category.ParentID = &id
+		}
+		if err := json.Unmarshal(names, &category.Names); err != nil {
+			return nil, fmt.Errorf("failed to decode category names: %w", err)
+		}
 		categories = append(categories, category)
 	}
 
-	return categories, nil
+	return categories, rows.Err()
+}
+
+// packageColumnValues holds the nullable package_size/package_unit columns
+type packageColumnValues struct {
+	size sql.NullFloat64
+	unit sql.NullString
+}
+
+func (v packageColumnValues) apply(product *domain.Product) {
+	if v.size.Valid && v.unit.Valid {
+		size := v.size.Float64
+		product.PackageSize = &size
+		product.PackageUnit = v.unit.String
+	}
 }
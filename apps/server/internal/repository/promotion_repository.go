@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+// promotionColumns selects promotions aliased pm, joined to their product
+// as pr, in the requested tax mode; it must stay in sync with scanPromotion
+func promotionColumns(tax query.TaxMode) string {
+	salePrice, bundlePrice := "pm.sale_price", "pm.bundle_price"
+	if !tax.Included() {
+		rate := "tax_rate_on(pr.tax_class, pm.valid_from::date)"
+		salePrice = fmt.Sprintf("exclude_tax(pm.sale_price, %s)", rate)
+		bundlePrice = fmt.Sprintf("exclude_tax(pm.bundle_price, %s)", rate)
+	}
+	return fmt.Sprintf(`
+		pm.id, pm.store_id, pm.product_id, %s, pm.bundle_quantity, %s,
+		pm.currency, pm.member_only, pm.description, pm.valid_from, pm.valid_to, pm.created_at
+	`, salePrice, bundlePrice)
+}
+
+// activePromotionCondition matches promotions aliased pm that apply now
+const activePromotionCondition = "pm.valid_from <= CURRENT_TIMESTAMP AND (pm.valid_to IS NULL OR pm.valid_to > CURRENT_TIMESTAMP)"
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+// Create inserts a promotion, defaulting ValidFrom to now when zero. It
+// returns nil when the store or product does not exist.
+func (r *PromotionRepository) Create(promotion domain.Promotion) (*domain.Promotion, error) {
+	var validFrom interface{}
+	if !promotion.ValidFrom.IsZero() {
+		validFrom = promotion.ValidFrom
+	}
+	var description interface{}
+	if promotion.Description != "" {
+		description = promotion.Description
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO promotions (
+			store_id, product_id, sale_price, bundle_quantity, bundle_price,
+			currency, member_only, description, valid_from, valid_to
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, CURRENT_TIMESTAMP), $10
+		WHERE EXISTS (SELECT 1 FROM stores WHERE id = $1)
+			AND EXISTS (SELECT 1 FROM products WHERE id = $2)
+		RETURNING id
+	`,
+		promotion.StoreID,
+		promotion.ProductID,
+		promotion.SalePrice,
+		promotion.BundleQuantity,
+		promotion.BundlePrice,
+		promotion.Currency,
+		promotion.MemberOnly,
+		description,
+		validFrom,
+		promotion.ValidTo,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return r.FindByID(id, query.TaxIncluded)
+}
+
+// FindAll lists promotions, newest first, optionally narrowed to a store,
+// a product and those active now
+func (r *PromotionRepository) FindAll(storeID, productID int, activeOnly bool, tax query.TaxMode, limit, offset int) ([]domain.Promotion, error) {
+	args := &argList{}
+	var conditions []string
+	if storeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("pm.store_id = %s", args.add(storeID)))
+	}
+	if productID > 0 {
+		conditions = append(conditions, fmt.Sprintf("pm.product_id = %s", args.add(productID)))
+	}
+	if activeOnly {
+		conditions = append(conditions, activePromotionCondition)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM promotions pm
+		JOIN products pr ON pr.id = pm.product_id
+		%s
+		ORDER BY pm.valid_from DESC, pm.id DESC
+		LIMIT %s OFFSET %s
+	`, promotionColumns(tax), where, args.add(limit), args.add(offset))
+
+	rows, err := r.db.Query(listQuery, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query promotions: %w", err)
+	}
+	defer rows.Close()
+
+	var promotions []domain.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promotion: %w", err)
+		}
+		promotion.TaxIncluded = tax.Included()
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, nil
+}
+
+// FindByID finds a promotion by its ID
+func (r *PromotionRepository) FindByID(id int, tax query.TaxMode) (*domain.Promotion, error) {
+	findQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM promotions pm
+		JOIN products pr ON pr.id = pm.product_id
+		WHERE pm.id = $1
+	`, promotionColumns(tax))
+
+	promotion, err := scanPromotion(r.db.QueryRow(findQuery, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find promotion: %w", err)
+	}
+	promotion.TaxIncluded = tax.Included()
+	return &promotion, nil
+}
+
+func scanPromotion(row rowScanner) (domain.Promotion, error) {
+	var promotion domain.Promotion
+	var salePrice sql.NullFloat64
+	var bundleQuantity sql.NullInt64
+	var bundlePrice sql.NullFloat64
+	var description sql.NullString
+	var validTo sql.NullTime
+	err := row.Scan(
+		&promotion.ID,
+		&promotion.StoreID,
+		&promotion.ProductID,
+		&salePrice,
+		&bundleQuantity,
+		&bundlePrice,
+		&promotion.Currency,
+		&promotion.MemberOnly,
+		&description,
+		&promotion.ValidFrom,
+		&validTo,
+		&promotion.CreatedAt,
+	)
+	if err != nil {
+		return promotion, err
+	}
+	if salePrice.Valid {
+		promotion.SalePrice = &salePrice.Float64
+	}
+	if bundleQuantity.Valid {
+		quantity := int(bundleQuantity.Int64)
+		promotion.BundleQuantity = &quantity
+	}
+	if bundlePrice.Valid {
+		promotion.BundlePrice = &bundlePrice.Float64
+	}
+	promotion.Description = description.String
+	if validTo.Valid {
+		promotion.ValidTo = &validTo.Time
+	}
+	return promotion, nil
+}
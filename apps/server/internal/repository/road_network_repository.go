@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/query"
+	"github.com/price-comparison/server/internal/routing"
+)
+
+type RoadNetworkRepository struct {
+	db *sql.DB
+}
+
+func NewRoadNetworkRepository(db *sql.DB) *RoadNetworkRepository {
+	return &RoadNetworkRepository{db: db}
+}
+
+// LoadEdges returns the edges usable by mode whose source node lies within
+// radiusMeters of center
+func (r *RoadNetworkRepository) LoadEdges(center query.GeoPoint, radiusMeters float64, mode routing.Mode) ([]routing.Edge, error) {
+	accessColumn := "allows_walking"
+	switch mode {
+	case routing.Cycling:
+		accessColumn = "allows_cycling"
+	case routing.Driving:
+		accessColumn = "allows_driving"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.source, e.target, e.length_m, COALESCE(e.max_speed_kmh, 0), e.oneway
+		FROM road_edges e
+		JOIN road_nodes n ON n.id = e.source
+		WHERE e.%s
+		  AND ST_DWithin(n.location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+	`, accessColumn)
+
+	rows, err := r.db.Query(query, center.Lon, center.Lat, radiusMeters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query road edges: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []routing.Edge
+	for rows.Next() {
+		var edge routing.Edge
+		if err := rows.Scan(&edge.Source, &edge.Target, &edge.LengthMeters, &edge.MaxSpeedKmh, &edge.Oneway); err != nil {
+			return nil, fmt.Errorf("failed to scan road edge: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read road edges: %w", err)
+	}
+
+	return edges, nil
+}
+
+// NearestNodes snaps each point to its closest road node within
+// maxDistanceMeters, preserving input order
+func (r *RoadNetworkRepository) NearestNodes(points []query.GeoPoint, maxDistanceMeters float64) ([]routing.Snap, error) {
+	snaps := make([]routing.Snap, len(points))
+	if len(points) == 0 {
+		return snaps, nil
+	}
+
+	lons := make([]float64, len(points))
+	lats := make([]float64, len(points))
+	for i, point := range points {
+		lons[i] = point.Lon
+		lats[i] = point.Lat
+	}
+
+	query := `
+		SELECT pts.idx, nearest.id, nearest.distance
+		FROM unnest($1::double precision[], $2::double precision[]) WITH ORDINALITY AS pts(lon, lat, idx)
+		CROSS JOIN LATERAL (
+			SELECT n.id, ST_Distance(n.location, ST_SetSRID(ST_MakePoint(pts.lon, pts.lat), 4326)::geography) AS distance
+			FROM road_nodes n
+			WHERE ST_DWithin(n.location, ST_SetSRID(ST_MakePoint(pts.lon, pts.lat), 4326)::geography, $3)
+			ORDER BY n.location <-> ST_SetSRID(ST_MakePoint(pts.lon, pts.lat), 4326)::geography
+			LIMIT 1
+		) nearest
+	`
+
+	rows, err := r.db.Query(query, pq.Array(lons), pq.Array(lats), maxDistanceMeters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snap points to road network: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idx int
+		var snap routing.Snap
+		if err := rows.Scan(&idx, &snap.NodeID, &snap.DistanceMeters); err != nil {
+			return nil, fmt.Errorf("failed to scan road snap: %w", err)
+		}
+		snap.Found = true
+		snaps[idx-1] = snap
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read road snaps: %w", err)
+	}
+
+	return snaps, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// RollupRepository maintains the daily price aggregates that price
+// statistics read (price_daily_product and price_daily_category)
+type RollupRepository struct {
+	db *sql.DB
+}
+
+func NewRollupRepository(db *sql.DB) *RollupRepository {
+	return &RollupRepository{db: db}
+}
+
+// Refresh recomputes up to maxKeys of the store/product/days queued by
+// price writes and returns how many it took. It returns 0 while another
+// refresh is running.
+func (r *RollupRepository) Refresh(maxKeys int) (int, error) {
+	var taken int
+	if err := r.db.QueryRow("SELECT refresh_price_rollups($1)", maxKeys).Scan(&taken); err != nil {
+		return 0, fmt.Errorf("failed to refresh price rollups: %w", err)
+	}
+	return taken, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/query"
+)
+
+// argList collects bound query arguments and hands out their placeholders
+type argList struct {
+	values []interface{}
+}
+
+func (a *argList) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+// storeFilterSQL is the compiled form of query.StoreFilters: a lateral join
+// computing each store's minimum matching price and the WHERE conditions.
+// All user-supplied values are bound through the argList.
+type storeFilterSQL struct {
+	priceJoin  string
+	conditions []string
+}
+
+func (f storeFilterSQL) whereClause() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// confidenceClause keeps prices with at least minConfidence. The horizon
+// bound on recorded_at is implied by the confidence test but lets Postgres
+// skip the monthly partitions that are too old to qualify; recorded_at is
+// compared with timestamps rather than NOW() for the same reason.
+func confidenceClause(minConfidence float64, args *argList) string {
+	threshold := args.add(minConfidence)
+	return fmt.Sprintf(
+		"p.recorded_at >= price_confidence_horizon(%s) AND price_confidence(p.base_confidence, p.recorded_at) >= %s",
+		threshold, threshold,
+	)
+}
+
+// categoryClause matches products filed under any of categories, given as
+// slugs or category strings, or under a category below one of them
+func categoryClause(categories []string, args *argList) string {
+	return fmt.Sprintf("pr.category_id IN (SELECT category_subtree(%s))", args.add(pq.Array(categories)))
+}
+
+// rollupCategoryClause is categoryClause for the daily rollups, aliased d
+func rollupCategoryClause(categories []string, args *argList) string {
+	return fmt.Sprintf("d.category_id IN (SELECT category_subtree(%s))", args.add(pq.Array(categories)))
+}
+
+// compileStoreFilters expects stores aliased as "s" and exposes the minimum
+// matching price as price_summary.min_price and the cheapest unit price as
+// price_summary.min_unit_price, both effective prices for the filters'
+// Purchase in their tax mode. Unit prices
+// per 100g, litre and piece don't compare, so min_unit_price (and its
+// price_summary.min_unit_price_basis) is only set when the store's matching
+// products share one basis. With a
+// Currency, prices lacking an exchange rate are left out of both minimums.
+// Quarantined and rejected prices never count, nor do prices below
+// MinConfidence.
+func compileStoreFilters(filters query.StoreFilters, args *argList) storeFilterSQL {
+	var priceClauses []string
+	if len(filters.Categories) > 0 {
+		priceClauses = append(priceClauses, categoryClause(filters.Categories, args))
+	}
+	if filters.Query != "" {
+		priceClauses = append(priceClauses, fmt.Sprintf("pr.name ILIKE %s", args.add("%"+escapeLike(filters.Query)+"%")))
+	}
+	if filters.RecordedWithinDays > 0 {
+		priceClauses = append(priceClauses, fmt.Sprintf(
+			"p.recorded_at >= LOCALTIMESTAMP - (%s * INTERVAL '1 day')",
+			args.add(filters.RecordedWithinDays),
+		))
+	}
+
+	if filters.MinConfidence > 0 {
+		priceClauses = append(priceClauses, confidenceClause(filters.MinConfidence, args))
+	}
+
+	priceExpr := effectivePriceExpr(filters.Purchase, args)
+	if filters.Currency != "" {
+		priceExpr = fmt.Sprintf("convert_price(%s, p.currency, %s, p.recorded_at::date)", priceExpr, args.add(filters.Currency))
+	}
+	priceExpr = taxAdjusted(priceExpr, filters.Tax)
+
+	// Bounds apply to each price, so a store's minimum is its cheapest
+	// price within them
+	if filters.MinPrice != nil {
+		priceClauses = append(priceClauses, fmt.Sprintf("%s >= %s", priceExpr, args.add(*filters.MinPrice)))
+	}
+	if filters.MaxPrice != nil {
+		priceClauses = append(priceClauses, fmt.Sprintf("%s <= %s", priceExpr, args.add(*filters.MaxPrice)))
+	}
+
+	priceWhere := ""
+	for _, clause := range priceClauses {
+		priceWhere += " AND " + clause
+	}
+
+	compiled := storeFilterSQL{
+		priceJoin: fmt.Sprintf(`
+		LEFT JOIN LATERAL (
+			SELECT
+				MIN(%s) AS min_price,
+				CASE WHEN COUNT(DISTINCT unit_price_basis(pr.package_unit)) = 1
+					THEN MIN(unit_price(%s, pr.package_size, pr.package_unit)) END AS min_unit_price,
+				CASE WHEN COUNT(DISTINCT unit_price_basis(pr.package_unit)) = 1
+					THEN MIN(unit_price_basis(pr.package_unit)) END AS min_unit_price_basis
+			FROM prices p
+			JOIN products pr ON pr.id = p.product_id
+			WHERE p.store_id = s.id AND p.status = 'active'%s
+		) price_summary ON true
+		`, priceExpr, priceExpr, priceWhere),
+	}
+
+	if filters.Bounds != nil {
+		minLonArg := args.add(filters.Bounds.MinLon)
+		minLatArg := args.add(filters.Bounds.MinLat)
+		maxLonArg := args.add(filters.Bounds.MaxLon)
+		maxLatArg := args.add(filters.Bounds.MaxLat)
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf(
+			"ST_Intersects(s.location::geometry, ST_MakeEnvelope(%s, %s, %s, %s, 4326))",
+			minLonArg, minLatArg, maxLonArg, maxLatArg,
+		))
+	}
+	if filters.AreaGeoJSON != "" {
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf(
+			"ST_Intersects(s.location::geometry, ST_MakeValid(ST_SetSRID(ST_GeomFromGeoJSON(%s), 4326)))",
+			args.add(filters.AreaGeoJSON),
+		))
+	}
+	if filters.AreaID > 0 {
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM areas a WHERE a.id = %s AND ST_Intersects(a.geom, s.location::geometry))",
+			args.add(filters.AreaID),
+		))
+	}
+	if len(filters.ChainIDs) > 0 {
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf("s.chain_id = ANY(%s)", args.add(pq.Array(filters.ChainIDs))))
+	}
+	if filters.OpenAt != nil {
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf("store_is_open(s.id, %s)", args.add(*filters.OpenAt)))
+	}
+	if filters.HasPriceFilters() {
+		compiled.conditions = append(compiled.conditions, "price_summary.min_price IS NOT NULL")
+	}
+	if len(filters.ProductIDs) > 0 {
+		compiled.conditions = append(compiled.conditions, fmt.Sprintf(`(
+			SELECT COUNT(DISTINCT hp.product_id)
+			FROM prices hp
+			WHERE hp.store_id = s.id AND hp.status = 'active' AND hp.product_id = ANY(%s)
+		) = %s`, args.add(pq.Array(filters.ProductIDs)), args.add(len(filters.ProductIDs))))
+	}
+
+	return compiled
+}
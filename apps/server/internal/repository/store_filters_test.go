@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
+func TestCompileStoreFiltersBindsValues(t *testing.T) {
+	minPrice := 100.0
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{
+		Query:              "牛乳'; DROP TABLE stores; --",
+		Categories:         []string{"乳製品", "飲料"},
+		MinPrice:           &minPrice,
+		ProductIDs:         []int{1, 2},
+		RecordedWithinDays: 7,
+	}, args)
+
+	sql := compiled.priceJoin + compiled.whereClause()
+	if strings.Contains(sql, "DROP TABLE") || strings.Contains(sql, "乳製品") {
+		t.Fatalf("filter values must be bound, got SQL: %s", sql)
+	}
+	if len(args.values) != 8 {
+		t.Fatalf("expected 8 bound args, got %d", len(args.values))
+	}
+	if !strings.Contains(sql, "price_summary.min_price IS NOT NULL") {
+		t.Fatalf("expected price filters to require a matching price")
+	}
+	if !strings.Contains(sql, "pr.category_id IN (SELECT category_subtree($1))") {
+		t.Fatalf("expected categories to match their subcategories, got SQL: %s", sql)
+	}
+}
+
+func TestCompileStoreFiltersWithoutFilters(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{}, args)
+
+	if compiled.whereClause() != "" {
+		t.Fatalf("expected no conditions, got %q", compiled.whereClause())
+	}
+	if len(args.values) != 2 || args.values[0] != 1 || args.values[1] != false {
+		t.Fatalf("expected only a single non-member purchase to be bound, got %v", args.values)
+	}
+}
+
+func TestCompileStoreFiltersBindsArea(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{
+		AreaGeoJSON: `{"type":"MultiPolygon","coordinates":[]}`,
+		AreaID:      3,
+	}, args)
+
+	sql := compiled.whereClause()
+	if strings.Contains(sql, "MultiPolygon") {
+		t.Fatalf("area geometry must be bound, got SQL: %s", sql)
+	}
+	if len(args.values) != 4 {
+		t.Fatalf("expected 4 bound args, got %d", len(args.values))
+	}
+}
+
+func TestCompileStoreFiltersConvertsCurrency(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{Currency: "USD"}, args)
+
+	if !strings.Contains(compiled.priceJoin, "convert_price(effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2), p.currency, $3") {
+		t.Fatalf("expected converted minimum price, got SQL: %s", compiled.priceJoin)
+	}
+	if len(args.values) != 3 || args.values[2] != "USD" {
+		t.Fatalf("expected currency to be bound, got %v", args.values)
+	}
+}
+
+func TestCompileStoreFiltersExcludesTax(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{Tax: query.TaxExcluded}, args)
+
+	if !strings.Contains(compiled.priceJoin, "MIN(exclude_tax(effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2), p.tax_rate))") {
+		t.Fatalf("expected tax-exclusive minimum price, got SQL: %s", compiled.priceJoin)
+	}
+}
+
+func TestCompileStoreFiltersSkipsLowConfidencePrices(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{MinConfidence: 0.3}, args)
+
+	if !strings.Contains(compiled.priceJoin, "price_confidence(p.base_confidence, p.recorded_at) >= $1") {
+		t.Fatalf("expected confidence threshold in price join, got SQL: %s", compiled.priceJoin)
+	}
+	if !strings.Contains(compiled.priceJoin, "p.recorded_at >= price_confidence_horizon($1)") {
+		t.Fatalf("expected a recorded_at bound for partition pruning, got SQL: %s", compiled.priceJoin)
+	}
+	if len(args.values) != 3 || args.values[0] != 0.3 {
+		t.Fatalf("expected bound threshold, got %v", args.values)
+	}
+	if strings.Contains(compiled.whereClause(), "price_summary.min_price IS NOT NULL") {
+		t.Fatalf("expected stores without confident prices to remain listed")
+	}
+}
+
+func TestCompileStoreFiltersBoundsEachPrice(t *testing.T) {
+	minPrice, maxPrice := 100.0, 300.0
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{MinPrice: &minPrice, MaxPrice: &maxPrice}, args)
+
+	if !strings.Contains(compiled.priceJoin, "effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2) >= $3 AND effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2) <= $4") {
+		t.Fatalf("expected bounds on each price in the price join, got SQL: %s", compiled.priceJoin)
+	}
+	if strings.Contains(compiled.whereClause(), "price_summary.min_price >=") {
+		t.Fatalf("expected no bounds on the store minimum, got SQL: %s", compiled.whereClause())
+	}
+}
+
+func TestCompileStoreFiltersUsesEffectivePrices(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{Purchase: query.Purchase{Quantity: 3, Member: true}}, args)
+
+	if !strings.Contains(compiled.priceJoin, "MIN(effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2)) AS min_price") {
+		t.Fatalf("expected the store minimum to be an effective price, got SQL: %s", compiled.priceJoin)
+	}
+	if len(args.values) != 2 || args.values[0] != 3 || args.values[1] != true {
+		t.Fatalf("expected the purchase to be bound, got %v", args.values)
+	}
+}
+
+func TestCompileStoreFiltersComparesUnitPricesWithinOneBasis(t *testing.T) {
+	args := &argList{}
+	compiled := compileStoreFilters(query.StoreFilters{}, args)
+
+	if !strings.Contains(compiled.priceJoin, "CASE WHEN COUNT(DISTINCT unit_price_basis(pr.package_unit)) = 1") ||
+		!strings.Contains(compiled.priceJoin, "THEN MIN(unit_price(effective_price(p.price, p.currency, p.store_id, p.product_id, p.recorded_at, $1, $2), pr.package_size, pr.package_unit)) END AS min_unit_price,") {
+		t.Fatalf("expected the unit price minimum to require a single basis, got SQL: %s", compiled.priceJoin)
+	}
+	if !strings.Contains(compiled.priceJoin, "AS min_unit_price_basis") {
+		t.Fatalf("expected the unit price basis to be exposed, got SQL: %s", compiled.priceJoin)
+	}
+}
+
+func TestUnitPriceSortsGroupByBasis(t *testing.T) {
+	if got := storeOrderClause("unit_price", "ASC"); got != "min_unit_price_basis NULLS LAST, min_unit_price ASC NULLS LAST" {
+		t.Fatalf("expected stores grouped by unit price basis, got %q", got)
+	}
+	if got := priceOrderColumn("unit_price"); got != "unit_price_basis NULLS LAST, unit_price" {
+		t.Fatalf("expected prices grouped by unit price basis, got %q", got)
+	}
+}
+
+func TestRollupCategoryClauseMatchesSubcategories(t *testing.T) {
+	args := &argList{}
+	args.add(1)
+	clause := rollupCategoryClause([]string{"dairy"}, args)
+
+	if clause != "d.category_id IN (SELECT category_subtree($2))" {
+		t.Fatalf("expected rollup categories to match the subtree, got %q", clause)
+	}
+	if len(args.values) != 2 {
+		t.Fatalf("expected the category to be bound, got %v", args.values)
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// hoursExceptionWindowDays limits exceptions on store responses to upcoming dates
+const hoursExceptionWindowDays = 30
+
+// attachOpeningHours loads weekly schedules and upcoming exceptions for the
+// given stores in two queries and sets them on each store in place
+func attachOpeningHours(db *sql.DB, stores []domain.Store) error {
+	if len(stores) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(stores))
+	index := make(map[int]int, len(stores))
+	for i, store := range stores {
+		ids[i] = int64(store.ID)
+		index[store.ID] = i
+	}
+
+	rows, err := db.Query(`
+		SELECT store_id, weekday, opens_at, closes_at
+		FROM store_opening_hours
+		WHERE store_id = ANY($1)
+		ORDER BY store_id, weekday, opens_at
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query opening hours: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var storeID int
+		var period domain.OpeningPeriod
+		var opens, closes time.Time
+		if err := rows.Scan(&storeID, &period.Weekday, &opens, &closes); err != nil {
+			return fmt.Errorf("failed to scan opening hours: %w", err)
+		}
+		period.Opens = opens.Format("15:04")
+		period.Closes = closes.Format("15:04")
+		store := &stores[index[storeID]]
+		store.OpeningHours = append(store.OpeningHours, period)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read opening hours: %w", err)
+	}
+
+	exceptionRows, err := db.Query(`
+		SELECT e.store_id, e.date, e.closed, e.opens_at, e.closes_at, e.note
+		FROM store_hours_exceptions e
+		JOIN stores s ON s.id = e.store_id
+		WHERE e.store_id = ANY($1)
+		  AND e.date >= (NOW() AT TIME ZONE s.timezone)::date
+		  AND e.date < (NOW() AT TIME ZONE s.timezone)::date + $2::int
+		ORDER BY e.store_id, e.date, e.opens_at
+	`, pq.Array(ids), hoursExceptionWindowDays)
+	if err != nil {
+		return fmt.Errorf("failed to query hours exceptions: %w", err)
+	}
+	defer exceptionRows.Close()
+
+	for exceptionRows.Next() {
+		var storeID int
+		var exception domain.HoursException
+		var date time.Time
+		var opens, closes sql.NullTime
+		var note sql.NullString
+		if err := exceptionRows.Scan(&storeID, &date, &exception.Closed, &opens, &closes, &note); err != nil {
+			return fmt.Errorf("failed to scan hours exception: %w", err)
+		}
+		exception.Date = date.Format("2006-01-02")
+		if opens.Valid {
+			exception.Opens = opens.Time.Format("15:04")
+		}
+		if closes.Valid {
+			exception.Closes = closes.Time.Format("15:04")
+		}
+		exception.Note = note.String
+		store := &stores[index[storeID]]
+		store.HoursExceptions = append(store.HoursExceptions, exception)
+	}
+	if err := exceptionRows.Err(); err != nil {
+		return fmt.Errorf("failed to read hours exceptions: %w", err)
+	}
+
+	return nil
+}
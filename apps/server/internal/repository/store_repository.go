@@ -3,7 +3,6 @@ package repository
 import (
 	"database/sql"
 	"fmt"
-	"strings"
 
 	"github.com/price-comparison/server/internal/domain"
 	"github.com/price-comparison/server/internal/query"
@@ -18,29 +17,46 @@ func NewStoreRepository(db *sql.DB) *StoreRepository {
 }
 
 // FindNearby finds stores within a specified radius (in meters) from a given point
-func (r *StoreRepository) FindNearby(lat, lon float64, radiusMeters int, limit, offset int) ([]domain.Store, error) {
-	query := `
+func (r *StoreRepository) FindNearby(lat, lon float64, radiusMeters int, filters query.NearbyFilters, limit, offset int) ([]domain.Store, error) {
+	args := &argList{}
+	latArg := args.add(lat)
+	lonArg := args.add(lon)
+	radiusArg := args.add(radiusMeters)
+	pointExpr := fmt.Sprintf("ST_GeographyFromText('POINT(' || %s || ' ' || %s || ')')", lonArg, latArg)
+
+	isOpenExpr := "NULL::boolean"
+	openCondition := ""
+	if filters.OpenAt != nil {
+		isOpenExpr = fmt.Sprintf("store_is_open(s.id, %s)", args.add(*filters.OpenAt))
+		openCondition = "AND " + isOpenExpr
+	}
+
+	limitArg := args.add(limit)
+	offsetArg := args.add(offset)
+
+	query := fmt.Sprintf(`
 		SELECT
-			id,
-			name,
-			address,
-			phone,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
-			ST_Distance(location, ST_GeographyFromText('POINT(' || $2 || ' ' || $1 || ')')) as distance,
-			created_at,
-			updated_at
-		FROM stores
-		WHERE ST_DWithin(
-			location,
-			ST_GeographyFromText('POINT(' || $2 || ' ' || $1 || ')'),
-			$3
-		)
+			s.id,
+			s.name,
+			s.address,
+			s.phone,
+			ST_Y(s.location::geometry) as latitude,
+			ST_X(s.location::geometry) as longitude,
+			s.timezone,
+			%[7]s,
+			%[1]s as is_open,
+			ST_Distance(s.location, %[2]s) as distance,
+			s.created_at,
+			s.updated_at
+		FROM stores s
+		%[8]s
+		WHERE ST_DWithin(s.location, %[2]s, %[3]s)
+		%[4]s
 		ORDER BY distance
-		LIMIT $4 OFFSET $5
-	`
+		LIMIT %[5]s OFFSET %[6]s
+	`, isOpenExpr, pointExpr, radiusArg, openCondition, limitArg, offsetArg, chainColumns, chainJoin)
 
-	rows, err := r.db.Query(query, lat, lon, radiusMeters, limit, offset)
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query nearby stores: %w", err)
 	}
@@ -51,6 +67,8 @@ func (r *StoreRepository) FindNearby(lat, lon float64, radiusMeters int, limit,
 		var store domain.Store
 		var distance float64
 		var phone sql.NullString
+		var isOpen sql.NullBool
+		var chain chainColumnValues
 		err := rows.Scan(
 			&store.ID,
 			&store.Name,
@@ -58,6 +76,12 @@ func (r *StoreRepository) FindNearby(lat, lon float64, radiusMeters int, limit,
 			&phone,
 			&store.Latitude,
 			&store.Longitude,
+			&store.Timezone,
+			&chain.id,
+			&chain.name,
+			&chain.slug,
+			&chain.logoURL,
+			&isOpen,
 			&distance,
 			&store.CreatedAt,
 			&store.UpdatedAt,
@@ -68,100 +92,64 @@ func (r *StoreRepository) FindNearby(lat, lon float64, radiusMeters int, limit,
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan store: %w", err)
 		}
+		if isOpen.Valid {
+			store.IsOpen = &isOpen.Bool
+		}
+		store.Chain = chain.toDomain()
 		store.Distance = &distance
 		stores = append(stores, store)
 	}
 
+	if err := attachOpeningHours(r.db, stores); err != nil {
+		return nil, err
+	}
+
 	return stores, nil
 }
 
+// storeOrderClause maps a normalized store sort to an ORDER BY clause over
+// the FindAll columns. Unit prices are grouped by basis so only prices per
+// the same unit are compared.
+func storeOrderClause(sortField, sortOrder string) string {
+	switch sortField {
+	case "created_at":
+		return fmt.Sprintf("s.created_at %s", sortOrder)
+	case "distance":
+		return fmt.Sprintf("distance %s NULLS LAST", sortOrder)
+	case "price":
+		return fmt.Sprintf("min_price %s NULLS LAST", sortOrder)
+	case "unit_price":
+		return fmt.Sprintf("min_unit_price_basis NULLS LAST, min_unit_price %s NULLS LAST", sortOrder)
+	case "competitiveness":
+		return fmt.Sprintf("competitiveness %s NULLS LAST", sortOrder)
+	default:
+		return fmt.Sprintf("s.name %s", sortOrder)
+	}
+}
+
 // FindAll returns all stores with filters
 func (r *StoreRepository) FindAll(filters query.StoreFilters, limit, offset int, sortField, sortOrder string) ([]domain.Store, error) {
-	var args []interface{}
-	addArg := func(value interface{}) string {
-		args = append(args, value)
-		return fmt.Sprintf("$%d", len(args))
-	}
+	args := &argList{}
 
 	distanceExpr := "NULL"
 	if filters.UserLocation != nil {
-		lonArg := addArg(filters.UserLocation.Lon)
-		latArg := addArg(filters.UserLocation.Lat)
+		lonArg := args.add(filters.UserLocation.Lon)
+		latArg := args.add(filters.UserLocation.Lat)
 		pointExpr := fmt.Sprintf("ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography", lonArg, latArg)
 		distanceExpr = fmt.Sprintf("ST_Distance(s.location, %s)", pointExpr)
 	}
 
-	minPriceExpr := "NULL"
-	priceJoin := ""
-	{
-		categoryClause := ""
-		queryClause := ""
-		joinProducts := ""
-		if filters.Category != "" || filters.Query != "" {
-			joinProducts = "JOIN products pr ON pr.id = p.product_id"
-		}
-		if filters.Category != "" {
-			categoryArg := addArg(filters.Category)
-			categoryClause = fmt.Sprintf("AND pr.category = %s", categoryArg)
-		}
-		if filters.Query != "" {
-			queryArg := addArg("%" + filters.Query + "%")
-			queryClause = fmt.Sprintf("AND pr.name ILIKE %s", queryArg)
-		}
-
-		priceJoin = fmt.Sprintf(`
-		LEFT JOIN LATERAL (
-			SELECT MIN(p.price) AS min_price
-			FROM prices p
-			%s
-			WHERE p.store_id = s.id %s %s
-		) price_summary ON true
-		`, joinProducts, categoryClause, queryClause)
-		minPriceExpr = "price_summary.min_price"
-	}
+	compiled := compileStoreFilters(filters, args)
 
-	var conditions []string
-	if filters.Bounds != nil {
-		minLonArg := addArg(filters.Bounds.MinLon)
-		minLatArg := addArg(filters.Bounds.MinLat)
-		maxLonArg := addArg(filters.Bounds.MaxLon)
-		maxLatArg := addArg(filters.Bounds.MaxLat)
-		conditions = append(conditions, fmt.Sprintf(
-			"ST_Intersects(s.location::geometry, ST_MakeEnvelope(%s, %s, %s, %s, 4326))",
-			minLonArg, minLatArg, maxLonArg, maxLatArg,
-		))
-	}
-	if filters.Category != "" || filters.Query != "" {
-		conditions = append(conditions, "price_summary.min_price IS NOT NULL")
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
-
-	orderBy := "s.name"
-	nulls := ""
-	switch sortField {
-	case "created_at":
-		orderBy = "s.created_at"
-	case "distance":
-		orderBy = "distance"
-		nulls = "NULLS LAST"
-	case "price":
-		orderBy = "min_price"
-		nulls = "NULLS LAST"
-	default:
-		orderBy = "s.name"
+	isOpenExpr := "NULL::boolean"
+	if filters.OpenAt != nil {
+		isOpenExpr = "true"
 	}
 
-	orderClause := fmt.Sprintf("%s %s", orderBy, sortOrder)
-	if nulls != "" {
-		orderClause = fmt.Sprintf("%s %s", orderClause, nulls)
-	}
+	orderClause := storeOrderClause(sortField, sortOrder)
 
-	limitArg := addArg(limit)
-	offsetArg := addArg(offset)
+	limitArg := args.add(limit)
+	offsetArg := args.add(offset)
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -171,18 +159,26 @@ func (r *StoreRepository) FindAll(filters query.StoreFilters, limit, offset int,
 			s.phone,
 			ST_Y(s.location::geometry) as latitude,
 			ST_X(s.location::geometry) as longitude,
+			s.timezone,
+			%s,
+			%s as is_open,
 			%s as distance,
-			%s as min_price,
+			price_summary.min_price as min_price,
+			price_summary.min_unit_price as min_unit_price,
+			price_summary.min_unit_price_basis as min_unit_price_basis,
+			sc.score as competitiveness,
 			s.created_at,
 			s.updated_at
 		FROM stores s
 		%s
+		LEFT JOIN store_competitiveness sc ON sc.store_id = s.id
+		%s
 		%s
 		ORDER BY %s
 		LIMIT %s OFFSET %s
-	`, distanceExpr, minPriceExpr, priceJoin, whereClause, orderClause, limitArg, offsetArg)
+	`, chainColumns, isOpenExpr, distanceExpr, chainJoin, compiled.priceJoin, compiled.whereClause(), orderClause, limitArg, offsetArg)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, args.values...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query stores: %w", err)
 	}
@@ -193,7 +189,12 @@ func (r *StoreRepository) FindAll(filters query.StoreFilters, limit, offset int,
 		var store domain.Store
 		var distance sql.NullFloat64
 		var minPrice sql.NullFloat64
+		var minUnitPrice sql.NullFloat64
+		var minUnitPriceBasis sql.NullString
+		var competitiveness sql.NullFloat64
 		var phone sql.NullString
+		var isOpen sql.NullBool
+		var chain chainColumnValues
 		err := rows.Scan(
 			&store.ID,
 			&store.Name,
@@ -201,8 +202,17 @@ func (r *StoreRepository) FindAll(filters query.StoreFilters, limit, offset int,
 			&phone,
 			&store.Latitude,
 			&store.Longitude,
+			&store.Timezone,
+			&chain.id,
+			&chain.name,
+			&chain.slug,
+			&chain.logoURL,
+			&isOpen,
 			&distance,
 			&minPrice,
+			&minUnitPrice,
+			&minUnitPriceBasis,
+			&competitiveness,
 			&store.CreatedAt,
 			&store.UpdatedAt,
 		)
@@ -218,30 +228,99 @@ func (r *StoreRepository) FindAll(filters query.StoreFilters, limit, offset int,
 		if minPrice.Valid {
 			store.MinPrice = &minPrice.Float64
 		}
+		if minUnitPrice.Valid && minUnitPriceBasis.Valid {
+			store.MinUnitPrice = &minUnitPrice.Float64
+			store.MinUnitPriceBasis = minUnitPriceBasis.String
+		}
+		if competitiveness.Valid {
+			store.Competitiveness = &competitiveness.Float64
+		}
+		if isOpen.Valid {
+			store.IsOpen = &isOpen.Bool
+		}
+		store.Chain = chain.toDomain()
 		stores = append(stores, store)
 	}
 
+	if err := attachOpeningHours(r.db, stores); err != nil {
+		return nil, err
+	}
+
 	return stores, nil
 }
 
+// FindClusters groups the stores matching filters into square cells of
+// cellSizeMeters on the Web Mercator plane
+func (r *StoreRepository) FindClusters(filters query.StoreFilters, cellSizeMeters float64) ([]domain.StoreCluster, error) {
+	args := &argList{}
+	compiled := compileStoreFilters(filters, args)
+	cellArg := args.add(cellSizeMeters)
+
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*) as store_count,
+			ST_Y(ST_Centroid(ST_Collect(s.location::geometry))) as latitude,
+			ST_X(ST_Centroid(ST_Collect(s.location::geometry))) as longitude,
+			MIN(price_summary.min_price) as min_price,
+			MIN(s.id) as store_id
+		FROM stores s
+		%s
+		%s
+		GROUP BY ST_SnapToGrid(ST_Transform(s.location::geometry, 3857), %s)
+		ORDER BY store_count DESC
+	`, compiled.priceJoin, compiled.whereClause(), cellArg)
+
+	rows, err := r.db.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store clusters: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := []domain.StoreCluster{}
+	for rows.Next() {
+		var cluster domain.StoreCluster
+		var minPrice sql.NullFloat64
+		var storeID int
+		if err := rows.Scan(&cluster.Count, &cluster.Latitude, &cluster.Longitude, &minPrice, &storeID); err != nil {
+			return nil, fmt.Errorf("failed to scan store cluster: %w", err)
+		}
+		if minPrice.Valid {
+			cluster.MinPrice = &minPrice.Float64
+		}
+		if cluster.Count == 1 {
+			cluster.StoreID = &storeID
+		}
+		clusters = append(clusters, cluster)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read store clusters: %w", err)
+	}
+
+	return clusters, nil
+}
+
 // FindByID finds a store by its ID
 func (r *StoreRepository) FindByID(id int) (*domain.Store, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
-			id,
-			name,
-			address,
-			phone,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
-			created_at,
-			updated_at
-		FROM stores
-		WHERE id = $1
-	`
+			s.id,
+			s.name,
+			s.address,
+			s.phone,
+			ST_Y(s.location::geometry) as latitude,
+			ST_X(s.location::geometry) as longitude,
+			s.timezone,
+			%s,
+			s.created_at,
+			s.updated_at
+		FROM stores s
+		%s
+		WHERE s.id = $1
+	`, chainColumns, chainJoin)
 
 	var store domain.Store
 	var phone sql.NullString
+	var chain chainColumnValues
 	err := r.db.QueryRow(query, id).Scan(
 		&store.ID,
 		&store.Name,
@@ -249,6 +328,11 @@ func (r *StoreRepository) FindByID(id int) (*domain.Store, error) {
 		&phone,
 		&store.Latitude,
 		&store.Longitude,
+		&store.Timezone,
+		&chain.id,
+		&chain.name,
+		&chain.slug,
+		&chain.logoURL,
 		&store.CreatedAt,
 		&store.UpdatedAt,
 	)
@@ -261,6 +345,12 @@ func (r *StoreRepository) FindByID(id int) (*domain.Store, error) {
 	if phone.Valid {
 		store.Phone = phone.String
 	}
+	store.Chain = chain.toDomain()
+
+	stores := []domain.Store{store}
+	if err := attachOpeningHours(r.db, stores); err != nil {
+		return nil, err
+	}
 
-	return &store, nil
+	return &stores[0], nil
 }
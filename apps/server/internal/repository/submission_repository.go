@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+// SubmissionRepository stores crowdsourced price submissions and the
+// contributors who send them
+type SubmissionRepository struct {
+	db *sql.DB
+}
+
+func NewSubmissionRepository(db *sql.DB) *SubmissionRepository {
+	return &SubmissionRepository{db: db}
+}
+
+const contributorColumns = `
+	c.id, c.external_id, c.display_name, c.role, c.store_id,
+	c.approved_count, c.rejected_count, c.reputation, c.created_at, c.updated_at
+`
+
+// submissionColumns selects submissions aliased ps with their contributor
+// aliased c; it must stay in sync with scanSubmission
+const submissionColumns = `
+	ps.id, ps.contributor_id, ps.store_id, ps.product_id, ps.price, ps.currency,
+	ps.tax_included, ps.photo_url, ps.note, ps.observed_at, ps.status, ps.auto_approved,
+	ps.review_note, ps.reviewed_at, ps.reviewed_by, ps.price_id, ps.created_at,
+` + contributorColumns
+
+// UpsertContributor finds the contributor with externalID, registering a
+// new shopper on first sight. A non-empty displayName replaces the stored one.
+func (r *SubmissionRepository) UpsertContributor(externalID, displayName string) (*domain.Contributor, error) {
+	var name interface{}
+	if displayName != "" {
+		name = displayName
+	}
+
+	contributor, err := scanContributor(r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO contributors AS c (external_id, display_name)
+		VALUES ($1, $2)
+		ON CONFLICT (external_id) DO UPDATE
+		SET display_name = COALESCE(EXCLUDED.display_name, c.display_name),
+			updated_at = CASE
+				WHEN EXCLUDED.display_name IS DISTINCT FROM c.display_name AND EXCLUDED.display_name IS NOT NULL
+				THEN CURRENT_TIMESTAMP ELSE c.updated_at
+			END
+		RETURNING %s
+	`, contributorColumns), externalID, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert contributor: %w", err)
+	}
+	return &contributor, nil
+}
+
+// FindContributors lists contributors, most reputable first
+func (r *SubmissionRepository) FindContributors(limit, offset int) ([]domain.Contributor, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM contributors c
+		ORDER BY c.reputation DESC, c.approved_count DESC, c.id
+		LIMIT $1 OFFSET $2
+	`, contributorColumns), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contributors: %w", err)
+	}
+	defer rows.Close()
+
+	var contributors []domain.Contributor
+	for rows.Next() {
+		contributor, err := scanContributor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, contributor)
+	}
+
+	return contributors, nil
+}
+
+// FindContributorByID finds a contributor by ID, returning nil when missing
+func (r *SubmissionRepository) FindContributorByID(id int) (*domain.Contributor, error) {
+	contributor, err := scanContributor(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		FROM contributors c
+		WHERE c.id = $1
+	`, contributorColumns), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find contributor: %w", err)
+	}
+	return &contributor, nil
+}
+
+// UpdateContributorRole sets a contributor's role and, for store managers,
+// their store. It returns nil when no contributor has the ID or the store
+// does not exist.
+func (r *SubmissionRepository) UpdateContributorRole(id int, role string, storeID *int) (*domain.Contributor, error) {
+	contributor, err := scanContributor(r.db.QueryRow(fmt.Sprintf(`
+		UPDATE contributors c
+		SET role = $2, store_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE c.id = $1
+			AND ($3::int IS NULL OR EXISTS (SELECT 1 FROM stores WHERE id = $3))
+		RETURNING %s
+	`, contributorColumns), id, role, storeID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update contributor: %w", err)
+	}
+	return &contributor, nil
+}
+
+// Create saves a pending submission, defaulting ObservedAt to now when
+// zero. It returns nil when the store or product does not exist.
+func (r *SubmissionRepository) Create(submission domain.PriceSubmission) (*domain.PriceSubmission, error) {
+	var observedAt interface{}
+	if !submission.ObservedAt.IsZero() {
+		observedAt = submission.ObservedAt
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO price_submissions (
+			contributor_id, store_id, product_id, price, currency, tax_included,
+			photo_url, note, observed_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, CURRENT_TIMESTAMP)
+		WHERE EXISTS (SELECT 1 FROM stores WHERE id = $2)
+			AND EXISTS (SELECT 1 FROM products WHERE id = $3)
+		RETURNING id
+	`,
+		submission.ContributorID,
+		submission.StoreID,
+		submission.ProductID,
+		submission.Price,
+		submission.Currency,
+		submission.TaxIncluded,
+		nullableString(submission.PhotoURL),
+		nullableString(submission.Note),
+		observedAt,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create price submission: %w", err)
+	}
+	return r.FindByID(id)
+}
+
+// FindAll lists submissions, oldest first so moderators work through the
+// queue in order, optionally narrowed to a status, store and contributor
+func (r *SubmissionRepository) FindAll(status string, storeID, contributorID int, limit, offset int) ([]domain.PriceSubmission, error) {
+	args := &argList{}
+	var conditions []string
+	if status != "" {
+		conditions = append(conditions, fmt.Sprintf("ps.status = %s", args.add(status)))
+	}
+	if storeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("ps.store_id = %s", args.add(storeID)))
+	}
+	if contributorID > 0 {
+		conditions = append(conditions, fmt.Sprintf("ps.contributor_id = %s", args.add(contributorID)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM price_submissions ps
+		JOIN contributors c ON c.id = ps.contributor_id
+		%s
+		ORDER BY ps.created_at, ps.id
+		LIMIT %s OFFSET %s
+	`, submissionColumns, where, args.add(limit), args.add(offset))
+
+	rows, err := r.db.Query(listQuery, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []domain.PriceSubmission
+	for rows.Next() {
+		submission, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price submission: %w", err)
+		}
+		submissions = append(submissions, submission)
+	}
+
+	return submissions, nil
+}
+
+// FindByID finds a submission by its ID, returning nil when missing
+func (r *SubmissionRepository) FindByID(id int) (*domain.PriceSubmission, error) {
+	submission, err := scanSubmission(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s
+		FROM price_submissions ps
+		JOIN contributors c ON c.id = ps.contributor_id
+		WHERE ps.id = $1
+	`, submissionColumns), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find price submission: %w", err)
+	}
+	return &submission, nil
+}
+
+// Review settles a pending submission in one statement: an approval
+// records it as a price with review.SourceType, and moderator decisions
+// (not automatic ones) count towards the contributor's reputation. It
+// returns nil when no pending submission has the ID.
+func (r *SubmissionRepository) Review(id int, review domain.SubmissionReview) (*domain.PriceSubmission, error) {
+	approved := review.Status == domain.SubmissionStatusApproved
+
+	var reviewedID int
+	err := r.db.QueryRow(`
+		WITH target AS (
+			SELECT *
+			FROM price_submissions
+			WHERE id = $1 AND status = 'pending'
+			FOR UPDATE
+		),
+		created AS (
+			INSERT INTO prices (store_id, product_id, price, currency, tax_included, recorded_at, source_type, contributor_id, source)
+			SELECT t.store_id, t.product_id, t.price, t.currency, t.tax_included, t.observed_at, $4, t.contributor_id, 'user:' || c.external_id
+			FROM target t
+			JOIN contributors c ON c.id = t.contributor_id
+			WHERE $3
+			RETURNING id
+		),
+		reviewed AS (
+			UPDATE price_submissions ps
+			SET status = $2,
+				auto_approved = $5,
+				review_note = $6,
+				reviewed_at = CURRENT_TIMESTAMP,
+				reviewed_by = $7,
+				price_id = (SELECT id FROM created)
+			FROM target
+			WHERE ps.id = target.id
+			RETURNING ps.id
+		),
+		counted AS (
+			UPDATE contributors c
+			SET approved_count = c.approved_count + $3::int,
+				rejected_count = c.rejected_count + (NOT $3)::int,
+				reputation = contributor_reputation(c.approved_count + $3::int, c.rejected_count + (NOT $3)::int),
+				updated_at = CURRENT_TIMESTAMP
+			FROM target
+			WHERE c.id = target.contributor_id AND NOT $5
+		)
+		SELECT id FROM reviewed
+	`,
+		id,
+		review.Status,
+		approved,
+		review.SourceType,
+		review.Auto,
+		nullableString(review.Note),
+		nullableString(review.ReviewedBy),
+	).Scan(&reviewedID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to review price submission: %w", err)
+	}
+	return r.FindByID(reviewedID)
+}
+
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func scanContributor(row rowScanner) (domain.Contributor, error) {
+	var contributor domain.Contributor
+	var displayName sql.NullString
+	var storeID sql.NullInt64
+	err := row.Scan(
+		&contributor.ID,
+		&contributor.ExternalID,
+		&displayName,
+		&contributor.Role,
+		&storeID,
+		&contributor.ApprovedCount,
+		&contributor.RejectedCount,
+		&contributor.Reputation,
+		&contributor.CreatedAt,
+		&contributor.UpdatedAt,
+	)
+	if err != nil {
+		return contributor, err
+	}
+	contributor.DisplayName = displayName.String
+	if storeID.Valid {
+		id := int(storeID.Int64)
+		contributor.StoreID = &id
+	}
+	return contributor, nil
+}
+
+func scanSubmission(row rowScanner) (domain.PriceSubmission, error) {
+	var submission domain.PriceSubmission
+	var contributor domain.Contributor
+	var photoURL sql.NullString
+	var note sql.NullString
+	var reviewNote sql.NullString
+	var reviewedAt sql.NullTime
+	var reviewedBy sql.NullString
+	var priceID sql.NullInt64
+	var displayName sql.NullString
+	var managerStoreID sql.NullInt64
+	err := row.Scan(
+		&submission.ID,
+		&submission.ContributorID,
+		&submission.StoreID,
+		&submission.ProductID,
+		&submission.Price,
+		&submission.Currency,
+		&submission.TaxIncluded,
+		&photoURL,
+		&note,
+		&submission.ObservedAt,
+		&submission.Status,
+		&submission.AutoApproved,
+		&reviewNote,
+		&reviewedAt,
+		&reviewedBy,
+		&priceID,
+		&submission.CreatedAt,
+		&contributor.ID,
+		&contributor.ExternalID,
+		&displayName,
+		&contributor.Role,
+		&managerStoreID,
+		&contributor.ApprovedCount,
+		&contributor.RejectedCount,
+		&contributor.Reputation,
+		&contributor.CreatedAt,
+		&contributor.UpdatedAt,
+	)
+	if err != nil {
+		return submission, err
+	}
+	submission.PhotoURL = photoURL.String
+	submission.Note = note.String
+	submission.ReviewNote = reviewNote.String
+	if reviewedAt.Valid {
+		submission.ReviewedAt = &reviewedAt.Time
+	}
+	submission.ReviewedBy = reviewedBy.String
+	if priceID.Valid {
+		id := int(priceID.Int64)
+		submission.PriceID = &id
+	}
+	contributor.DisplayName = displayName.String
+	if managerStoreID.Valid {
+		id := int(managerStoreID.Int64)
+		contributor.StoreID = &id
+	}
+	submission.Contributor = &contributor
+	return submission, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type SuggestionRepository struct {
+	db *sql.DB
+}
+
+func NewSuggestionRepository(db *sql.DB) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// FindByPrefix returns product names, categories and store names matching a
+// normalized prefix, ranked by prefix match first and trigram similarity second.
+// Categories also match by alias ("dairy" suggests 乳製品).
+// The context bounds the query so typeahead requests stay within budget.
+func (r *SuggestionRepository) FindByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	query := `
+		WITH product_matches AS (
+			SELECT 'product' AS type, id, name AS text,
+				CASE WHEN search_name LIKE $2 THEN 1.0 ELSE 0 END + similarity(search_name, $1) AS score
+			FROM products
+			WHERE search_name LIKE $2 OR search_name % $1
+			ORDER BY score DESC
+			LIMIT $3
+		),
+		category_matches AS (
+			SELECT 'category' AS type, NULL::int AS id, c.name AS text, MAX(m.score) AS score
+			FROM (
+				SELECT id AS category_id,
+					CASE WHEN search_name LIKE $2 THEN 1.0 ELSE 0 END + similarity(search_name, $1) AS score
+				FROM categories
+				WHERE search_name LIKE $2 OR search_name % $1
+				UNION ALL
+				SELECT category_id,
+					CASE WHEN search_alias LIKE $2 THEN 1.0 ELSE 0 END + similarity(search_alias, $1) AS score
+				FROM category_aliases
+				WHERE search_alias LIKE $2 OR search_alias % $1
+			) m
+			JOIN categories c ON c.id = m.category_id
+			GROUP BY c.id, c.name
+			ORDER BY score DESC
+			LIMIT $3
+		),
+		store_matches AS (
+			SELECT 'store' AS type, id, name AS text,
+				CASE WHEN search_name LIKE $2 THEN 1.0 ELSE 0 END + similarity(search_name, $1) AS score
+			FROM stores
+			WHERE search_name LIKE $2 OR search_name % $1
+			ORDER BY score DESC
+			LIMIT $3
+		)
+		SELECT type, id, text, score
+		FROM (
+			SELECT * FROM product_matches
+			UNION ALL
+			SELECT * FROM category_matches
+			UNION ALL
+			SELECT * FROM store_matches
+		) matches
+		ORDER BY score DESC, text
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, prefix, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	var suggestions []domain.Suggestion
+	for rows.Next() {
+		var suggestion domain.Suggestion
+		var id sql.NullInt64
+		if err := rows.Scan(&suggestion.Type, &id, &suggestion.Text, &suggestion.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		if id.Valid {
+			value := int(id.Int64)
+			suggestion.ID = &value
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
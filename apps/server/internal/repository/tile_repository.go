@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
+// StoreTileLayer is the layer name stores are encoded under in vector tiles
+const StoreTileLayer = "stores"
+
+type TileRepository struct {
+	db *sql.DB
+}
+
+func NewTileRepository(db *sql.DB) *TileRepository {
+	return &TileRepository{db: db}
+}
+
+// StoreTile renders the stores inside a tile as a Mapbox Vector Tile. Each
+// feature carries id, name, chain_id and the minimum price matching filters.
+func (r *TileRepository) StoreTile(tile query.TileCoord, filters query.StoreFilters) ([]byte, error) {
+	args := &argList{}
+	envelope := fmt.Sprintf("ST_TileEnvelope(%s, %s, %s)", args.add(tile.Z), args.add(tile.X), args.add(tile.Y))
+
+	compiled := compileStoreFilters(filters, args)
+	compiled.conditions = append([]string{
+		fmt.Sprintf("s.location::geometry && ST_Transform(%s, 4326)", envelope),
+	}, compiled.conditions...)
+
+	query := fmt.Sprintf(`
+		SELECT ST_AsMVT(features, '%s', 4096, 'geom')
+		FROM (
+			SELECT
+				ST_AsMVTGeom(ST_Transform(s.location::geometry, 3857), %s, 4096, 64, true) AS geom,
+				s.id,
+				s.name,
+				s.chain_id,
+				price_summary.min_price
+			FROM stores s
+			%s
+			%s
+		) features
+	`, StoreTileLayer, envelope, compiled.priceJoin, compiled.whereClause())
+
+	var mvt []byte
+	if err := r.db.QueryRow(query, args.values...).Scan(&mvt); err != nil {
+		return nil, fmt.Errorf("failed to render store tile: %w", err)
+	}
+	return mvt, nil
+}
@@ -4,12 +4,15 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/price-comparison/server/internal/domain"
 )
 
 type Meta struct {
-	Count  int `json:"count,omitempty"`
-	Limit  int `json:"limit,omitempty"`
-	Offset int `json:"offset,omitempty"`
+	Count       int                   `json:"count,omitempty"`
+	Limit       int                   `json:"limit,omitempty"`
+	Offset      int                   `json:"offset,omitempty"`
+	Suggestions []string              `json:"suggestions,omitempty"`
+	Location    *domain.GeocodeResult `json:"location,omitempty"` // Geocoded search origin
 }
 
 type APIError struct {
@@ -28,6 +31,7 @@ const (
 	ErrNotFound        = "NOT_FOUND"
 	ErrInternal        = "INTERNAL_ERROR"
 	ErrUnauthorized    = "UNAUTHORIZED"
+	ErrConflict        = "CONFLICT"
 )
 
 func OK(c *gin.Context, data interface{}, meta *Meta) {
@@ -0,0 +1,140 @@
+package routing
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// Mode is a way of travelling over the road network
+type Mode string
+
+const (
+	Walking Mode = "walking"
+	Cycling Mode = "cycling"
+	Driving Mode = "driving"
+)
+
+// Average speeds in meters per second. Driving uses an edge's speed limit
+// when known and falls back to an urban average otherwise.
+const (
+	walkingSpeed = 4.8 / 3.6
+	cyclingSpeed = 15.0 / 3.6
+	drivingSpeed = 30.0 / 3.6
+)
+
+func ParseMode(value string) (Mode, error) {
+	switch Mode(value) {
+	case Walking, Cycling, Driving:
+		return Mode(value), nil
+	default:
+		return "", fmt.Errorf("unknown travel mode %q", value)
+	}
+}
+
+// Speed is the default travel speed for the mode in meters per second
+func (m Mode) Speed() float64 {
+	switch m {
+	case Cycling:
+		return cyclingSpeed
+	case Driving:
+		return drivingSpeed
+	default:
+		return walkingSpeed
+	}
+}
+
+// MaxSpeed bounds how far the mode can go per second, used to limit how much
+// of the network is loaded for a time budget
+func (m Mode) MaxSpeed() float64 {
+	if m == Driving {
+		return 60.0 / 3.6
+	}
+	return m.Speed()
+}
+
+// Edge is a road segment between two network nodes
+type Edge struct {
+	Source       int64
+	Target       int64
+	LengthMeters float64
+	MaxSpeedKmh  float64
+	Oneway       bool
+}
+
+type arc struct {
+	to      int64
+	seconds float64
+}
+
+// Graph is a directed, time-weighted adjacency list for one travel mode
+type Graph struct {
+	arcs map[int64][]arc
+}
+
+func NewGraph(edges []Edge, mode Mode) *Graph {
+	g := &Graph{arcs: make(map[int64][]arc)}
+	for _, edge := range edges {
+		speed := mode.Speed()
+		if mode == Driving && edge.MaxSpeedKmh > 0 {
+			speed = edge.MaxSpeedKmh / 3.6
+			if speed > mode.MaxSpeed() {
+				speed = mode.MaxSpeed()
+			}
+		}
+		seconds := edge.LengthMeters / speed
+		g.arcs[edge.Source] = append(g.arcs[edge.Source], arc{to: edge.Target, seconds: seconds})
+		// Pedestrians may walk either way down one-way streets
+		if !edge.Oneway || mode == Walking {
+			g.arcs[edge.Target] = append(g.arcs[edge.Target], arc{to: edge.Source, seconds: seconds})
+		}
+	}
+	return g
+}
+
+// ShortestTimes runs Dijkstra from origin and returns the travel time in
+// seconds to every node reachable within budgetSeconds
+func (g *Graph) ShortestTimes(origin int64, startSeconds, budgetSeconds float64) map[int64]float64 {
+	times := map[int64]float64{origin: startSeconds}
+	if startSeconds > budgetSeconds {
+		return map[int64]float64{}
+	}
+
+	queue := &nodeQueue{{node: origin, seconds: startSeconds}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queueItem)
+		if current.seconds > times[current.node] {
+			continue
+		}
+		for _, next := range g.arcs[current.node] {
+			seconds := current.seconds + next.seconds
+			if seconds > budgetSeconds {
+				continue
+			}
+			if known, ok := times[next.to]; ok && known <= seconds {
+				continue
+			}
+			times[next.to] = seconds
+			heap.Push(queue, queueItem{node: next.to, seconds: seconds})
+		}
+	}
+
+	return times
+}
+
+type queueItem struct {
+	node    int64
+	seconds float64
+}
+
+type nodeQueue []queueItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].seconds < q[j].seconds }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
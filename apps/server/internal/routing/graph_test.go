@@ -0,0 +1,50 @@
+package routing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShortestTimesRespectsBudgetAndOneway(t *testing.T) {
+	// 1 -> 2 -> 3 is a detour around a one-way 3 -> 1 shortcut
+	edges := []Edge{
+		{Source: 1, Target: 2, LengthMeters: 400},
+		{Source: 2, Target: 3, LengthMeters: 400},
+		{Source: 3, Target: 1, LengthMeters: 100, Oneway: true},
+		{Source: 3, Target: 4, LengthMeters: 5000},
+	}
+	graph := NewGraph(edges, Cycling)
+
+	times := graph.ShortestTimes(1, 0, 600)
+	want := 800 / cyclingSpeed
+	if math.Abs(times[3]-want) > 1e-9 {
+		t.Fatalf("expected %f seconds to node 3, got %f", want, times[3])
+	}
+	if _, ok := times[4]; ok {
+		t.Fatalf("node 4 should be outside the budget")
+	}
+}
+
+func TestWalkingIgnoresOneway(t *testing.T) {
+	edges := []Edge{{Source: 3, Target: 1, LengthMeters: 100, Oneway: true}}
+
+	if _, ok := NewGraph(edges, Walking).ShortestTimes(1, 0, 600)[3]; !ok {
+		t.Fatalf("pedestrians should be able to walk against one-way streets")
+	}
+	if _, ok := NewGraph(edges, Driving).ShortestTimes(1, 0, 600)[3]; ok {
+		t.Fatalf("drivers must respect one-way streets")
+	}
+}
+
+func TestDrivingUsesCappedSpeedLimit(t *testing.T) {
+	edges := []Edge{
+		{Source: 1, Target: 2, LengthMeters: 1000, MaxSpeedKmh: 100},
+		{Source: 2, Target: 3, LengthMeters: 1000},
+	}
+	times := NewGraph(edges, Driving).ShortestTimes(1, 0, 3600)
+
+	want := 1000/Driving.MaxSpeed() + 1000/drivingSpeed
+	if math.Abs(times[3]-want) > 1e-9 {
+		t.Fatalf("expected %f seconds, got %f", want, times[3])
+	}
+}
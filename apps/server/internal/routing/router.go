@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
+// Unreachable marks a destination that cannot be reached within the budget
+const Unreachable = -1.0
+
+// Snap attaches an off-network point to its nearest road node
+type Snap struct {
+	NodeID         int64
+	DistanceMeters float64
+	Found          bool
+}
+
+// Network loads the parts of the road network needed for one search
+type Network interface {
+	LoadEdges(center query.GeoPoint, radiusMeters float64, mode Mode) ([]Edge, error)
+	NearestNodes(points []query.GeoPoint, maxDistanceMeters float64) ([]Snap, error)
+}
+
+// Router computes door-to-door travel times over a road network. Points are
+// snapped to the nearest node within snapMeters; the access legs to and from
+// the network are travelled at the mode's default speed.
+type Router struct {
+	network    Network
+	snapMeters float64
+}
+
+func NewRouter(network Network, snapMeters float64) *Router {
+	return &Router{network: network, snapMeters: snapMeters}
+}
+
+// TravelTimes returns the travel time in seconds from origin to each
+// destination, or Unreachable when it exceeds budget
+func (r *Router) TravelTimes(origin query.GeoPoint, destinations []query.GeoPoint, mode Mode, budget time.Duration) ([]float64, error) {
+	times := make([]float64, len(destinations))
+	for i := range times {
+		times[i] = Unreachable
+	}
+	if len(destinations) == 0 {
+		return times, nil
+	}
+
+	budgetSeconds := budget.Seconds()
+	snaps, err := r.network.NearestNodes(append([]query.GeoPoint{origin}, destinations...), r.snapMeters)
+	if err != nil {
+		return nil, err
+	}
+	originSnap := snaps[0]
+	if !originSnap.Found {
+		return times, nil
+	}
+
+	edges, err := r.network.LoadEdges(origin, mode.MaxSpeed()*budgetSeconds+r.snapMeters, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	speed := mode.Speed()
+	nodeTimes := NewGraph(edges, mode).ShortestTimes(originSnap.NodeID, originSnap.DistanceMeters/speed, budgetSeconds)
+	for i, snap := range snaps[1:] {
+		if !snap.Found {
+			continue
+		}
+		nodeSeconds, ok := nodeTimes[snap.NodeID]
+		if !ok {
+			continue
+		}
+		total := nodeSeconds + snap.DistanceMeters/speed
+		if total <= budgetSeconds {
+			times[i] = total
+		}
+	}
+
+	return times, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type AnomalyRepository interface {
+	FindByStatus(status string, productID int, limit, offset int) ([]domain.PriceAnomaly, error)
+	Review(id int, status, reviewer string) (*domain.PriceAnomaly, error)
+}
+
+// Review actions for quarantined prices
+const (
+	ReviewApprove = "approve"
+	ReviewReject  = "reject"
+)
+
+type AnomalyUsecase struct {
+	repo AnomalyRepository
+}
+
+func NewAnomalyUsecase(repo AnomalyRepository) *AnomalyUsecase {
+	return &AnomalyUsecase{repo: repo}
+}
+
+// List returns quarantined prices, or rejected ones when asked
+func (u *AnomalyUsecase) List(opts AnomalyListOptions) ([]domain.PriceAnomaly, error) {
+	status := opts.Status
+	if status == "" {
+		status = domain.PriceStatusQuarantined
+	}
+	if status != domain.PriceStatusQuarantined && status != domain.PriceStatusRejected {
+		return nil, fmt.Errorf("status must be %s or %s", domain.PriceStatusQuarantined, domain.PriceStatusRejected)
+	}
+	return u.repo.FindByStatus(status, opts.ProductID, normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+// Review approves a quarantined price back into listings and aggregates,
+// or rejects it for good, recording reviewer as its moderator. It returns
+// nil when no quarantined price has id.
+func (u *AnomalyUsecase) Review(id int, action, reviewer string) (*domain.PriceAnomaly, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	switch action {
+	case ReviewApprove:
+		return u.repo.Review(id, domain.PriceStatusActive, reviewer)
+	case ReviewReject:
+		return u.repo.Review(id, domain.PriceStatusRejected, reviewer)
+	default:
+		return nil, fmt.Errorf("action must be %s or %s", ReviewApprove, ReviewReject)
+	}
+}
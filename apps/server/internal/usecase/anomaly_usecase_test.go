@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type anomalyRepoStub struct {
+	lastStatus   string
+	reviewedID   int
+	reviewStatus string
+	reviewer     string
+}
+
+func (s *anomalyRepoStub) FindByStatus(status string, productID int, limit, offset int) ([]domain.PriceAnomaly, error) {
+	s.lastStatus = status
+	return []domain.PriceAnomaly{}, nil
+}
+
+func (s *anomalyRepoStub) Review(id int, status, reviewer string) (*domain.PriceAnomaly, error) {
+	s.reviewedID = id
+	s.reviewStatus = status
+	s.reviewer = reviewer
+	return &domain.PriceAnomaly{Status: status}, nil
+}
+
+func TestAnomalyListDefaultsToQuarantined(t *testing.T) {
+	stub := &anomalyRepoStub{}
+	uc := NewAnomalyUsecase(stub)
+
+	if _, err := uc.List(AnomalyListOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastStatus != domain.PriceStatusQuarantined {
+		t.Fatalf("expected quarantined, got %s", stub.lastStatus)
+	}
+	if _, err := uc.List(AnomalyListOptions{Status: domain.PriceStatusActive}); err == nil {
+		t.Fatalf("expected error when listing active prices")
+	}
+}
+
+func TestAnomalyReviewMapsActions(t *testing.T) {
+	stub := &anomalyRepoStub{}
+	uc := NewAnomalyUsecase(stub)
+
+	if _, err := uc.Review(7, ReviewApprove, "hanako"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.reviewedID != 7 || stub.reviewStatus != domain.PriceStatusActive || stub.reviewer != "hanako" {
+		t.Fatalf("expected price 7 to become active by hanako, got %d %s %q", stub.reviewedID, stub.reviewStatus, stub.reviewer)
+	}
+	if _, err := uc.Review(7, ReviewReject, "hanako"); err != nil || stub.reviewStatus != domain.PriceStatusRejected {
+		t.Fatalf("expected price to be rejected, got %s (%v)", stub.reviewStatus, err)
+	}
+	if _, err := uc.Review(7, "ignore", "hanako"); err == nil {
+		t.Fatalf("expected error for unknown action")
+	}
+}
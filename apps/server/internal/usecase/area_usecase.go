@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type AreaRepository interface {
+	Create(name, geoJSON string) (*domain.Area, error)
+	FindAll(limit, offset int) ([]domain.Area, error)
+	FindByID(id int) (*domain.Area, error)
+}
+
+type AreaUsecase struct {
+	repo AreaRepository
+}
+
+func NewAreaUsecase(repo AreaRepository) *AreaUsecase {
+	return &AreaUsecase{repo: repo}
+}
+
+// Create saves an area; GeoJSON must already be validated with geo.ParseArea
+func (u *AreaUsecase) Create(opts AreaCreateOptions) (*domain.Area, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if opts.GeoJSON == "" {
+		return nil, fmt.Errorf("geometry is required")
+	}
+	return u.repo.Create(name, opts.GeoJSON)
+}
+
+func (u *AreaUsecase) List(opts AreaListOptions) ([]domain.Area, error) {
+	return u.repo.FindAll(normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+func (u *AreaUsecase) GetByID(id int) (*domain.Area, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	return u.repo.FindByID(id)
+}
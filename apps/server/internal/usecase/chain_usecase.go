@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type ChainRepository interface {
+	FindAll(limit, offset int) ([]domain.Chain, error)
+	FindByID(id int) (*domain.Chain, error)
+}
+
+type ChainUsecase struct {
+	repo     ChainRepository
+	cache    Cache
+	cacheTTL time.Duration
+}
+
+func NewChainUsecase(repo ChainRepository, cache Cache, cacheTTL time.Duration) *ChainUsecase {
+	return &ChainUsecase{repo: repo, cache: cache, cacheTTL: cacheTTL}
+}
+
+func (u *ChainUsecase) List(opts ChainListOptions) ([]domain.Chain, error) {
+	limit := normalizeLimit(opts.Limit)
+	offset := normalizeOffset(opts.Offset)
+
+	cacheKey := fmt.Sprintf("chains:list:%d:%d", limit, offset)
+	if u.cache != nil {
+		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
+			var chains []domain.Chain
+			if err := json.Unmarshal([]byte(cached), &chains); err == nil {
+				return chains, nil
+			}
+		}
+	}
+
+	chains, err := u.repo.FindAll(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil {
+		if payload, err := json.Marshal(chains); err == nil {
+			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
+		}
+	}
+
+	return chains, nil
+}
+
+func (u *ChainUsecase) GetByID(id int) (*domain.Chain, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	return u.repo.FindByID(id)
+}
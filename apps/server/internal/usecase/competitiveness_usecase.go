@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/jobs"
+)
+
+type CompetitivenessRepository interface {
+	Refresh(radiusMeters float64, windowDays, minStores, minProducts int) (int, error)
+	FindRanking(areaID int, limit, offset int) ([]domain.StoreRanking, error)
+}
+
+// RefreshCompetitivenessJobType is the background job that recomputes
+// store competitiveness scores
+const RefreshCompetitivenessJobType = "stores.competitiveness"
+
+// RefreshCompetitivenessJob is the payload of a
+// RefreshCompetitivenessJobType job
+type RefreshCompetitivenessJob struct{}
+
+// CompetitivenessPolicy sets which prices and neighbours a store's score
+// compares; see config.CompetitivenessConfig
+type CompetitivenessPolicy struct {
+	RadiusMeters int
+	WindowDays   int
+	MinStores    int
+	MinProducts  int
+}
+
+type CompetitivenessUsecase struct {
+	repo   CompetitivenessRepository
+	policy CompetitivenessPolicy
+}
+
+func NewCompetitivenessUsecase(repo CompetitivenessRepository, policy CompetitivenessPolicy) *CompetitivenessUsecase {
+	return &CompetitivenessUsecase{repo: repo, policy: policy}
+}
+
+// Refresh recomputes every store's score. An invalid policy fails the job
+// permanently, since retrying cannot fix the configuration
+func (u *CompetitivenessUsecase) Refresh(ctx context.Context, _ RefreshCompetitivenessJob) error {
+	if err := u.policy.Validate(); err != nil {
+		return jobs.Permanent(err)
+	}
+	_, err := u.repo.Refresh(float64(u.policy.RadiusMeters), u.policy.WindowDays, u.policy.MinStores, u.policy.MinProducts)
+	return err
+}
+
+// Ranking lists the scored stores of an area, cheapest first
+func (u *CompetitivenessUsecase) Ranking(areaID int, pagination Pagination) ([]domain.StoreRanking, error) {
+	if areaID <= 0 {
+		return nil, fmt.Errorf("area id must be positive")
+	}
+	return u.repo.FindRanking(areaID, normalizeLimit(pagination.Limit), normalizeOffset(pagination.Offset))
+}
+
+// Validate reports whether the policy can score any store
+func (p CompetitivenessPolicy) Validate() error {
+	if p.RadiusMeters <= 0 || p.WindowDays <= 0 {
+		return fmt.Errorf("competitiveness radius and window must be positive")
+	}
+	if p.MinStores < 2 || p.MinProducts < 1 {
+		return fmt.Errorf("competitiveness needs at least 2 stores and 1 product to compare")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/jobs"
+)
+
+type competitivenessRepoStub struct {
+	refreshes int
+	radius    float64
+	limit     int
+}
+
+func (s *competitivenessRepoStub) Refresh(radiusMeters float64, windowDays, minStores, minProducts int) (int, error) {
+	s.refreshes++
+	s.radius = radiusMeters
+	return 0, nil
+}
+
+func (s *competitivenessRepoStub) FindRanking(areaID int, limit, offset int) ([]domain.StoreRanking, error) {
+	s.limit = limit
+	return []domain.StoreRanking{}, nil
+}
+
+func TestCompetitivenessRefreshChecksPolicy(t *testing.T) {
+	repo := &competitivenessRepoStub{}
+	policy := CompetitivenessPolicy{RadiusMeters: 5000, WindowDays: 30, MinStores: 3, MinProducts: 10}
+	if err := NewCompetitivenessUsecase(repo, policy).Refresh(context.Background(), RefreshCompetitivenessJob{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.refreshes != 1 || repo.radius != 5000 {
+		t.Fatalf("expected one refresh within 5000m, got %+v", repo)
+	}
+
+	// A store is always its own neighbour, so one store compares nothing
+	policy.MinStores = 1
+	err := NewCompetitivenessUsecase(repo, policy).Refresh(context.Background(), RefreshCompetitivenessJob{})
+	if err == nil {
+		t.Fatalf("expected error for min stores 1")
+	}
+	if !jobs.IsPermanent(err) {
+		t.Fatalf("expected an invalid policy not to be retried, got %v", err)
+	}
+	if repo.refreshes != 1 {
+		t.Fatalf("expected no refresh with an invalid policy, got %d", repo.refreshes)
+	}
+}
+
+func TestCompetitivenessRankingNormalizesPagination(t *testing.T) {
+	repo := &competitivenessRepoStub{}
+	uc := NewCompetitivenessUsecase(repo, CompetitivenessPolicy{})
+	if _, err := uc.Ranking(0, Pagination{}); err == nil {
+		t.Fatalf("expected error for area 0")
+	}
+	if _, err := uc.Ranking(4, Pagination{Limit: 1000}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.limit != MaxLimit {
+		t.Fatalf("expected limit %d, got %d", MaxLimit, repo.limit)
+	}
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type ConnectorRunRepository interface {
+	FindRuns(connector string, limit, offset int) ([]domain.ConnectorRun, error)
+}
+
+// ConnectorRunner is the connector scheduler
+type ConnectorRunner interface {
+	Jobs() []domain.ConnectorJob
+	RunNow(ctx context.Context, name string) (*domain.ConnectorRun, error)
+}
+
+type ConnectorUsecase struct {
+	repo   ConnectorRunRepository
+	runner ConnectorRunner
+}
+
+func NewConnectorUsecase(repo ConnectorRunRepository, runner ConnectorRunner) *ConnectorUsecase {
+	return &ConnectorUsecase{repo: repo, runner: runner}
+}
+
+// Jobs lists the configured connectors and when each runs next
+func (u *ConnectorUsecase) Jobs() []domain.ConnectorJob {
+	return u.runner.Jobs()
+}
+
+func (u *ConnectorUsecase) ListRuns(opts ConnectorRunListOptions) ([]domain.ConnectorRun, error) {
+	return u.repo.FindRuns(opts.Connector, normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+// Run starts the named connector outside its schedule and waits for it to
+// finish. It fails with domain.ErrConnectorNotFound or
+// domain.ErrConnectorRunning rather than recording a run.
+func (u *ConnectorUsecase) Run(ctx context.Context, name string) (*domain.ConnectorRun, error) {
+	return u.runner.RunNow(ctx, name)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type ExchangeRateRepository interface {
+	FindAll(base, quote string, limit, offset int) ([]domain.ExchangeRate, error)
+	Upsert(rate domain.ExchangeRate) error
+	Convert(amount float64, from, to string, date time.Time) (*float64, error)
+}
+
+// CurrencyUsecase manages dated exchange rates and converts amounts with them
+type CurrencyUsecase struct {
+	repo ExchangeRateRepository
+}
+
+func NewCurrencyUsecase(repo ExchangeRateRepository) *CurrencyUsecase {
+	return &CurrencyUsecase{repo: repo}
+}
+
+func (u *CurrencyUsecase) ListRates(opts ExchangeRateListOptions) ([]domain.ExchangeRate, error) {
+	base, err := normalizeCurrency(opts.Base)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := normalizeCurrency(opts.Quote)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindAll(base, quote, normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+// SaveRate records a rate, replacing one already published for the same
+// pair and date
+func (u *CurrencyUsecase) SaveRate(rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	base, err := normalizeCurrency(rate.BaseCurrency)
+	if err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: %v", domain.ErrInvalidExchangeRate, err)
+	}
+	quote, err := normalizeCurrency(rate.QuoteCurrency)
+	if err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: %v", domain.ErrInvalidExchangeRate, err)
+	}
+	if base == "" || quote == "" || base == quote {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: base and quote currencies must be two different codes", domain.ErrInvalidExchangeRate)
+	}
+	if rate.Rate <= 0 {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: rate must be positive", domain.ErrInvalidExchangeRate)
+	}
+	if rate.RateDate.IsZero() {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: rate date is required", domain.ErrInvalidExchangeRate)
+	}
+
+	rate.BaseCurrency = base
+	rate.QuoteCurrency = quote
+	rate.RateDate = truncateDate(rate.RateDate)
+	if err := u.repo.Upsert(rate); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	return rate, nil
+}
+
+// Convert converts an amount at the rate in effect on the given date
+func (u *CurrencyUsecase) Convert(opts ConvertOptions) (domain.Conversion, error) {
+	from, err := normalizeCurrency(opts.From)
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+	to, err := normalizeCurrency(opts.To)
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+	if from == "" || to == "" {
+		return domain.Conversion{}, fmt.Errorf("from and to currencies are required")
+	}
+
+	date := truncateDate(time.Now())
+	if opts.Date != nil {
+		date = truncateDate(*opts.Date)
+	}
+
+	converted, err := u.repo.Convert(opts.Amount, from, to, date)
+	if err != nil {
+		return domain.Conversion{}, err
+	}
+	if converted == nil {
+		return domain.Conversion{}, fmt.Errorf("%w: %s to %s on %s", domain.ErrExchangeRateNotFound, from, to, date.Format("2006-01-02"))
+	}
+
+	return domain.Conversion{
+		Amount:          opts.Amount,
+		FromCurrency:    from,
+		ToCurrency:      to,
+		Date:            date,
+		ConvertedAmount: *converted,
+	}, nil
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type exchangeRateRepoStub struct {
+	saved     []domain.ExchangeRate
+	converted *float64
+	lastFrom  string
+	lastTo    string
+}
+
+func (s *exchangeRateRepoStub) FindAll(base, quote string, limit, offset int) ([]domain.ExchangeRate, error) {
+	return []domain.ExchangeRate{}, nil
+}
+
+func (s *exchangeRateRepoStub) Upsert(rate domain.ExchangeRate) error {
+	s.saved = append(s.saved, rate)
+	return nil
+}
+
+func (s *exchangeRateRepoStub) Convert(amount float64, from, to string, date time.Time) (*float64, error) {
+	s.lastFrom = from
+	s.lastTo = to
+	return s.converted, nil
+}
+
+func TestSaveRateNormalizesAndValidates(t *testing.T) {
+	stub := &exchangeRateRepoStub{}
+	uc := NewCurrencyUsecase(stub)
+	date := time.Date(2024, 1, 31, 15, 4, 0, 0, time.UTC)
+
+	rate, err := uc.SaveRate(domain.ExchangeRate{BaseCurrency: "usd", QuoteCurrency: "JPY", RateDate: date, Rate: 147.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate.BaseCurrency != "USD" || !rate.RateDate.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected normalized rate, got %+v", rate)
+	}
+
+	invalid := []domain.ExchangeRate{
+		{BaseCurrency: "JPY", QuoteCurrency: "JPY", RateDate: date, Rate: 1},
+		{BaseCurrency: "USD", QuoteCurrency: "JPY", RateDate: date, Rate: 0},
+		{BaseCurrency: "US", QuoteCurrency: "JPY", RateDate: date, Rate: 1},
+		{BaseCurrency: "USD", QuoteCurrency: "JPY", Rate: 1},
+	}
+	for _, rate := range invalid {
+		if _, err := uc.SaveRate(rate); !errors.Is(err, domain.ErrInvalidExchangeRate) {
+			t.Errorf("expected ErrInvalidExchangeRate for %+v, got %v", rate, err)
+		}
+	}
+	if len(stub.saved) != 1 {
+		t.Fatalf("expected only the valid rate to be saved, got %d", len(stub.saved))
+	}
+}
+
+func TestConvertWithoutRate(t *testing.T) {
+	uc := NewCurrencyUsecase(&exchangeRateRepoStub{})
+
+	_, err := uc.Convert(ConvertOptions{Amount: 100, From: "eur", To: "jpy"})
+	if !errors.Is(err, domain.ErrExchangeRateNotFound) {
+		t.Fatalf("expected ErrExchangeRateNotFound, got %v", err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	converted := 16200.0
+	stub := &exchangeRateRepoStub{converted: &converted}
+	uc := NewCurrencyUsecase(stub)
+
+	conversion, err := uc.Convert(ConvertOptions{Amount: 100, From: "eur", To: "jpy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastFrom != "EUR" || stub.lastTo != "JPY" || conversion.ConvertedAmount != converted {
+		t.Fatalf("unexpected conversion: %+v", conversion)
+	}
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/forecast"
+	"github.com/price-comparison/server/internal/query"
+)
+
+type ForecastRepository interface {
+	FindDailyPrices(storeID, productID int, from time.Time, tax query.TaxMode) ([]domain.PeriodPrice, error)
+}
+
+const (
+	// ForecastHorizon is how many days ahead prices are forecast
+	ForecastHorizon = 7
+	// DefaultForecastHistoryDays and MaxForecastHistoryDays bound the
+	// history a forecast is fitted to
+	DefaultForecastHistoryDays = 180
+	MaxForecastHistoryDays     = 730
+)
+
+// forecastBacktestFolds is how many past weeks a forecast is backtested on
+const forecastBacktestFolds = 4
+
+// maxForecastGapDays is the longest run of days without a price that is
+// filled with the previous price, both within the history and up to today
+const maxForecastGapDays = forecast.Season
+
+// saleThreshold is how far below the week's average forecast a day must be
+// to be reported as the likely sale day
+const saleThreshold = 0.03
+
+type ForecastUsecase struct {
+	repo ForecastRepository
+}
+
+func NewForecastUsecase(repo ForecastRepository) *ForecastUsecase {
+	return &ForecastUsecase{repo: repo}
+}
+
+// Forecast predicts the product's price at the store for each of the next
+// ForecastHorizon days. Days without prices repeat the previous day's
+// price; only the currency of the latest price is used, and history before
+// a gap of more than maxForecastGapDays is ignored. It returns
+// domain.ErrInsufficientHistory when the latest price is older than
+// maxForecastGapDays, or when there are fewer than two weeks of history or
+// prices on fewer than seven days.
+func (u *ForecastUsecase) Forecast(opts PriceForecastOptions) (*domain.PriceForecast, error) {
+	if opts.StoreID <= 0 || opts.ProductID <= 0 {
+		return nil, fmt.Errorf("store id and product id must be positive")
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	historyDays := opts.HistoryDays
+	if historyDays <= 0 {
+		historyDays = DefaultForecastHistoryDays
+	}
+	if historyDays > MaxForecastHistoryDays {
+		historyDays = MaxForecastHistoryDays
+	}
+
+	today := truncateDate(time.Now())
+	prices, err := u.repo.FindDailyPrices(opts.StoreID, opts.ProductID, today.AddDate(0, 0, 1-historyDays), tax)
+	if err != nil {
+		return nil, err
+	}
+	return forecastSeries(prices, today, domain.PriceForecast{
+		StoreID:     opts.StoreID,
+		ProductID:   opts.ProductID,
+		TaxIncluded: tax.Included(),
+		HistoryDays: historyDays,
+	})
+}
+
+func forecastSeries(prices []domain.PeriodPrice, today time.Time, result domain.PriceForecast) (*domain.PriceForecast, error) {
+	if len(prices) == 0 {
+		return nil, domain.ErrInsufficientHistory
+	}
+	latest := prices[len(prices)-1]
+	result.Currency = latest.Currency
+	result.LastPrice = round2(latest.AvgPrice)
+	result.LastObserved = truncateDate(latest.PeriodStart)
+
+	if today.Sub(result.LastObserved) > maxForecastGapDays*24*time.Hour {
+		return nil, domain.ErrInsufficientHistory
+	}
+
+	// The series starts at the first price in the currency after the last
+	// gap too long to fill, so every filled day is near an observed one
+	var start, previous time.Time
+	byDay := make(map[time.Time]float64)
+	for _, price := range prices {
+		if price.Currency != result.Currency {
+			continue
+		}
+		day := truncateDate(price.PeriodStart)
+		if start.IsZero() || day.Sub(previous) > maxForecastGapDays*24*time.Hour {
+			start = day
+			byDay = make(map[time.Time]float64)
+		}
+		byDay[day] = price.AvgPrice
+		previous = day
+	}
+	result.Observations = len(byDay)
+
+	var series []float64
+	last := math.NaN()
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if price, ok := byDay[day]; ok {
+			last = price
+		}
+		series = append(series, last)
+	}
+	if len(series) < forecast.MinObservations || result.Observations < forecast.Season {
+		return nil, domain.ErrInsufficientHistory
+	}
+
+	model, err := forecast.Fit(series)
+	if err != nil {
+		return nil, domain.ErrInsufficientHistory
+	}
+
+	var total float64
+	cheapest := 0
+	for h, prediction := range model.Forecast(ForecastHorizon) {
+		result.Days = append(result.Days, domain.PriceForecastDay{
+			Date:  today.AddDate(0, 0, h+1),
+			Price: round2(prediction.Value),
+			Low:   round2(prediction.Low),
+			High:  round2(prediction.High),
+		})
+		total += prediction.Value
+		if prediction.Value < result.Days[cheapest].Price {
+			cheapest = h
+		}
+	}
+	average := total / ForecastHorizon
+	if average > 0 && (average-result.Days[cheapest].Price)/average >= saleThreshold {
+		saleDay := result.Days[cheapest].Date
+		result.LikelySaleDay = &saleDay
+	}
+
+	// Day t of the series falls on start + t
+	result.WeekdayEffects = make([]float64, forecast.Season)
+	for t := len(series); t < len(series)+forecast.Season; t++ {
+		result.WeekdayEffects[start.AddDate(0, 0, t).Weekday()] = round2(model.Effect(t))
+	}
+
+	metrics := forecast.Backtest(series, ForecastHorizon, forecastBacktestFolds)
+	result.Backtest = domain.ForecastBacktest{
+		Folds:    metrics.Folds,
+		MAE:      round2(metrics.MAE),
+		MAPE:     round2(metrics.MAPE),
+		NaiveMAE: round2(metrics.NaiveMAE),
+		Coverage: round2(metrics.Coverage),
+	}
+	return &result, nil
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+func TestForecastFindsWeeklySaleDay(t *testing.T) {
+	// Saturday 2024-06-29; Wednesdays are sale days at 150
+	today := time.Date(2024, time.June, 29, 0, 0, 0, 0, time.UTC)
+	var prices []domain.PeriodPrice
+	for day := today.AddDate(0, 0, -55); !day.After(today); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Monday {
+			// Not every day is priced; gaps repeat the previous day
+			continue
+		}
+		price := 200.0
+		if day.Weekday() == time.Wednesday {
+			price = 150
+		}
+		prices = append(prices, domain.PeriodPrice{PeriodStart: day, Currency: "JPY", AvgPrice: price, Count: 1})
+	}
+
+	forecast, err := forecastSeries(prices, today, domain.PriceForecast{StoreID: 1, ProductID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forecast.Days) != ForecastHorizon || !forecast.Days[0].Date.Equal(today.AddDate(0, 0, 1)) {
+		t.Fatalf("expected seven days from tomorrow, got %+v", forecast.Days)
+	}
+	if forecast.LikelySaleDay == nil || forecast.LikelySaleDay.Weekday() != time.Wednesday {
+		t.Fatalf("expected a Wednesday sale, got %v", forecast.LikelySaleDay)
+	}
+	if forecast.WeekdayEffects[time.Wednesday] > -30 {
+		t.Fatalf("expected a Wednesday discount, got %v", forecast.WeekdayEffects)
+	}
+	if forecast.Currency != "JPY" || forecast.LastPrice != 200 || forecast.Backtest.Folds != 4 {
+		t.Fatalf("unexpected forecast summary %+v", forecast)
+	}
+}
+
+func TestForecastWeekdaysStartAtFirstUsedPrice(t *testing.T) {
+	today := time.Date(2024, time.June, 29, 0, 0, 0, 0, time.UTC)
+	first := today.AddDate(0, 0, -41)
+	prices := []domain.PeriodPrice{
+		// Skipped: only the latest price's currency is forecast
+		{PeriodStart: first.AddDate(0, 0, -3), Currency: "USD", AvgPrice: 1.5, Count: 1},
+	}
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		price := 200.0
+		if day.Weekday() == time.Wednesday {
+			price = 150
+		}
+		prices = append(prices, domain.PeriodPrice{PeriodStart: day, Currency: "JPY", AvgPrice: price, Count: 1})
+	}
+
+	forecast, err := forecastSeries(prices, today, domain.PriceForecast{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if forecast.WeekdayEffects[time.Wednesday] > -30 {
+		t.Fatalf("expected the discount on Wednesday, got %v", forecast.WeekdayEffects)
+	}
+}
+
+func TestForecastSkipsLongGaps(t *testing.T) {
+	today := time.Date(2024, time.June, 29, 0, 0, 0, 0, time.UTC)
+	var prices []domain.PeriodPrice
+	for day := today.AddDate(0, 0, -90); day.Before(today.AddDate(0, 0, -60)); day = day.AddDate(0, 0, 1) {
+		prices = append(prices, domain.PeriodPrice{PeriodStart: day, Currency: "JPY", AvgPrice: 300, Count: 1})
+	}
+	for day := today.AddDate(0, 0, -20); !day.After(today); day = day.AddDate(0, 0, 1) {
+		prices = append(prices, domain.PeriodPrice{PeriodStart: day, Currency: "JPY", AvgPrice: 200, Count: 1})
+	}
+
+	forecast, err := forecastSeries(prices, today, domain.PriceForecast{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if forecast.Observations != 21 {
+		t.Fatalf("expected history before the gap to be ignored, got %d observations", forecast.Observations)
+	}
+
+	// A month without prices is not filled up to today
+	stale := prices[:30]
+	if _, err := forecastSeries(stale, today, domain.PriceForecast{}); !errors.Is(err, domain.ErrInsufficientHistory) {
+		t.Fatalf("expected ErrInsufficientHistory for stale prices, got %v", err)
+	}
+}
+
+func TestForecastNeedsHistory(t *testing.T) {
+	today := time.Date(2024, time.June, 29, 0, 0, 0, 0, time.UTC)
+	sparse := []domain.PeriodPrice{
+		{PeriodStart: today.AddDate(0, 0, -30), Currency: "JPY", AvgPrice: 200, Count: 1},
+		{PeriodStart: today.AddDate(0, 0, -3), Currency: "JPY", AvgPrice: 210, Count: 1},
+	}
+	for _, prices := range [][]domain.PeriodPrice{nil, sparse} {
+		if _, err := forecastSeries(prices, today, domain.PriceForecast{}); !errors.Is(err, domain.ErrInsufficientHistory) {
+			t.Fatalf("expected ErrInsufficientHistory for %+v, got %v", prices, err)
+		}
+	}
+
+	uc := NewForecastUsecase(nil)
+	if _, err := uc.Forecast(PriceForecastOptions{StoreID: 1}); err == nil {
+		t.Fatalf("expected error without a product")
+	}
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+const (
+	DefaultGeocodeLimit = 5
+	MaxGeocodeLimit     = 20
+)
+
+// Geocoder resolves addresses and postal codes to coordinates and back.
+// Implementations: the offline postal-code repository and an HTTP provider.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string, limit int) ([]domain.GeocodeResult, error)
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*domain.GeocodeResult, error)
+}
+
+type GeocodeUsecase struct {
+	geocoder Geocoder
+	cache    Cache
+	cacheTTL time.Duration
+	timeout  time.Duration
+}
+
+func NewGeocodeUsecase(geocoder Geocoder, cache Cache, cacheTTL, timeout time.Duration) *GeocodeUsecase {
+	return &GeocodeUsecase{geocoder: geocoder, cache: cache, cacheTTL: cacheTTL, timeout: timeout}
+}
+
+// Geocode returns candidate locations for an address or postal code, best
+// match first
+func (u *GeocodeUsecase) Geocode(opts GeocodeOptions) ([]domain.GeocodeResult, error) {
+	address := strings.Join(strings.Fields(opts.Query), " ")
+	if address == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultGeocodeLimit
+	}
+	if limit > MaxGeocodeLimit {
+		limit = MaxGeocodeLimit
+	}
+
+	cacheKey := fmt.Sprintf("geocode:search:%s:%d", address, limit)
+	if u.cache != nil {
+		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
+			var results []domain.GeocodeResult
+			if err := json.Unmarshal([]byte(cached), &results); err == nil {
+				return results, nil
+			}
+		}
+	}
+
+	ctx, cancel := u.context()
+	defer cancel()
+	results, err := u.geocoder.Geocode(ctx, address, limit)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []domain.GeocodeResult{}
+	}
+
+	if u.cache != nil {
+		if payload, err := json.Marshal(results); err == nil {
+			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
+		}
+	}
+
+	return results, nil
+}
+
+// Resolve returns the best match for an address, or nil when nothing matches
+func (u *GeocodeUsecase) Resolve(address string) (*domain.GeocodeResult, error) {
+	results, err := u.Geocode(GeocodeOptions{Query: address, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return &results[0], nil
+}
+
+// Reverse returns the address nearest to a point, or nil when there is none
+func (u *GeocodeUsecase) Reverse(opts ReverseGeocodeOptions) (*domain.GeocodeResult, error) {
+	if opts.Latitude < -90 || opts.Latitude > 90 || opts.Longitude < -180 || opts.Longitude > 180 {
+		return nil, fmt.Errorf("coordinates out of range")
+	}
+
+	cacheKey := fmt.Sprintf("geocode:reverse:%.5f:%.5f", opts.Latitude, opts.Longitude)
+	if u.cache != nil {
+		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
+			var result *domain.GeocodeResult
+			if err := json.Unmarshal([]byte(cached), &result); err == nil {
+				return result, nil
+			}
+		}
+	}
+
+	ctx, cancel := u.context()
+	defer cancel()
+	result, err := u.geocoder.ReverseGeocode(ctx, opts.Latitude, opts.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil {
+		if payload, err := json.Marshal(result); err == nil {
+			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
+		}
+	}
+
+	return result, nil
+}
+
+func (u *GeocodeUsecase) context() (context.Context, context.CancelFunc) {
+	if u.timeout > 0 {
+		return context.WithTimeout(context.Background(), u.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type geocoderStub struct {
+	lastAddress string
+	lastLimit   int
+	results     []domain.GeocodeResult
+}
+
+func (s *geocoderStub) Geocode(ctx context.Context, address string, limit int) ([]domain.GeocodeResult, error) {
+	s.lastAddress = address
+	s.lastLimit = limit
+	return s.results, nil
+}
+
+func (s *geocoderStub) ReverseGeocode(ctx context.Context, lat, lon float64) (*domain.GeocodeResult, error) {
+	return nil, nil
+}
+
+func TestGeocodeCollapsesWhitespaceAndCapsLimit(t *testing.T) {
+	stub := &geocoderStub{}
+	uc := NewGeocodeUsecase(stub, nil, 0, 0)
+
+	results, err := uc.Geocode(GeocodeOptions{Query: "  東京都  渋谷区 ", Limit: 500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastAddress != "東京都 渋谷区" {
+		t.Fatalf("expected collapsed address, got %q", stub.lastAddress)
+	}
+	if stub.lastLimit != MaxGeocodeLimit {
+		t.Fatalf("expected limit %d, got %d", MaxGeocodeLimit, stub.lastLimit)
+	}
+	if results == nil {
+		t.Fatalf("expected empty slice, got nil")
+	}
+}
+
+func TestGeocodeResolveReturnsBestMatch(t *testing.T) {
+	stub := &geocoderStub{results: []domain.GeocodeResult{{Latitude: 35.66, Longitude: 139.70, PostalCode: "1500043"}}}
+	uc := NewGeocodeUsecase(stub, nil, 0, 0)
+
+	result, err := uc.Resolve("150-0043")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil || result.PostalCode != "1500043" {
+		t.Fatalf("expected best match, got %+v", result)
+	}
+	if stub.lastLimit != 1 {
+		t.Fatalf("expected resolve to request one result, got %d", stub.lastLimit)
+	}
+}
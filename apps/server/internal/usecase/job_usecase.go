@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type JobRepository interface {
+	Enqueue(job domain.Job) (*domain.Job, error)
+	FindAll(status, jobType string, limit, offset int) ([]domain.Job, error)
+	FindByID(id int64) (*domain.Job, error)
+	Retry(id int64) (*domain.Job, error)
+	CountByStatus() ([]domain.JobCount, error)
+}
+
+// JobRegistry is the worker pool's set of job handlers
+type JobRegistry interface {
+	Types() []string
+	Handles(jobType string) bool
+}
+
+// MaxJobAttempts bounds the attempts a job may ask for
+const MaxJobAttempts = 100
+
+type JobUsecase struct {
+	repo        JobRepository
+	registry    JobRegistry
+	maxAttempts int
+}
+
+// NewJobUsecase creates a JobUsecase whose jobs get maxAttempts attempts
+// unless they ask for a different number
+func NewJobUsecase(repo JobRepository, registry JobRegistry, maxAttempts int) *JobUsecase {
+	return &JobUsecase{repo: repo, registry: registry, maxAttempts: maxAttempts}
+}
+
+// Enqueue queues a job of a registered type. The payload must be a JSON
+// object; RunAt and MaxAttempts are optional. Validation failures wrap
+// domain.ErrInvalidJob.
+func (u *JobUsecase) Enqueue(job domain.Job) (*domain.Job, error) {
+	if !u.registry.Handles(job.Type) {
+		return nil, fmt.Errorf("%w: unknown job type %q", domain.ErrInvalidJob, job.Type)
+	}
+	if len(job.Payload) == 0 {
+		job.Payload = json.RawMessage("{}")
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload == nil {
+		return nil, fmt.Errorf("%w: payload must be a JSON object", domain.ErrInvalidJob)
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = u.maxAttempts
+	}
+	if job.MaxAttempts < 1 || job.MaxAttempts > MaxJobAttempts {
+		return nil, fmt.Errorf("%w: max_attempts must be between 1 and %d", domain.ErrInvalidJob, MaxJobAttempts)
+	}
+	return u.repo.Enqueue(job)
+}
+
+func (u *JobUsecase) List(opts JobListOptions) ([]domain.Job, error) {
+	switch opts.Status {
+	case "", domain.JobStatusQueued, domain.JobStatusRunning, domain.JobStatusSucceeded, domain.JobStatusFailed:
+	default:
+		return nil, fmt.Errorf("status must be %s, %s, %s or %s",
+			domain.JobStatusQueued, domain.JobStatusRunning, domain.JobStatusSucceeded, domain.JobStatusFailed)
+	}
+	return u.repo.FindAll(opts.Status, opts.Type, normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+func (u *JobUsecase) GetByID(id int64) (*domain.Job, error) {
+	return u.repo.FindByID(id)
+}
+
+// Retry requeues a failed job with a fresh set of attempts. It returns nil
+// when no job has id, and domain.ErrJobNotRetryable when the job has not
+// failed.
+func (u *JobUsecase) Retry(id int64) (*domain.Job, error) {
+	job, err := u.repo.FindByID(id)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusFailed {
+		return nil, domain.ErrJobNotRetryable
+	}
+	retried, err := u.repo.Retry(id)
+	if err != nil {
+		return nil, err
+	}
+	if retried == nil {
+		// Retried by someone else since we looked
+		return nil, domain.ErrJobNotRetryable
+	}
+	return retried, nil
+}
+
+// Summary counts the queue's jobs by type and status
+func (u *JobUsecase) Summary() (*domain.JobQueueSummary, error) {
+	counts, err := u.repo.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &domain.JobQueueSummary{Types: u.registry.Types(), Counts: counts}, nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type jobRepoStub struct {
+	job      *domain.Job
+	enqueued []domain.Job
+	retried  bool
+}
+
+func (s *jobRepoStub) Enqueue(job domain.Job) (*domain.Job, error) {
+	s.enqueued = append(s.enqueued, job)
+	job.ID = int64(len(s.enqueued))
+	job.Status = domain.JobStatusQueued
+	return &job, nil
+}
+
+func (s *jobRepoStub) FindAll(status, jobType string, limit, offset int) ([]domain.Job, error) {
+	return []domain.Job{}, nil
+}
+
+func (s *jobRepoStub) FindByID(id int64) (*domain.Job, error) {
+	return s.job, nil
+}
+
+func (s *jobRepoStub) Retry(id int64) (*domain.Job, error) {
+	s.retried = true
+	job := *s.job
+	job.Status = domain.JobStatusQueued
+	job.Attempts = 0
+	return &job, nil
+}
+
+func (s *jobRepoStub) CountByStatus() ([]domain.JobCount, error) {
+	return []domain.JobCount{}, nil
+}
+
+type jobRegistryStub []string
+
+func (s jobRegistryStub) Types() []string {
+	return s
+}
+
+func (s jobRegistryStub) Handles(jobType string) bool {
+	for _, t := range s {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestJobEnqueueValidates(t *testing.T) {
+	repo := &jobRepoStub{}
+	uc := NewJobUsecase(repo, jobRegistryStub{"connector.run"}, 5)
+
+	job, err := uc.Enqueue(domain.Job{Type: "connector.run", Payload: json.RawMessage(`{"connector": "local-feed"}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.MaxAttempts != 5 {
+		t.Fatalf("expected the default max attempts, got %d", job.MaxAttempts)
+	}
+
+	if _, err := uc.Enqueue(domain.Job{Type: "connector.run"}); err != nil {
+		t.Fatalf("expected an empty payload to be accepted, got %v", err)
+	}
+	if string(repo.enqueued[1].Payload) != "{}" {
+		t.Fatalf("expected an empty payload to become {}, got %s", repo.enqueued[1].Payload)
+	}
+
+	invalid := []domain.Job{
+		{Type: "rollup.daily"},
+		{Type: "connector.run", Payload: json.RawMessage(`[1, 2]`)},
+		{Type: "connector.run", Payload: json.RawMessage(`null`)},
+		{Type: "connector.run", MaxAttempts: -1},
+		{Type: "connector.run", MaxAttempts: MaxJobAttempts + 1},
+	}
+	for _, job := range invalid {
+		if _, err := uc.Enqueue(job); !errors.Is(err, domain.ErrInvalidJob) {
+			t.Fatalf("expected ErrInvalidJob for %+v, got %v", job, err)
+		}
+	}
+	if len(repo.enqueued) != 2 {
+		t.Fatalf("expected only valid jobs to be enqueued, got %d", len(repo.enqueued))
+	}
+}
+
+func TestJobRetryOnlyFailedJobs(t *testing.T) {
+	repo := &jobRepoStub{job: &domain.Job{ID: 7, Status: domain.JobStatusSucceeded}}
+	uc := NewJobUsecase(repo, jobRegistryStub{}, 5)
+
+	if _, err := uc.Retry(7); !errors.Is(err, domain.ErrJobNotRetryable) {
+		t.Fatalf("expected ErrJobNotRetryable, got %v", err)
+	}
+	if repo.retried {
+		t.Fatalf("expected a succeeded job not to be retried")
+	}
+
+	repo.job.Status = domain.JobStatusFailed
+	repo.job.Attempts = 5
+	job, err := uc.Retry(7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != domain.JobStatusQueued || job.Attempts != 0 {
+		t.Fatalf("expected the job to be requeued with fresh attempts, got %+v", job)
+	}
+
+	repo.job = nil
+	if job, err := uc.Retry(8); job != nil || err != nil {
+		t.Fatalf("expected nil for a missing job, got %+v, %v", job, err)
+	}
+}
+
+func TestJobListRejectsUnknownStatus(t *testing.T) {
+	uc := NewJobUsecase(&jobRepoStub{}, jobRegistryStub{}, 5)
+	if _, err := uc.List(JobListOptions{Status: "done"}); err == nil {
+		t.Fatalf("expected error for an unknown status")
+	}
+	if _, err := uc.List(JobListOptions{Status: domain.JobStatusFailed}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
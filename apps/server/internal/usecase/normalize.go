@@ -1,5 +1,14 @@
 package usecase
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
 func normalizeLimit(limit int) int {
 	if limit <= 0 {
 		return DefaultLimit
@@ -23,3 +32,86 @@ func normalizeOrder(order string) string {
 	}
 	return "ASC"
 }
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return "none"
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// normalizeStringSet drops empty and duplicate values and sorts the rest so
+// equivalent filters share a cache key
+func normalizeStringSet(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func normalizeIDSet(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	var out []int
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code; empty means no conversion
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// normalizeTaxMode defaults to tax-inclusive amounts
+func normalizeTaxMode(mode query.TaxMode) (query.TaxMode, error) {
+	switch mode {
+	case "", query.TaxIncluded:
+		return query.TaxIncluded, nil
+	case query.TaxExcluded:
+		return query.TaxExcluded, nil
+	default:
+		return "", fmt.Errorf("invalid tax mode %q", mode)
+	}
+}
+
+// normalizeMinConfidence checks a price confidence threshold; 0 counts
+// every price
+func normalizeMinConfidence(value float64) (float64, error) {
+	if value < 0 || value > 1 {
+		return 0, fmt.Errorf("min confidence must be between 0 and 1")
+	}
+	return value, nil
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/archive"
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type PartitionRepository interface {
+	EnsurePartitions(monthsAhead int) (int, error)
+	FindPartitions() ([]domain.PricePartition, error)
+	ArchivePartition(partition domain.PricePartition, file *archive.File) (*domain.PriceArchive, error)
+}
+
+// MaintainPricesJobType is the background job that creates upcoming price
+// partitions and archives the ones past retention
+const MaintainPricesJobType = "prices.maintain"
+
+// MaintainPricesJob is the payload of a MaintainPricesJobType job
+type MaintainPricesJob struct{}
+
+// RetentionPolicy sets how many months of price partitions exist ahead of
+// time and how many whole months are kept before archiving; 0 keeps
+// everything
+type RetentionPolicy struct {
+	MonthsAhead     int
+	RetentionMonths int
+}
+
+type PartitionUsecase struct {
+	repo     PartitionRepository
+	archives *archive.Dir
+	policy   RetentionPolicy
+}
+
+func NewPartitionUsecase(repo PartitionRepository, archives *archive.Dir, policy RetentionPolicy) *PartitionUsecase {
+	return &PartitionUsecase{repo: repo, archives: archives, policy: policy}
+}
+
+// Maintain creates missing partitions, then archives expired ones oldest
+// first
+func (u *PartitionUsecase) Maintain(ctx context.Context, _ MaintainPricesJob) error {
+	if _, err := u.repo.EnsurePartitions(u.policy.MonthsAhead); err != nil {
+		return err
+	}
+	if u.policy.RetentionMonths <= 0 {
+		return nil
+	}
+
+	partitions, err := u.repo.FindPartitions()
+	if err != nil {
+		return err
+	}
+	for _, partition := range expiredPartitions(partitions, RetentionCutoff(time.Now(), u.policy.RetentionMonths)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		file, err := u.archives.Create(archiveFileName(partition, time.Now()))
+		if err != nil {
+			return err
+		}
+		_, err = u.repo.ArchivePartition(partition, file)
+		file.Abort()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveFileName names the archive of partition made at archivedAt. A
+// month can be archived more than once: prices recorded late for an
+// archived month recreate its partition, which expires again, so the time
+// keeps the later archive from replacing the earlier one.
+func archiveFileName(partition domain.PricePartition, archivedAt time.Time) string {
+	return fmt.Sprintf("%s-%s.jsonl.gz", partition.Name, archivedAt.UTC().Format("20060102T150405Z"))
+}
+
+// RetentionCutoff is the start of the month retentionMonths before the
+// month of now; partitions ending by then are expired
+func RetentionCutoff(now time.Time, retentionMonths int) time.Time {
+	return time.Date(now.Year(), now.Month()-time.Month(retentionMonths), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func expiredPartitions(partitions []domain.PricePartition, cutoff time.Time) []domain.PricePartition {
+	var expired []domain.PricePartition
+	for _, partition := range partitions {
+		if !partition.RangeEnd.After(cutoff) {
+			expired = append(expired, partition)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/archive"
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type partitionRepoStub struct {
+	partitions  []domain.PricePartition
+	monthsAhead int
+	archived    []string
+	err         error
+}
+
+func (s *partitionRepoStub) EnsurePartitions(monthsAhead int) (int, error) {
+	s.monthsAhead = monthsAhead
+	return 0, nil
+}
+
+func (s *partitionRepoStub) FindPartitions() ([]domain.PricePartition, error) {
+	return s.partitions, nil
+}
+
+func (s *partitionRepoStub) ArchivePartition(partition domain.PricePartition, file *archive.File) (*domain.PriceArchive, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	io.WriteString(file, "{}\n")
+	if err := file.Commit(); err != nil {
+		return nil, err
+	}
+	s.archived = append(s.archived, partition.Name)
+	return &domain.PriceArchive{PartitionName: partition.Name, Path: file.Path()}, nil
+}
+
+func monthPartition(year int, month time.Month) domain.PricePartition {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return domain.PricePartition{
+		Name:       "prices_" + start.Format("y2006m01"),
+		RangeStart: start,
+		RangeEnd:   start.AddDate(0, 1, 0),
+	}
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	cutoff := RetentionCutoff(time.Date(2024, time.February, 17, 9, 30, 0, 0, time.UTC), 3)
+	if !cutoff.Equal(time.Date(2023, time.November, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 2023-11-01, got %s", cutoff)
+	}
+}
+
+func TestExpiredPartitionsEndByCutoff(t *testing.T) {
+	partitions := []domain.PricePartition{
+		monthPartition(2023, time.September),
+		monthPartition(2023, time.October),
+		monthPartition(2023, time.November),
+	}
+	expired := expiredPartitions(partitions, time.Date(2023, time.November, 1, 0, 0, 0, 0, time.UTC))
+	if len(expired) != 2 || expired[1].Name != "prices_y2023m10" {
+		t.Fatalf("expected September and October, got %+v", expired)
+	}
+}
+
+func TestArchiveFileNameIncludesArchiveTime(t *testing.T) {
+	partition := monthPartition(2024, time.January)
+	first := archiveFileName(partition, time.Date(2025, time.February, 1, 3, 0, 0, 0, time.UTC))
+	again := archiveFileName(partition, time.Date(2025, time.March, 1, 3, 0, 0, 0, time.FixedZone("JST", 9*3600)))
+
+	if first != "prices_y2024m01-20250201T030000Z.jsonl.gz" {
+		t.Fatalf("unexpected archive name %q", first)
+	}
+	if again != "prices_y2024m01-20250228T180000Z.jsonl.gz" {
+		t.Fatalf("expected a separate archive in UTC, got %q", again)
+	}
+}
+
+func TestMaintainArchivesOnlyWithRetention(t *testing.T) {
+	old := monthPartition(2000, time.January)
+	current := monthPartition(time.Now().Year(), time.Now().Month())
+
+	keep := &partitionRepoStub{partitions: []domain.PricePartition{old, current}}
+	uc := NewPartitionUsecase(keep, archive.NewDir(t.TempDir()), RetentionPolicy{MonthsAhead: 3})
+	if err := uc.Maintain(context.Background(), MaintainPricesJob{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keep.monthsAhead != 3 || len(keep.archived) != 0 {
+		t.Fatalf("expected partitions ensured and nothing archived, got %+v", keep)
+	}
+
+	expire := &partitionRepoStub{partitions: []domain.PricePartition{old, current}}
+	uc = NewPartitionUsecase(expire, archive.NewDir(t.TempDir()), RetentionPolicy{MonthsAhead: 3, RetentionMonths: 12})
+	if err := uc.Maintain(context.Background(), MaintainPricesJob{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expire.archived) != 1 || expire.archived[0] != old.Name {
+		t.Fatalf("expected only %s archived, got %v", old.Name, expire.archived)
+	}
+}
+
+func TestMaintainStopsOnArchiveError(t *testing.T) {
+	repo := &partitionRepoStub{partitions: []domain.PricePartition{monthPartition(2000, time.January)}, err: errors.New("lock timeout")}
+	uc := NewPartitionUsecase(repo, archive.NewDir(t.TempDir()), RetentionPolicy{RetentionMonths: 1})
+	if err := uc.Maintain(context.Background(), MaintainPricesJob{}); err == nil {
+		t.Fatalf("expected the archive error")
+	}
+}
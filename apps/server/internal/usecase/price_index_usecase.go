@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+type PriceIndexRepository interface {
+	FindPeriodPrices(category string, areaID int, period string, from, to time.Time, tax query.TaxMode) ([]domain.PeriodPrice, error)
+}
+
+// PriceIndexUsecase computes chained price indexes for a category.
+// Each period is linked to the previous priced period by a Laspeyres
+// ratio over the products priced at the same store in both, weighted by
+// how often each was priced in the earlier one: a basket refreshed every
+// period, so products and stores entering and leaving the category don't
+// move the index.
+type PriceIndexUsecase struct {
+	repo PriceIndexRepository
+}
+
+func NewPriceIndexUsecase(repo PriceIndexRepository) *PriceIndexUsecase {
+	return &PriceIndexUsecase{repo: repo}
+}
+
+// PriceIndex computes the index over opts.From through opts.To, scaled to
+// 100 in the period containing opts.Base (default: the first priced
+// period). Validation failures wrap domain.ErrInvalidPriceIndex, and
+// prices in more than one currency return domain.ErrMixedCurrencies, since
+// a link cannot weigh them against each other.
+func (u *PriceIndexUsecase) PriceIndex(opts PriceIndexOptions) (*domain.PriceIndex, error) {
+	opts, err := normalizePriceIndexOptions(opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := u.repo.FindPeriodPrices(opts.Category, opts.AreaID, opts.Period, opts.From, opts.To, opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	if currencies := priceCurrencies(prices); len(currencies) > 1 {
+		return nil, fmt.Errorf("%w (%s)", domain.ErrMixedCurrencies, strings.Join(currencies, ", "))
+	}
+
+	periods := indexPeriods(opts.Period, opts.From, opts.To)
+	points := chainIndex(periods, prices)
+	index := &domain.PriceIndex{
+		Category:    opts.Category,
+		AreaID:      opts.AreaID,
+		Period:      opts.Period,
+		From:        opts.From,
+		To:          opts.To,
+		TaxIncluded: opts.Tax.Included(),
+		Points:      points,
+	}
+
+	base := -1
+	if opts.Base.IsZero() {
+		for i, point := range points {
+			if point.Index != nil {
+				base = i
+				break
+			}
+		}
+		if base < 0 {
+			return index, nil
+		}
+	} else {
+		start := periodStart(opts.Period, opts.Base)
+		for i, point := range points {
+			if point.PeriodStart.Equal(start) {
+				base = i
+			}
+		}
+		if base < 0 || points[base].Index == nil {
+			return nil, fmt.Errorf("%w: no prices in the base period starting %s", domain.ErrInvalidPriceIndex, start.Format("2006-01-02"))
+		}
+	}
+
+	scale := 100 / *points[base].Index
+	for i := range points {
+		if points[i].Index != nil {
+			scaled := *points[i].Index * scale
+			points[i].Index = &scaled
+		}
+	}
+	basePeriod := points[base].PeriodStart
+	index.BasePeriod = &basePeriod
+	return index, nil
+}
+
+// DefaultPriceIndexDays is the range of a price index without a from date
+const DefaultPriceIndexDays = 365
+
+func normalizePriceIndexOptions(opts PriceIndexOptions, now time.Time) (PriceIndexOptions, error) {
+	opts.Category = strings.TrimSpace(opts.Category)
+	if opts.Category == "" {
+		return opts, fmt.Errorf("%w: category is required", domain.ErrInvalidPriceIndex)
+	}
+	switch opts.Period {
+	case "":
+		opts.Period = domain.PriceIndexPeriodMonth
+	case domain.PriceIndexPeriodMonth, domain.PriceIndexPeriodWeek:
+	default:
+		return opts, fmt.Errorf("%w: period must be %s or %s", domain.ErrInvalidPriceIndex, domain.PriceIndexPeriodMonth, domain.PriceIndexPeriodWeek)
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return opts, fmt.Errorf("%w: %v", domain.ErrInvalidPriceIndex, err)
+	}
+	opts.Tax = tax
+
+	if opts.To.IsZero() {
+		opts.To = now
+	}
+	opts.To = truncateDate(opts.To)
+	if opts.From.IsZero() {
+		opts.From = opts.To.AddDate(0, 0, -DefaultPriceIndexDays)
+	}
+	opts.From = truncateDate(opts.From)
+	if opts.From.After(opts.To) {
+		return opts, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidPriceIndex)
+	}
+	if opts.To.Sub(opts.From) > MaxStatsDays*24*time.Hour {
+		return opts, fmt.Errorf("%w: range must be at most %d days", domain.ErrInvalidPriceIndex, MaxStatsDays)
+	}
+	if !opts.Base.IsZero() {
+		opts.Base = truncateDate(opts.Base)
+		if opts.Base.Before(periodStart(opts.Period, opts.From)) || opts.Base.After(opts.To) {
+			return opts, fmt.Errorf("%w: base must be within the range", domain.ErrInvalidPriceIndex)
+		}
+	}
+	return opts, nil
+}
+
+// periodStart is the first day of the month, or the Monday of the week,
+// containing day
+func periodStart(period string, day time.Time) time.Time {
+	day = truncateDate(day)
+	if period == domain.PriceIndexPeriodWeek {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// indexPeriods lists the starts of the periods overlapping from through to
+func indexPeriods(period string, from, to time.Time) []time.Time {
+	var periods []time.Time
+	for start := periodStart(period, from); !start.After(to); {
+		periods = append(periods, start)
+		if period == domain.PriceIndexPeriodWeek {
+			start = start.AddDate(0, 0, 7)
+		} else {
+			start = start.AddDate(0, 1, 0)
+		}
+	}
+	return periods
+}
+
+// priceCurrencies lists the distinct currencies of prices, sorted
+func priceCurrencies(prices []domain.PeriodPrice) []string {
+	seen := make(map[string]bool)
+	var currencies []string
+	for _, price := range prices {
+		if !seen[price.Currency] {
+			seen[price.Currency] = true
+			currencies = append(currencies, price.Currency)
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// indexItem is one product at one store, so a link compares a product's
+// price with its own earlier price at the same store
+type indexItem struct {
+	storeID   int
+	productID int
+	currency  string
+}
+
+type indexQuote struct {
+	price float64
+	count int
+}
+
+// chainIndex links the periods into an unscaled index that is 1 in the
+// first priced period. A period sharing no products with the previous
+// priced period keeps its index.
+func chainIndex(periods []time.Time, prices []domain.PeriodPrice) []domain.PriceIndexPoint {
+	byPeriod := make(map[time.Time]map[indexItem]indexQuote)
+	for _, price := range prices {
+		start := truncateDate(price.PeriodStart)
+		if byPeriod[start] == nil {
+			byPeriod[start] = make(map[indexItem]indexQuote)
+		}
+		byPeriod[start][indexItem{price.StoreID, price.ProductID, price.Currency}] = indexQuote{price.AvgPrice, price.Count}
+	}
+
+	points := make([]domain.PriceIndexPoint, len(periods))
+	var previous map[indexItem]indexQuote
+	var level float64
+	for i, start := range periods {
+		points[i].PeriodStart = start
+		current := byPeriod[start]
+		if len(current) == 0 {
+			continue
+		}
+		for _, quote := range current {
+			points[i].Observations += quote.count
+		}
+
+		if previous == nil {
+			level = 1
+		} else {
+			var now, before float64
+			matched := make(map[int]bool)
+			for item, earlier := range previous {
+				if later, ok := current[item]; ok && earlier.price > 0 {
+					now += float64(earlier.count) * later.price
+					before += float64(earlier.count) * earlier.price
+					matched[item.productID] = true
+				}
+			}
+			points[i].Products = len(matched)
+			if before > 0 {
+				change := (now/before - 1) * 100
+				points[i].Change = &change
+				level *= now / before
+			}
+		}
+		value := level
+		points[i].Index = &value
+		previous = current
+	}
+	return points
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+type priceIndexRepoStub struct {
+	prices []domain.PeriodPrice
+	period string
+	from   time.Time
+	to     time.Time
+}
+
+func (s *priceIndexRepoStub) FindPeriodPrices(category string, areaID int, period string, from, to time.Time, tax query.TaxMode) ([]domain.PeriodPrice, error) {
+	s.period, s.from, s.to = period, from, to
+	return s.prices, nil
+}
+
+func month(m time.Month) time.Time {
+	return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func assertIndex(t *testing.T, point domain.PriceIndexPoint, want float64) {
+	t.Helper()
+	if point.Index == nil || math.Abs(*point.Index-want) > 1e-9 {
+		t.Fatalf("expected index %v for %s, got %+v", want, point.PeriodStart.Format("2006-01"), point.Index)
+	}
+}
+
+func TestPriceIndexChainsMatchedProducts(t *testing.T) {
+	repo := &priceIndexRepoStub{prices: []domain.PeriodPrice{
+		{PeriodStart: month(time.January), ProductID: 1, Currency: "JPY", AvgPrice: 100, Count: 3},
+		{PeriodStart: month(time.January), ProductID: 2, Currency: "JPY", AvgPrice: 200, Count: 1},
+		// Product 2 doubles; product 3 appears and must not move the index
+		{PeriodStart: month(time.February), ProductID: 1, Currency: "JPY", AvgPrice: 100, Count: 2},
+		{PeriodStart: month(time.February), ProductID: 2, Currency: "JPY", AvgPrice: 400, Count: 1},
+		{PeriodStart: month(time.February), ProductID: 3, Currency: "JPY", AvgPrice: 9999, Count: 5},
+		// No prices in March; April links to February
+		{PeriodStart: month(time.April), ProductID: 1, Currency: "JPY", AvgPrice: 110, Count: 1},
+		{PeriodStart: month(time.April), ProductID: 3, Currency: "JPY", AvgPrice: 9999, Count: 1},
+	}}
+	uc := NewPriceIndexUsecase(repo)
+
+	index, err := uc.PriceIndex(PriceIndexOptions{
+		Category: "乳製品",
+		From:     time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, time.April, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.period != domain.PriceIndexPeriodMonth || len(index.Points) != 4 {
+		t.Fatalf("expected four monthly points, got %+v", index.Points)
+	}
+	if index.BasePeriod == nil || !index.BasePeriod.Equal(month(time.January)) {
+		t.Fatalf("expected January as the base, got %v", index.BasePeriod)
+	}
+
+	assertIndex(t, index.Points[0], 100)
+	// (3*100 + 1*400) / (3*100 + 1*200)
+	assertIndex(t, index.Points[1], 140)
+	if index.Points[1].Products != 2 || index.Points[1].Observations != 8 {
+		t.Fatalf("expected two matched products and eight prices, got %+v", index.Points[1])
+	}
+	if index.Points[2].Index != nil {
+		t.Fatalf("expected no index without prices, got %v", *index.Points[2].Index)
+	}
+	// (2*110 + 5*9999) / (2*100 + 5*9999) applied to 140
+	assertIndex(t, index.Points[3], 140*(2*110+5*9999.0)/(2*100+5*9999.0))
+}
+
+func TestPriceIndexLinksTheSameStore(t *testing.T) {
+	repo := &priceIndexRepoStub{prices: []domain.PeriodPrice{
+		{PeriodStart: month(time.January), StoreID: 1, ProductID: 1, Currency: "JPY", AvgPrice: 200, Count: 1},
+		// A cheaper store starts selling product 1; store 1 keeps its price
+		{PeriodStart: month(time.February), StoreID: 1, ProductID: 1, Currency: "JPY", AvgPrice: 200, Count: 1},
+		{PeriodStart: month(time.February), StoreID: 2, ProductID: 1, Currency: "JPY", AvgPrice: 100, Count: 4},
+	}}
+	uc := NewPriceIndexUsecase(repo)
+
+	index, err := uc.PriceIndex(PriceIndexOptions{
+		Category: "乳製品",
+		From:     month(time.January),
+		To:       time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertIndex(t, index.Points[1], 100)
+	if index.Points[1].Products != 1 {
+		t.Fatalf("expected one matched product, got %+v", index.Points[1])
+	}
+}
+
+func TestPriceIndexRejectsMixedCurrencies(t *testing.T) {
+	repo := &priceIndexRepoStub{prices: []domain.PeriodPrice{
+		{PeriodStart: month(time.January), StoreID: 1, ProductID: 1, Currency: "JPY", AvgPrice: 200, Count: 1},
+		{PeriodStart: month(time.January), StoreID: 2, ProductID: 1, Currency: "USD", AvgPrice: 1.5, Count: 1},
+	}}
+	uc := NewPriceIndexUsecase(repo)
+
+	_, err := uc.PriceIndex(PriceIndexOptions{Category: "乳製品", From: month(time.January), To: month(time.February)})
+	if !errors.Is(err, domain.ErrMixedCurrencies) {
+		t.Fatalf("expected ErrMixedCurrencies, got %v", err)
+	}
+}
+
+func TestPriceIndexRebasesOnBasePeriod(t *testing.T) {
+	repo := &priceIndexRepoStub{prices: []domain.PeriodPrice{
+		{PeriodStart: month(time.January), ProductID: 1, Currency: "JPY", AvgPrice: 100, Count: 1},
+		{PeriodStart: month(time.February), ProductID: 1, Currency: "JPY", AvgPrice: 125, Count: 1},
+	}}
+	uc := NewPriceIndexUsecase(repo)
+
+	index, err := uc.PriceIndex(PriceIndexOptions{
+		Category: "乳製品",
+		From:     month(time.January),
+		To:       time.Date(2024, time.February, 20, 0, 0, 0, 0, time.UTC),
+		Base:     time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertIndex(t, index.Points[0], 80)
+	assertIndex(t, index.Points[1], 100)
+	if change := index.Points[1].Change; change == nil || math.Abs(*change-25) > 1e-9 {
+		t.Fatalf("expected a 25%% change, got %v", change)
+	}
+}
+
+func TestPriceIndexWeeksStartOnMonday(t *testing.T) {
+	periods := indexPeriods(domain.PriceIndexPeriodWeek,
+		time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC))
+	if len(periods) != 3 || !periods[0].Equal(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected weeks from Monday March 4, got %v", periods)
+	}
+}
+
+func TestPriceIndexValidates(t *testing.T) {
+	uc := NewPriceIndexUsecase(&priceIndexRepoStub{})
+	invalid := []PriceIndexOptions{
+		{},
+		{Category: "乳製品", Period: "day"},
+		{Category: "乳製品", From: month(time.March), To: month(time.January)},
+		{Category: "乳製品", From: month(time.January).AddDate(-6, 0, 0), To: month(time.January)},
+		{Category: "乳製品", From: month(time.January), To: month(time.March), Base: month(time.May)},
+		// Nothing priced in the base period
+		{Category: "乳製品", From: month(time.January), To: month(time.March), Base: month(time.February)},
+	}
+	for _, opts := range invalid {
+		if _, err := uc.PriceIndex(opts); !errors.Is(err, domain.ErrInvalidPriceIndex) {
+			t.Fatalf("expected ErrInvalidPriceIndex for %+v, got %v", opts, err)
+		}
+	}
+}
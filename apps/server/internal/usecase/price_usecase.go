@@ -4,12 +4,17 @@ import (
 	"fmt"
 
 	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
 )
 
+// MaxPurchaseQuantity bounds the basket size prices are compared for
+const MaxPurchaseQuantity = 99
+
 type PriceRepository interface {
-	FindByProductID(productID int, limit, offset int, sortField, sortOrder string) ([]domain.Price, error)
-	FindByStoreID(storeID int, category string, limit, offset int, sortField, sortOrder string) ([]domain.Price, error)
-	FindStorePriceStats(storeID int, category string, query string, days int) (domain.StorePriceStats, error)
+	FindByProductID(productID int, currency string, tax query.TaxMode, minConfidence float64, purchase query.Purchase, limit, offset int, sortField, sortOrder string) ([]domain.Price, error)
+	FindByStoreID(storeID int, category string, currency string, tax query.TaxMode, purchase query.Purchase, limit, offset int, sortField, sortOrder string) ([]domain.Price, error)
+	FindStorePriceStats(storeID int, category string, query string, currency string, tax query.TaxMode, days int) (domain.StorePriceStats, error)
+	FindChainPriceStats(chainID int, category string, query string, currency string, tax query.TaxMode, days int) (domain.ChainPriceStats, error)
 }
 
 type PriceUsecase struct {
@@ -24,34 +29,102 @@ func (u *PriceUsecase) ListByProduct(opts PriceListOptions) ([]domain.Price, err
 	if opts.ProductID <= 0 {
 		return nil, fmt.Errorf("product id must be positive")
 	}
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
 	limit := normalizeLimit(opts.Limit)
 	offset := normalizeOffset(opts.Offset)
 	sortField, sortOrder := normalizePriceSort(opts.Sort)
-	return u.repo.FindByProductID(opts.ProductID, limit, offset, sortField, sortOrder)
+	purchase, err := normalizePurchase(opts.Quantity, opts.Member)
+	if err != nil {
+		return nil, err
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	minConfidence, err := normalizeMinConfidence(opts.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByProductID(opts.ProductID, currency, tax, minConfidence, purchase, limit, offset, sortField, sortOrder)
 }
 
 func (u *PriceUsecase) ListByStore(opts StorePriceListOptions) ([]domain.Price, error) {
 	if opts.StoreID <= 0 {
 		return nil, fmt.Errorf("store id must be positive")
 	}
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
 	limit := normalizeLimit(opts.Limit)
 	offset := normalizeOffset(opts.Offset)
 	sortField, sortOrder := normalizePriceSort(opts.Sort)
-	return u.repo.FindByStoreID(opts.StoreID, opts.Category, limit, offset, sortField, sortOrder)
+	purchase, err := normalizePurchase(opts.Quantity, opts.Member)
+	if err != nil {
+		return nil, err
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByStoreID(opts.StoreID, opts.Category, currency, tax, purchase, limit, offset, sortField, sortOrder)
 }
 
 func (u *PriceUsecase) GetStorePriceStats(opts StorePriceStatsOptions) (domain.StorePriceStats, error) {
 	if opts.StoreID <= 0 {
 		return domain.StorePriceStats{}, fmt.Errorf("store id must be positive")
 	}
-	days := opts.Days
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return domain.StorePriceStats{}, err
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return domain.StorePriceStats{}, err
+	}
+	return u.repo.FindStorePriceStats(opts.StoreID, opts.Category, opts.Query, currency, tax, normalizeStatsDays(opts.Days))
+}
+
+func (u *PriceUsecase) GetChainPriceStats(opts ChainPriceStatsOptions) (domain.ChainPriceStats, error) {
+	if opts.ChainID <= 0 {
+		return domain.ChainPriceStats{}, fmt.Errorf("chain id must be positive")
+	}
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return domain.ChainPriceStats{}, err
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return domain.ChainPriceStats{}, err
+	}
+	return u.repo.FindChainPriceStats(opts.ChainID, opts.Category, opts.Query, currency, tax, normalizeStatsDays(opts.Days))
+}
+
+// MaxStatsDays is the longest price statistics range, about five years
+const MaxStatsDays = 1830
+
+func normalizeStatsDays(days int) int {
 	if days <= 0 {
-		days = 14
+		return 14
+	}
+	if days > MaxStatsDays {
+		return MaxStatsDays
+	}
+	return days
+}
+
+// normalizePurchase defaults the quantity to a single unit
+func normalizePurchase(quantity int, member bool) (query.Purchase, error) {
+	if quantity == 0 {
+		quantity = 1
 	}
-	if days > 60 {
-		days = 60
+	if quantity < 1 || quantity > MaxPurchaseQuantity {
+		return query.Purchase{}, fmt.Errorf("quantity must be between 1 and %d", MaxPurchaseQuantity)
 	}
-	return u.repo.FindStorePriceStats(opts.StoreID, opts.Category, opts.Query, days)
+	return query.Purchase{Quantity: quantity, Member: member}, nil
 }
 
 func normalizePriceSort(sort Sort) (string, string) {
@@ -61,6 +134,8 @@ func normalizePriceSort(sort Sort) (string, string) {
 	switch field {
 	case "recorded_at":
 		return "recorded_at", order
+	case "unit_price":
+		return "unit_price", order
 	case "price":
 		fallthrough
 	default:
@@ -4,16 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
 )
 
+const searchSuggestionLimit = 3
+
 type ProductRepository interface {
 	FindAll(limit, offset int, sortField, sortOrder string) ([]domain.Product, error)
 	FindByID(id int) (*domain.Product, error)
-	Search(keyword string, limit, offset int, sortField, sortOrder string) ([]domain.Product, error)
-	ListCategories() ([]string, error)
+	Search(filters query.ProductSearchFilters, limit, offset int, sortField, sortOrder string) ([]domain.ProductSearchResult, error)
+	SuggestNames(keyword string, limit int) ([]string, error)
+	ListCategories() ([]domain.Category, error)
 }
 
 type ProductUsecase struct {
@@ -62,45 +67,79 @@ func (u *ProductUsecase) GetByID(id int) (*domain.Product, error) {
 	return u.repo.FindByID(id)
 }
 
-func (u *ProductUsecase) Search(opts ProductSearchOptions) ([]domain.Product, error) {
-	if opts.Keyword == "" {
-		return nil, fmt.Errorf("keyword is required")
+func (u *ProductUsecase) Search(opts ProductSearchOptions) (domain.ProductSearchPage, error) {
+	keyword := normalizeSearchText(opts.Keyword)
+	if keyword == "" {
+		return domain.ProductSearchPage{}, fmt.Errorf("%w: keyword is required", domain.ErrInvalidSearch)
+	}
+	if opts.MinPrice != nil && opts.MaxPrice != nil && *opts.MinPrice > *opts.MaxPrice {
+		return domain.ProductSearchPage{}, fmt.Errorf("%w: min price must not exceed max price", domain.ErrInvalidSearch)
 	}
 	limit := normalizeLimit(opts.Limit)
 	offset := normalizeOffset(opts.Offset)
-	sortField, sortOrder := normalizeProductSort(opts.Sort)
+	sortField, sortOrder := normalizeProductSearchSort(opts.Sort)
+
+	filters := query.ProductSearchFilters{
+		Keyword:  keyword,
+		Category: opts.Category,
+		MinPrice: opts.MinPrice,
+		MaxPrice: opts.MaxPrice,
+	}
 
-	cacheKey := fmt.Sprintf("products:search:%s:%d:%d:%s:%s", opts.Keyword, limit, offset, sortField, sortOrder)
+	cacheKey := fmt.Sprintf("products:search:%s:%s:%s:%s:%d:%d:%s:%s",
+		filters.Keyword,
+		filters.Category,
+		formatOptionalFloat(filters.MinPrice),
+		formatOptionalFloat(filters.MaxPrice),
+		limit,
+		offset,
+		sortField,
+		sortOrder,
+	)
 	if u.cache != nil {
 		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
-			var products []domain.Product
-			if err := json.Unmarshal([]byte(cached), &products); err == nil {
-				return products, nil
+			var page domain.ProductSearchPage
+			if err := json.Unmarshal([]byte(cached), &page); err == nil {
+				return page, nil
 			}
 		}
 	}
 
-	products, err := u.repo.Search(opts.Keyword, limit, offset, sortField, sortOrder)
+	results, err := u.repo.Search(filters, limit, offset, sortField, sortOrder)
 	if err != nil {
-		return nil, err
+		return domain.ProductSearchPage{}, err
+	}
+	for i := range results {
+		results[i].Highlights = highlightSpans(results[i].Name, keyword)
+	}
+
+	page := domain.ProductSearchPage{Results: results}
+	if len(results) == 0 && offset == 0 {
+		suggestions, err := u.repo.SuggestNames(keyword, searchSuggestionLimit)
+		if err != nil {
+			return domain.ProductSearchPage{}, err
+		}
+		page.Suggestions = suggestions
 	}
 
 	if u.cache != nil {
-		if payload, err := json.Marshal(products); err == nil {
+		if payload, err := json.Marshal(page); err == nil {
 			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
 		}
 	}
 
-	return products, nil
+	return page, nil
 }
 
-func (u *ProductUsecase) ListCategories() ([]string, error) {
+// ListCategories returns the category tree, named in lang where a category
+// has a translation for it and in Japanese otherwise
+func (u *ProductUsecase) ListCategories(lang string) ([]domain.Category, error) {
 	cacheKey := "products:categories"
 	if u.cache != nil {
 		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
-			var categories []string
+			var categories []domain.Category
 			if err := json.Unmarshal([]byte(cached), &categories); err == nil {
-				return categories, nil
+				return categoryTree(categories, lang), nil
 			}
 		}
 	}
@@ -116,7 +155,60 @@ func (u *ProductUsecase) ListCategories() ([]string, error) {
 		}
 	}
 
-	return categories, nil
+	return categoryTree(categories, lang), nil
+}
+
+// categoryTree nests categories under their parents, keeping their order.
+// Categories whose parent is missing become roots, as does the first
+// category of any parent cycle.
+func categoryTree(categories []domain.Category, lang string) []domain.Category {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	known := make(map[int]bool, len(categories))
+	children := make(map[int][]domain.Category)
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	var nodes, roots []domain.Category
+	for _, category := range categories {
+		if name := category.Names[lang]; name != "" {
+			category.Name = name
+		}
+		category.Children = nil
+		nodes = append(nodes, category)
+		if category.ParentID != nil && known[*category.ParentID] {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+			continue
+		}
+		roots = append(roots, category)
+	}
+
+	placed := make(map[int]bool, len(categories))
+	var attach func(nodes []domain.Category) []domain.Category
+	attach = func(nodes []domain.Category) []domain.Category {
+		var attached []domain.Category
+		for _, node := range nodes {
+			if placed[node.ID] {
+				continue
+			}
+			placed[node.ID] = true
+			node.Children = attach(children[node.ID])
+			attached = append(attached, node)
+		}
+		return attached
+	}
+
+	tree := attach(roots)
+	// A cycle has no root to reach it from
+	for _, node := range nodes {
+		if !placed[node.ID] {
+			tree = append(tree, attach([]domain.Category{node})...)
+		}
+	}
+	if tree == nil {
+		tree = []domain.Category{}
+	}
+	return tree
 }
 
 func normalizeProductSort(sort Sort) (string, string) {
@@ -132,3 +224,16 @@ func normalizeProductSort(sort Sort) (string, string) {
 		return "name", order
 	}
 }
+
+// normalizeProductSearchSort defaults to relevance, best matches first
+func normalizeProductSearchSort(sort Sort) (string, string) {
+	switch sort.Field {
+	case "name", "created_at":
+		return sort.Field, normalizeOrder(sort.Order)
+	default:
+		if sort.Order == "asc" || sort.Order == "ASC" {
+			return "relevance", "ASC"
+		}
+		return "relevance", "DESC"
+	}
+}
@@ -1,12 +1,17 @@
 package usecase
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
 )
 
 type productRepoStub struct {
+	searchResults []domain.ProductSearchResult
+	categories    []domain.Category
+	lastKeyword   string
 	lastLimit     int
 	lastOffset    int
 	lastSortField string
@@ -25,24 +30,31 @@ func (p *productRepoStub) FindByID(id int) (*domain.Product, error) {
 	return nil, nil
 }
 
-func (p *productRepoStub) Search(keyword string, limit, offset int, sortField, sortOrder string) ([]domain.Product, error) {
+func (p *productRepoStub) Search(filters query.ProductSearchFilters, limit, offset int, sortField, sortOrder string) ([]domain.ProductSearchResult, error) {
+	p.lastKeyword = filters.Keyword
 	p.lastLimit = limit
 	p.lastOffset = offset
 	p.lastSortField = sortField
 	p.lastSortOrder = sortOrder
-	return []domain.Product{}, nil
+	return p.searchResults, nil
+}
+
+func (p *productRepoStub) SuggestNames(keyword string, limit int) ([]string, error) {
+	return []string{"牛乳 1L"}, nil
 }
 
-func (p *productRepoStub) ListCategories() ([]string, error) {
-	return []string{}, nil
+func (p *productRepoStub) ListCategories() ([]domain.Category, error) {
+	return p.categories, nil
 }
 
 func TestProductSearchRequiresKeyword(t *testing.T) {
 	stub := &productRepoStub{}
 	uc := NewProductUsecase(stub, nil, 0)
 
-	if _, err := uc.Search(ProductSearchOptions{}); err == nil {
-		t.Fatalf("expected error for empty keyword")
+	for _, keyword := range []string{"", "  ", "　"} {
+		if _, err := uc.Search(ProductSearchOptions{Keyword: keyword}); !errors.Is(err, domain.ErrInvalidSearch) {
+			t.Fatalf("expected ErrInvalidSearch for keyword %q, got %v", keyword, err)
+		}
 	}
 }
 
@@ -67,3 +79,88 @@ func TestProductListDefaults(t *testing.T) {
 		t.Fatalf("expected sort order ASC, got %s", stub.lastSortOrder)
 	}
 }
+
+func TestProductSearchNormalizesKeywordAndDefaultsToRelevance(t *testing.T) {
+	stub := &productRepoStub{
+		searchResults: []domain.ProductSearchResult{{Product: domain.Product{ID: 1, Name: "コカ・コーラ 500ml"}}},
+	}
+	uc := NewProductUsecase(stub, nil, 0)
+
+	page, err := uc.Search(ProductSearchOptions{Keyword: " ｺｶ "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stub.lastKeyword != "こか" {
+		t.Fatalf("expected normalized keyword こか, got %q", stub.lastKeyword)
+	}
+	if stub.lastSortField != "relevance" || stub.lastSortOrder != "DESC" {
+		t.Fatalf("expected relevance DESC, got %s %s", stub.lastSortField, stub.lastSortOrder)
+	}
+	if len(page.Results[0].Highlights) != 1 || page.Results[0].Highlights[0] != (domain.TextSpan{Start: 0, End: 2}) {
+		t.Fatalf("unexpected highlights: %+v", page.Results[0].Highlights)
+	}
+	if len(page.Suggestions) != 0 {
+		t.Fatalf("expected no suggestions when results exist")
+	}
+}
+
+func TestProductSearchSuggestsWhenEmpty(t *testing.T) {
+	stub := &productRepoStub{}
+	uc := NewProductUsecase(stub, nil, 0)
+
+	page, err := uc.Search(ProductSearchOptions{Keyword: "ぎゅうにゅ"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Suggestions) != 1 {
+		t.Fatalf("expected suggestions, got %v", page.Suggestions)
+	}
+}
+
+func TestProductListCategoriesNestsTree(t *testing.T) {
+	food, dairy := 1, 2
+	stub := &productRepoStub{categories: []domain.Category{
+		{ID: 3, ParentID: &dairy, Slug: "yogurt", Name: "ヨーグルト"},
+		{ID: 1, Slug: "food", Name: "食品", Names: map[string]string{"en": "Food"}},
+		{ID: 4, Slug: "beverages", Name: "飲料", Names: map[string]string{"en": "Beverages"}},
+		{ID: 2, ParentID: &food, Slug: "dairy", Name: "乳製品", Names: map[string]string{"en": "Dairy"}},
+	}}
+	uc := NewProductUsecase(stub, nil, 0)
+
+	tree, err := uc.ListCategories("EN")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Slug != "food" || tree[1].Name != "Beverages" {
+		t.Fatalf("expected food and beverages at the root in English, got %+v", tree)
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].Name != "Dairy" {
+		t.Fatalf("expected dairy under food, got %+v", tree[0].Children)
+	}
+	yogurt := tree[0].Children[0].Children
+	if len(yogurt) != 1 || yogurt[0].Name != "ヨーグルト" {
+		t.Fatalf("expected untranslated yogurt under dairy, got %+v", yogurt)
+	}
+
+	tree, _ = uc.ListCategories("")
+	if tree[0].Name != "食品" {
+		t.Fatalf("expected Japanese names by default, got %q", tree[0].Name)
+	}
+}
+
+func TestCategoryTreeKeepsCycles(t *testing.T) {
+	snacks, sweets := 2, 3
+	tree := categoryTree([]domain.Category{
+		{ID: 1, Slug: "food", Name: "食品"},
+		{ID: 2, ParentID: &sweets, Slug: "snacks", Name: "スナック"},
+		{ID: 3, ParentID: &snacks, Slug: "sweets", Name: "お菓子"},
+	}, "")
+
+	if len(tree) != 2 || tree[1].Slug != "snacks" {
+		t.Fatalf("expected the cycle to start a root after food, got %+v", tree)
+	}
+	if len(tree[1].Children) != 1 || tree[1].Children[0].Slug != "sweets" || tree[1].Children[0].Children != nil {
+		t.Fatalf("expected sweets once under snacks, got %+v", tree[1].Children)
+	}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+type PromotionRepository interface {
+	Create(promotion domain.Promotion) (*domain.Promotion, error)
+	FindAll(storeID, productID int, activeOnly bool, tax query.TaxMode, limit, offset int) ([]domain.Promotion, error)
+	FindByID(id int, tax query.TaxMode) (*domain.Promotion, error)
+}
+
+type PromotionUsecase struct {
+	repo PromotionRepository
+}
+
+func NewPromotionUsecase(repo PromotionRepository) *PromotionUsecase {
+	return &PromotionUsecase{repo: repo}
+}
+
+// Create validates and saves a promotion. It needs a sale price, a
+// multi-buy bundle, or both; the currency defaults to JPY like prices.
+// Validation failures wrap domain.ErrInvalidPromotion, and a nil promotion
+// means the store or product does not exist.
+func (u *PromotionUsecase) Create(promotion domain.Promotion) (*domain.Promotion, error) {
+	if promotion.StoreID <= 0 || promotion.ProductID <= 0 {
+		return nil, fmt.Errorf("%w: store id and product id must be positive", domain.ErrInvalidPromotion)
+	}
+	if promotion.SalePrice == nil && promotion.BundleQuantity == nil {
+		return nil, fmt.Errorf("%w: sale price or bundle is required", domain.ErrInvalidPromotion)
+	}
+	if promotion.SalePrice != nil && *promotion.SalePrice < 0 {
+		return nil, fmt.Errorf("%w: sale price must not be negative", domain.ErrInvalidPromotion)
+	}
+	if (promotion.BundleQuantity == nil) != (promotion.BundlePrice == nil) {
+		return nil, fmt.Errorf("%w: bundle quantity and bundle price must be set together", domain.ErrInvalidPromotion)
+	}
+	if promotion.BundleQuantity != nil {
+		if *promotion.BundleQuantity < 2 || *promotion.BundleQuantity > MaxPurchaseQuantity {
+			return nil, fmt.Errorf("%w: bundle quantity must be between 2 and %d", domain.ErrInvalidPromotion, MaxPurchaseQuantity)
+		}
+		if *promotion.BundlePrice < 0 {
+			return nil, fmt.Errorf("%w: bundle price must not be negative", domain.ErrInvalidPromotion)
+		}
+	}
+	if promotion.ValidTo != nil {
+		if promotion.ValidFrom.IsZero() && !promotion.ValidTo.After(time.Now()) {
+			return nil, fmt.Errorf("%w: valid_to must be in the future", domain.ErrInvalidPromotion)
+		}
+		if !promotion.ValidFrom.IsZero() && !promotion.ValidTo.After(promotion.ValidFrom) {
+			return nil, fmt.Errorf("%w: valid_to must be after valid_from", domain.ErrInvalidPromotion)
+		}
+	}
+
+	currency, err := normalizeCurrency(promotion.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPromotion, err)
+	}
+	if currency == "" {
+		currency = "JPY"
+	}
+	promotion.Currency = currency
+	promotion.Description = strings.TrimSpace(promotion.Description)
+	// The validity window is stored without a time zone and compared with
+	// the database's UTC clock
+	promotion.ValidFrom = promotion.ValidFrom.UTC()
+	if promotion.ValidTo != nil {
+		validTo := promotion.ValidTo.UTC()
+		promotion.ValidTo = &validTo
+	}
+
+	return u.repo.Create(promotion)
+}
+
+func (u *PromotionUsecase) List(opts PromotionListOptions) ([]domain.Promotion, error) {
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindAll(
+		opts.StoreID,
+		opts.ProductID,
+		opts.ActiveOnly,
+		tax,
+		normalizeLimit(opts.Limit),
+		normalizeOffset(opts.Offset),
+	)
+}
+
+func (u *PromotionUsecase) GetByID(id int, tax query.TaxMode) (*domain.Promotion, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	tax, err := normalizeTaxMode(tax)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(id, tax)
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+	"github.com/price-comparison/server/internal/query"
+)
+
+type promotionRepoStub struct {
+	created []domain.Promotion
+}
+
+func (s *promotionRepoStub) Create(promotion domain.Promotion) (*domain.Promotion, error) {
+	s.created = append(s.created, promotion)
+	return &promotion, nil
+}
+
+func (s *promotionRepoStub) FindAll(storeID, productID int, activeOnly bool, tax query.TaxMode, limit, offset int) ([]domain.Promotion, error) {
+	return []domain.Promotion{}, nil
+}
+
+func (s *promotionRepoStub) FindByID(id int, tax query.TaxMode) (*domain.Promotion, error) {
+	return nil, nil
+}
+
+func TestCreatePromotionDefaultsCurrency(t *testing.T) {
+	stub := &promotionRepoStub{}
+	uc := NewPromotionUsecase(stub)
+	bundleQuantity, bundlePrice := 2, 300.0
+
+	created, err := uc.Create(domain.Promotion{
+		StoreID:        1,
+		ProductID:      2,
+		BundleQuantity: &bundleQuantity,
+		BundlePrice:    &bundlePrice,
+		Description:    "  2個で300円  ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Currency != "JPY" || created.Description != "2個で300円" {
+		t.Fatalf("expected normalized promotion, got %+v", created)
+	}
+}
+
+func TestCreatePromotionValidates(t *testing.T) {
+	stub := &promotionRepoStub{}
+	uc := NewPromotionUsecase(stub)
+	salePrice, negative := 198.0, -1.0
+	one, two := 1, 2
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	before := from.Add(-time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	invalid := []domain.Promotion{
+		{ProductID: 2, SalePrice: &salePrice},
+		{StoreID: 1, ProductID: 2},
+		{StoreID: 1, ProductID: 2, SalePrice: &negative},
+		{StoreID: 1, ProductID: 2, BundleQuantity: &two},
+		{StoreID: 1, ProductID: 2, BundleQuantity: &one, BundlePrice: &salePrice},
+		{StoreID: 1, ProductID: 2, SalePrice: &salePrice, ValidFrom: from, ValidTo: &before},
+		{StoreID: 1, ProductID: 2, SalePrice: &salePrice, ValidTo: &past},
+		{StoreID: 1, ProductID: 2, SalePrice: &salePrice, Currency: "JP"},
+	}
+	for _, promotion := range invalid {
+		if _, err := uc.Create(promotion); !errors.Is(err, domain.ErrInvalidPromotion) {
+			t.Errorf("expected ErrInvalidPromotion for %+v, got %v", promotion, err)
+		}
+	}
+	if len(stub.created) != 0 {
+		t.Fatalf("expected nothing to be saved, got %d", len(stub.created))
+	}
+}
+
+func TestCreatePromotionStoresUTC(t *testing.T) {
+	stub := &promotionRepoStub{}
+	uc := NewPromotionUsecase(stub)
+	salePrice := 198.0
+	jst := time.FixedZone("JST", 9*3600)
+	validTo := time.Date(2024, 2, 4, 23, 59, 59, 0, jst)
+
+	if _, err := uc.Create(domain.Promotion{
+		StoreID:   1,
+		ProductID: 2,
+		SalePrice: &salePrice,
+		ValidFrom: time.Date(2024, 2, 1, 0, 0, 0, 0, jst),
+		ValidTo:   &validTo,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created := stub.created[0]
+	if created.ValidFrom.Location() != time.UTC || created.ValidFrom.Hour() != 15 {
+		t.Fatalf("expected valid_from in UTC, got %s", created.ValidFrom)
+	}
+	if created.ValidTo.Location() != time.UTC || created.ValidTo.Hour() != 14 || created.ValidTo.Day() != 4 {
+		t.Fatalf("expected valid_to in UTC, got %s", created.ValidTo)
+	}
+}
+
+func TestNormalizePurchase(t *testing.T) {
+	purchase, err := normalizePurchase(0, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purchase.Quantity != 1 || !purchase.Member {
+		t.Fatalf("expected a single member unit, got %+v", purchase)
+	}
+	if _, err := normalizePurchase(MaxPurchaseQuantity+1, false); err == nil {
+		t.Fatalf("expected error for quantity over %d", MaxPurchaseQuantity)
+	}
+}
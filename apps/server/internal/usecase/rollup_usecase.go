@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+)
+
+type RollupRepository interface {
+	Refresh(maxKeys int) (int, error)
+}
+
+// RefreshRollupsJobType is the background job that brings the daily price
+// aggregates up to date with recent price writes
+const RefreshRollupsJobType = "rollup.refresh"
+
+// RefreshRollupsJob is the payload of a RefreshRollupsJobType job
+type RefreshRollupsJob struct{}
+
+// rollupRefreshBatch bounds the store/product/days recomputed per
+// transaction
+const rollupRefreshBatch = 10000
+
+type RollupUsecase struct {
+	repo RollupRepository
+}
+
+func NewRollupUsecase(repo RollupRepository) *RollupUsecase {
+	return &RollupUsecase{repo: repo}
+}
+
+// Refresh recomputes queued days in batches until the queue is drained
+func (u *RollupUsecase) Refresh(ctx context.Context, _ RefreshRollupsJob) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		taken, err := u.repo.Refresh(rollupRefreshBatch)
+		if err != nil {
+			return err
+		}
+		if taken < rollupRefreshBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type rollupRepoStub struct {
+	batches []int
+	calls   int
+	err     error
+}
+
+func (s *rollupRepoStub) Refresh(maxKeys int) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	taken := 0
+	if s.calls < len(s.batches) {
+		taken = s.batches[s.calls]
+	}
+	s.calls++
+	return taken, nil
+}
+
+func TestRollupRefreshDrainsQueue(t *testing.T) {
+	repo := &rollupRepoStub{batches: []int{rollupRefreshBatch, rollupRefreshBatch, 42}}
+	if err := NewRollupUsecase(repo).Refresh(context.Background(), RefreshRollupsJob{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 3 {
+		t.Fatalf("expected refreshes until a partial batch, got %d", repo.calls)
+	}
+}
+
+func TestRollupRefreshStops(t *testing.T) {
+	failing := &rollupRepoStub{err: errors.New("connection reset")}
+	if err := NewRollupUsecase(failing).Refresh(context.Background(), RefreshRollupsJob{}); err == nil {
+		t.Fatalf("expected the refresh error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	busy := &rollupRepoStub{batches: []int{rollupRefreshBatch}}
+	if err := NewRollupUsecase(busy).Refresh(ctx, RefreshRollupsJob{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if busy.calls != 0 {
+		t.Fatalf("expected no refresh after cancellation, got %d", busy.calls)
+	}
+}
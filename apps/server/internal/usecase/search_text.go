@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/price-comparison/server/internal/domain"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeSearchText mirrors the search_normalize SQL function: NFKC folds
+// full-width ASCII and half-width katakana, then text is lowercased and
+// katakana is folded to hiragana.
+func normalizeSearchText(value string) string {
+	runes, _ := normalizeWithOffsets(value)
+	return strings.TrimSpace(string(runes))
+}
+
+// normalizeWithOffsets normalizes value and records, for every normalized
+// rune, the range of original rune offsets it was produced from.
+func normalizeWithOffsets(value string) ([]rune, []domain.TextSpan) {
+	var out []rune
+	var origins []domain.TextSpan
+
+	var it norm.Iter
+	it.InitString(norm.NFKC, value)
+	consumed := 0
+	runePos := 0
+	for !it.Done() {
+		segment := it.Next()
+		end := it.Pos()
+		segmentRunes := utf8.RuneCountInString(value[consumed:end])
+		origin := domain.TextSpan{Start: runePos, End: runePos + segmentRunes}
+		for _, r := range string(segment) {
+			out = append(out, foldSearchRune(r))
+			origins = append(origins, origin)
+		}
+		consumed = end
+		runePos += segmentRunes
+	}
+
+	return out, origins
+}
+
+func foldSearchRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 0x60
+	}
+	return r
+}
+
+// highlightSpans finds every whitespace-separated keyword term in text and
+// returns the matched ranges as merged rune offsets into the original text.
+func highlightSpans(text, keyword string) []domain.TextSpan {
+	normalized, origins := normalizeWithOffsets(text)
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	var spans []domain.TextSpan
+	for _, term := range strings.Fields(normalizeSearchText(keyword)) {
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(normalized); i++ {
+			if !runesEqual(normalized[i:i+len(termRunes)], termRunes) {
+				continue
+			}
+			spans = append(spans, domain.TextSpan{
+				Start: origins[i].Start,
+				End:   origins[i+len(termRunes)-1].End,
+			})
+		}
+	}
+
+	return mergeSpans(spans)
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeSpans(spans []domain.TextSpan) []domain.TextSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	merged := []domain.TextSpan{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			if span.End > last.End {
+				last.End = span.End
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
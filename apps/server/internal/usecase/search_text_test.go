@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+func TestNormalizeSearchText(t *testing.T) {
+	cases := map[string]string{
+		"ＣＯＣＡ－ＣＯＬＡ": "coca-cola",
+		"ｶﾞﾑ":       "がむ",
+		"ミルク　Tea":   "みるく tea",
+		"  牛乳  ":    "牛乳",
+	}
+	for input, want := range cases {
+		if got := normalizeSearchText(input); got != want {
+			t.Errorf("normalizeSearchText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHighlightSpansMapToOriginalOffsets(t *testing.T) {
+	spans := highlightSpans("ﾐﾙｸｺｰﾋｰ ｶﾞﾑ", "こーひー がむ")
+	want := []domain.TextSpan{{Start: 3, End: 7}, {Start: 8, End: 11}}
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d spans, got %+v", len(want), spans)
+	}
+	for i := range want {
+		if spans[i] != want[i] {
+			t.Fatalf("span %d: expected %+v, got %+v", i, want[i], spans[i])
+		}
+	}
+}
+
+func TestHighlightSpansMergesOverlaps(t *testing.T) {
+	spans := highlightSpans("牛乳パン", "牛乳 乳ぱ")
+	if len(spans) != 1 || spans[0] != (domain.TextSpan{Start: 0, End: 3}) {
+		t.Fatalf("expected merged span, got %+v", spans)
+	}
+}
@@ -2,41 +2,84 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/price-comparison/server/internal/domain"
 	"github.com/price-comparison/server/internal/query"
+	"github.com/price-comparison/server/internal/routing"
 )
 
 type StoreRepository interface {
-	FindNearby(lat, lon float64, radiusMeters int, limit, offset int) ([]domain.Store, error)
+	FindNearby(lat, lon float64, radiusMeters int, filters query.NearbyFilters, limit, offset int) ([]domain.Store, error)
 	FindAll(filters query.StoreFilters, limit, offset int, sortField, sortOrder string) ([]domain.Store, error)
+	FindClusters(filters query.StoreFilters, cellSizeMeters float64) ([]domain.StoreCluster, error)
 	FindByID(id int) (*domain.Store, error)
 }
 
+// TravelTimeRouter computes road-network travel times in seconds, returning
+// routing.Unreachable for destinations outside the budget
+type TravelTimeRouter interface {
+	TravelTimes(origin query.GeoPoint, destinations []query.GeoPoint, mode routing.Mode, budget time.Duration) ([]float64, error)
+}
+
+const (
+	MaxTravelMinutes = 60
+	// clusterCellPixels is the on-screen size of a cluster grid cell
+	clusterCellPixels = 64
+	// webMercatorWorldMeters is the width of the world at zoom 0 in EPSG:3857
+	webMercatorWorldMeters = 2 * math.Pi * 6378137
+	// travelCandidateLimit caps how many straight-line candidates are routed
+	travelCandidateLimit = 500
+)
+
 type StoreUsecase struct {
 	repo     StoreRepository
+	router   TravelTimeRouter
 	cache    Cache
 	cacheTTL time.Duration
 }
 
-func NewStoreUsecase(repo StoreRepository, cache Cache, cacheTTL time.Duration) *StoreUsecase {
-	return &StoreUsecase{repo: repo, cache: cache, cacheTTL: cacheTTL}
+func NewStoreUsecase(repo StoreRepository, router TravelTimeRouter, cache Cache, cacheTTL time.Duration) *StoreUsecase {
+	return &StoreUsecase{repo: repo, router: router, cache: cache, cacheTTL: cacheTTL}
 }
 
 func (u *StoreUsecase) List(opts StoreListOptions) ([]domain.Store, error) {
+	if opts.MinPrice != nil && opts.MaxPrice != nil && *opts.MinPrice > *opts.MaxPrice {
+		return nil, fmt.Errorf("min price must not exceed max price")
+	}
+	if opts.RecordedWithinDays < 0 {
+		return nil, fmt.Errorf("recorded within days must not be negative")
+	}
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+	opts.Currency = currency
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	opts.Tax = tax
+	minConfidence, err := normalizeMinConfidence(opts.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+	opts.MinConfidence = minConfidence
+	purchase, err := normalizePurchase(opts.Quantity, opts.Member)
+	if err != nil {
+		return nil, err
+	}
+	opts.Quantity = purchase.Quantity
 	limit := normalizeLimit(opts.Limit)
 	offset := normalizeOffset(opts.Offset)
 	sortField, sortOrder := normalizeStoreSort(opts.Sort, opts.UserLocation != nil)
-
-	filters := query.StoreFilters{
-		Query:        opts.Query,
-		Category:     opts.Category,
-		Bounds:       opts.Bounds,
-		UserLocation: opts.UserLocation,
-	}
+	filters := buildStoreFilters(opts)
 
 	cacheKey := buildStoreCacheKey(filters, limit, offset, sortField, sortOrder)
 	if u.cache != nil {
@@ -62,13 +105,96 @@ func (u *StoreUsecase) List(opts StoreListOptions) ([]domain.Store, error) {
 	return stores, nil
 }
 
+// Clusters aggregates the stores matching the list filters within Bounds
+// into grid cells sized for the zoom level. Pagination and sort are ignored.
+func (u *StoreUsecase) Clusters(opts StoreClusterOptions) ([]domain.StoreCluster, error) {
+	if opts.Bounds == nil {
+		return nil, fmt.Errorf("bounds are required for clustering")
+	}
+	if opts.Zoom < 0 || opts.Zoom > MaxTileZoom {
+		return nil, fmt.Errorf("zoom must be between 0 and %d", MaxTileZoom)
+	}
+	if opts.MinPrice != nil && opts.MaxPrice != nil && *opts.MinPrice > *opts.MaxPrice {
+		return nil, fmt.Errorf("min price must not exceed max price")
+	}
+	currency, err := normalizeCurrency(opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+	opts.Currency = currency
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	opts.Tax = tax
+	minConfidence, err := normalizeMinConfidence(opts.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+	opts.MinConfidence = minConfidence
+	purchase, err := normalizePurchase(opts.Quantity, opts.Member)
+	if err != nil {
+		return nil, err
+	}
+	opts.Quantity = purchase.Quantity
+
+	filters := buildStoreFilters(opts.StoreListOptions)
+	filters.UserLocation = nil
+	cacheKey := fmt.Sprintf("stores:clusters:%d:%s", opts.Zoom, buildStoreCacheKey(filters, 0, 0, "", ""))
+	if u.cache != nil {
+		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
+			var clusters []domain.StoreCluster
+			if err := json.Unmarshal([]byte(cached), &clusters); err == nil {
+				return clusters, nil
+			}
+		}
+	}
+
+	clusters, err := u.repo.FindClusters(filters, clusterCellSize(opts.Zoom))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil {
+		if payload, err := json.Marshal(clusters); err == nil {
+			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
+		}
+	}
+
+	return clusters, nil
+}
+
+// clusterCellSize is the grid cell edge in Web Mercator meters that spans
+// clusterCellPixels on a 256px-tile map at zoom
+func clusterCellSize(zoom int) float64 {
+	return webMercatorWorldMeters / float64(int(256)<<zoom) * clusterCellPixels
+}
+
 func (u *StoreUsecase) Nearby(opts StoreNearbyOptions) ([]domain.Store, error) {
-	if opts.Radius <= 0 {
+	if opts.TravelMode == "" && opts.Radius <= 0 {
 		return nil, fmt.Errorf("radius must be positive")
 	}
+	if opts.TravelMode != "" {
+		if u.router == nil {
+			return nil, domain.ErrTravelTimeUnavailable
+		}
+		if opts.TravelMinutes <= 0 || opts.TravelMinutes > MaxTravelMinutes {
+			return nil, fmt.Errorf("travel minutes must be between 1 and %d", MaxTravelMinutes)
+		}
+	}
 	limit := normalizeLimit(opts.Limit)
 	offset := normalizeOffset(opts.Offset)
-	cacheKey := fmt.Sprintf("stores:nearby:%.5f:%.5f:%d:%d:%d", opts.Latitude, opts.Longitude, opts.Radius, limit, offset)
+	filters := query.NearbyFilters{OpenAt: truncateOpenAt(opts.OpenAt)}
+	cacheKey := fmt.Sprintf("stores:nearby:%.5f:%.5f:%d:%s:%d:%s:%d:%d",
+		opts.Latitude,
+		opts.Longitude,
+		opts.Radius,
+		opts.TravelMode,
+		opts.TravelMinutes,
+		formatOpenAt(filters.OpenAt),
+		limit,
+		offset,
+	)
 	if u.cache != nil {
 		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
 			var stores []domain.Store
@@ -78,7 +204,13 @@ func (u *StoreUsecase) Nearby(opts StoreNearbyOptions) ([]domain.Store, error) {
 		}
 	}
 
-	stores, err := u.repo.FindNearby(opts.Latitude, opts.Longitude, opts.Radius, limit, offset)
+	var stores []domain.Store
+	var err error
+	if opts.TravelMode != "" {
+		stores, err = u.nearbyByTravelTime(opts, filters, limit, offset)
+	} else {
+		stores, err = u.repo.FindNearby(opts.Latitude, opts.Longitude, opts.Radius, filters, limit, offset)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +224,51 @@ func (u *StoreUsecase) Nearby(opts StoreNearbyOptions) ([]domain.Store, error) {
 	return stores, nil
 }
 
+// nearbyByTravelTime prefilters candidates by the furthest straight-line
+// distance the mode could cover, then keeps those reachable over the road
+// network within the budget, ordered by travel time
+func (u *StoreUsecase) nearbyByTravelTime(opts StoreNearbyOptions, filters query.NearbyFilters, limit, offset int) ([]domain.Store, error) {
+	budget := time.Duration(opts.TravelMinutes) * time.Minute
+	radius := int(opts.TravelMode.MaxSpeed() * budget.Seconds())
+
+	candidates, err := u.repo.FindNearby(opts.Latitude, opts.Longitude, radius, filters, travelCandidateLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := make([]query.GeoPoint, len(candidates))
+	for i, store := range candidates {
+		destinations[i] = query.GeoPoint{Lat: store.Latitude, Lon: store.Longitude}
+	}
+	origin := query.GeoPoint{Lat: opts.Latitude, Lon: opts.Longitude}
+	times, err := u.router.TravelTimes(origin, destinations, opts.TravelMode, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make([]domain.Store, 0, len(candidates))
+	for i, store := range candidates {
+		if times[i] == routing.Unreachable {
+			continue
+		}
+		seconds := times[i]
+		store.TravelTime = &seconds
+		reachable = append(reachable, store)
+	}
+	sort.SliceStable(reachable, func(i, j int) bool {
+		return *reachable[i].TravelTime < *reachable[j].TravelTime
+	})
+
+	if offset >= len(reachable) {
+		return []domain.Store{}, nil
+	}
+	end := offset + limit
+	if end > len(reachable) {
+		end = len(reachable)
+	}
+	return reachable[offset:end], nil
+}
+
 func (u *StoreUsecase) GetByID(id int) (*domain.Store, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("id must be positive")
@@ -111,6 +288,10 @@ func normalizeStoreSort(sort Sort, hasLocation bool) (string, string) {
 		return "name", order
 	case "price":
 		return "price", order
+	case "unit_price":
+		return "unit_price", order
+	case "competitiveness":
+		return "competitiveness", order
 	case "created_at":
 		return "created_at", order
 	case "name":
@@ -120,6 +301,27 @@ func normalizeStoreSort(sort Sort, hasLocation bool) (string, string) {
 	}
 }
 
+func buildStoreFilters(opts StoreListOptions) query.StoreFilters {
+	return query.StoreFilters{
+		Query:              opts.Query,
+		Categories:         normalizeStringSet(opts.Categories),
+		MinPrice:           opts.MinPrice,
+		MaxPrice:           opts.MaxPrice,
+		ProductIDs:         normalizeIDSet(opts.ProductIDs),
+		ChainIDs:           normalizeIDSet(opts.ChainIDs),
+		RecordedWithinDays: opts.RecordedWithinDays,
+		Currency:           opts.Currency,
+		Tax:                opts.Tax,
+		MinConfidence:      opts.MinConfidence,
+		Purchase:           query.Purchase{Quantity: opts.Quantity, Member: opts.Member},
+		OpenAt:             truncateOpenAt(opts.OpenAt),
+		Bounds:             opts.Bounds,
+		AreaGeoJSON:        opts.AreaGeoJSON,
+		AreaID:             opts.AreaID,
+		UserLocation:       opts.UserLocation,
+	}
+}
+
 func buildStoreCacheKey(filters query.StoreFilters, limit, offset int, sortField, sortOrder string) string {
 	boundsKey := "none"
 	if filters.Bounds != nil {
@@ -136,10 +338,28 @@ func buildStoreCacheKey(filters query.StoreFilters, limit, offset int, sortField
 		locationKey = fmt.Sprintf("%.4f:%.4f", filters.UserLocation.Lat, filters.UserLocation.Lon)
 	}
 
-	return fmt.Sprintf("stores:list:%s:%s:%s:%s:%d:%d:%s:%s",
+	areaKey := "none"
+	if filters.AreaGeoJSON != "" {
+		areaKey = fmt.Sprintf("%x", sha1.Sum([]byte(filters.AreaGeoJSON)))
+	}
+
+	return fmt.Sprintf("stores:list:%s:%s:%s:%s:%s:%s:%d:%s:%s:%g:%d:%t:%s:%s:%s:%d:%s:%d:%d:%s:%s",
 		filters.Query,
-		filters.Category,
+		strings.Join(filters.Categories, ","),
+		formatOptionalFloat(filters.MinPrice),
+		formatOptionalFloat(filters.MaxPrice),
+		joinIDs(filters.ProductIDs),
+		joinIDs(filters.ChainIDs),
+		filters.RecordedWithinDays,
+		filters.Currency,
+		filters.Tax,
+		filters.MinConfidence,
+		filters.Purchase.Quantity,
+		filters.Purchase.Member,
+		formatOpenAt(filters.OpenAt),
 		boundsKey,
+		areaKey,
+		filters.AreaID,
 		locationKey,
 		limit,
 		offset,
@@ -147,3 +367,20 @@ func buildStoreCacheKey(filters query.StoreFilters, limit, offset int, sortField
 		sortOrder,
 	)
 }
+
+// truncateOpenAt rounds open-hours lookups to the minute so "open now"
+// requests within the same minute share cached results
+func truncateOpenAt(openAt *time.Time) *time.Time {
+	if openAt == nil {
+		return nil
+	}
+	truncated := openAt.UTC().Truncate(time.Minute)
+	return &truncated
+}
+
+func formatOpenAt(openAt *time.Time) string {
+	if openAt == nil {
+		return "none"
+	}
+	return openAt.UTC().Format("200601021504")
+}
@@ -1,24 +1,41 @@
 package usecase
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/price-comparison/server/internal/domain"
 	"github.com/price-comparison/server/internal/query"
+	"github.com/price-comparison/server/internal/routing"
 )
 
+type routerStub struct {
+	times []float64
+}
+
+func (r *routerStub) TravelTimes(origin query.GeoPoint, destinations []query.GeoPoint, mode routing.Mode, budget time.Duration) ([]float64, error) {
+	return r.times, nil
+}
+
 type storeRepoStub struct {
+	nearbyStores  []domain.Store
+	lastFilters   query.StoreFilters
+	lastNearby    query.NearbyFilters
 	lastLimit     int
 	lastOffset    int
 	lastSortField string
 	lastSortOrder string
+	lastCellSize  float64
 }
 
-func (s *storeRepoStub) FindNearby(lat, lon float64, radiusMeters int, limit, offset int) ([]domain.Store, error) {
-	return nil, nil
+func (s *storeRepoStub) FindNearby(lat, lon float64, radiusMeters int, filters query.NearbyFilters, limit, offset int) ([]domain.Store, error) {
+	s.lastNearby = filters
+	return s.nearbyStores, nil
 }
 
 func (s *storeRepoStub) FindAll(filters query.StoreFilters, limit, offset int, sortField, sortOrder string) ([]domain.Store, error) {
+	s.lastFilters = filters
 	s.lastLimit = limit
 	s.lastOffset = offset
 	s.lastSortField = sortField
@@ -26,13 +43,19 @@ func (s *storeRepoStub) FindAll(filters query.StoreFilters, limit, offset int, s
 	return []domain.Store{}, nil
 }
 
+func (s *storeRepoStub) FindClusters(filters query.StoreFilters, cellSizeMeters float64) ([]domain.StoreCluster, error) {
+	s.lastFilters = filters
+	s.lastCellSize = cellSizeMeters
+	return []domain.StoreCluster{}, nil
+}
+
 func (s *storeRepoStub) FindByID(id int) (*domain.Store, error) {
 	return nil, nil
 }
 
 func TestStoreListDefaults(t *testing.T) {
 	stub := &storeRepoStub{}
-	uc := NewStoreUsecase(stub, nil, 0)
+	uc := NewStoreUsecase(stub, nil, nil, 0)
 
 	if _, err := uc.List(StoreListOptions{}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -51,3 +74,178 @@ func TestStoreListDefaults(t *testing.T) {
 		t.Fatalf("expected sort order ASC, got %s", stub.lastSortOrder)
 	}
 }
+
+func TestStoreListSortsByUnitPrice(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	if _, err := uc.List(StoreListOptions{Sort: Sort{Field: "unit_price", Order: "asc"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stub.lastSortField != "unit_price" {
+		t.Fatalf("expected sort field unit_price, got %s", stub.lastSortField)
+	}
+}
+
+func TestStoreListSortsByCompetitiveness(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	if _, err := uc.List(StoreListOptions{Sort: Sort{Field: "competitiveness"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stub.lastSortField != "competitiveness" || stub.lastSortOrder != "ASC" {
+		t.Fatalf("expected competitiveness ascending, got %s %s", stub.lastSortField, stub.lastSortOrder)
+	}
+}
+
+func TestStoreListTaxMode(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	if _, err := uc.List(StoreListOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastFilters.Tax != query.TaxIncluded {
+		t.Fatalf("expected tax-inclusive prices by default, got %q", stub.lastFilters.Tax)
+	}
+
+	if _, err := uc.List(StoreListOptions{Tax: query.TaxExcluded}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastFilters.Tax != query.TaxExcluded {
+		t.Fatalf("expected tax-exclusive prices, got %q", stub.lastFilters.Tax)
+	}
+
+	if _, err := uc.List(StoreListOptions{Tax: "gross"}); err == nil {
+		t.Fatalf("expected error for unknown tax mode")
+	}
+}
+
+func TestStoreListMinConfidence(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	if _, err := uc.List(StoreListOptions{MinConfidence: 0.25}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastFilters.MinConfidence != 0.25 {
+		t.Fatalf("expected threshold to reach the filters, got %v", stub.lastFilters.MinConfidence)
+	}
+
+	for _, invalid := range []float64{-0.1, 1.5} {
+		if _, err := uc.List(StoreListOptions{MinConfidence: invalid}); err == nil {
+			t.Fatalf("expected error for min confidence %v", invalid)
+		}
+	}
+}
+
+func TestStoreListNormalizesMultiValueFilters(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	_, err := uc.List(StoreListOptions{
+		Categories: []string{"飲料", "", "乳製品", "飲料"},
+		ProductIDs: []int{3, 1, 3, -2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	categories := stub.lastFilters.Categories
+	if len(categories) != 2 || categories[0] != "乳製品" || categories[1] != "飲料" {
+		t.Fatalf("unexpected categories: %v", categories)
+	}
+	ids := stub.lastFilters.ProductIDs
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("unexpected product ids: %v", ids)
+	}
+}
+
+func TestStoreListRejectsInvertedPriceRange(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	minPrice, maxPrice := 300.0, 100.0
+	if _, err := uc.List(StoreListOptions{MinPrice: &minPrice, MaxPrice: &maxPrice}); err == nil {
+		t.Fatalf("expected error for min price above max price")
+	}
+}
+
+func TestStoreNearbyTruncatesOpenAt(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+
+	openAt := time.Date(2024, 5, 3, 21, 45, 30, 0, time.FixedZone("JST", 9*3600))
+	if _, err := uc.Nearby(StoreNearbyOptions{Latitude: 35.68, Longitude: 139.76, Radius: 1000, OpenAt: &openAt}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := stub.lastNearby.OpenAt
+	want := time.Date(2024, 5, 3, 12, 45, 0, 0, time.UTC)
+	if got == nil || !got.Equal(want) {
+		t.Fatalf("expected open at %v, got %v", want, got)
+	}
+}
+
+func TestStoreNearbyByTravelTimeSortsAndDropsUnreachable(t *testing.T) {
+	stub := &storeRepoStub{nearbyStores: []domain.Store{{ID: 1}, {ID: 2}, {ID: 3}}}
+	router := &routerStub{times: []float64{600, routing.Unreachable, 120}}
+	uc := NewStoreUsecase(stub, router, nil, 0)
+
+	stores, err := uc.Nearby(StoreNearbyOptions{
+		Latitude:      35.68,
+		Longitude:     139.76,
+		TravelMode:    routing.Walking,
+		TravelMinutes: 15,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stores) != 2 || stores[0].ID != 3 || stores[1].ID != 1 {
+		t.Fatalf("expected stores 3 then 1, got %+v", stores)
+	}
+	if *stores[0].TravelTime != 120 {
+		t.Fatalf("expected travel time 120, got %v", *stores[0].TravelTime)
+	}
+}
+
+func TestStoreNearbyByTravelTimeRequiresRouter(t *testing.T) {
+	uc := NewStoreUsecase(&storeRepoStub{}, nil, nil, 0)
+
+	if _, err := uc.Nearby(StoreNearbyOptions{TravelMode: routing.Driving, TravelMinutes: 10}); !errors.Is(err, domain.ErrTravelTimeUnavailable) {
+		t.Fatalf("expected ErrTravelTimeUnavailable without a router, got %v", err)
+	}
+}
+
+func TestStoreClustersRequiresBounds(t *testing.T) {
+	uc := NewStoreUsecase(&storeRepoStub{}, nil, nil, 0)
+
+	if _, err := uc.Clusters(StoreClusterOptions{Zoom: 10}); err == nil {
+		t.Fatalf("expected error without bounds")
+	}
+}
+
+func TestStoreClustersCellShrinksWithZoom(t *testing.T) {
+	stub := &storeRepoStub{}
+	uc := NewStoreUsecase(stub, nil, nil, 0)
+	bounds := &query.Bounds{MinLat: 35.6, MinLon: 139.6, MaxLat: 35.8, MaxLon: 139.9}
+
+	if _, err := uc.Clusters(StoreClusterOptions{StoreListOptions: StoreListOptions{Bounds: bounds}, Zoom: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	atTen := stub.lastCellSize
+	if _, err := uc.Clusters(StoreClusterOptions{StoreListOptions: StoreListOptions{Bounds: bounds}, Zoom: 11}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if atTen <= 0 || stub.lastCellSize != atTen/2 {
+		t.Fatalf("expected cell size to halve per zoom level, got %f then %f", atTen, stub.lastCellSize)
+	}
+	if stub.lastFilters.Bounds != bounds {
+		t.Fatalf("expected bounds to be passed through")
+	}
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type SubmissionRepository interface {
+	UpsertContributor(externalID, displayName string) (*domain.Contributor, error)
+	FindContributors(limit, offset int) ([]domain.Contributor, error)
+	FindContributorByID(id int) (*domain.Contributor, error)
+	UpdateContributorRole(id int, role string, storeID *int) (*domain.Contributor, error)
+	Create(submission domain.PriceSubmission) (*domain.PriceSubmission, error)
+	FindAll(status string, storeID, contributorID int, limit, offset int) ([]domain.PriceSubmission, error)
+	FindByID(id int) (*domain.PriceSubmission, error)
+	Review(id int, review domain.SubmissionReview) (*domain.PriceSubmission, error)
+}
+
+// Limits on submitted prices: how long after seeing a price it can still be
+// reported, and how far ahead of the server clock the app's clock may run
+const (
+	MaxSubmissionAge      = 30 * 24 * time.Hour
+	maxSubmissionClockRun = 5 * time.Minute
+	maxContributorIDLen   = 100
+	maxSubmissionNoteLen  = 500
+)
+
+// TrustPolicy decides which contributors' submissions are approved without
+// moderation: store managers for their own store, and contributors with at
+// least MinApproved approved submissions and MinReputation
+type TrustPolicy struct {
+	MinApproved   int
+	MinReputation float64
+}
+
+func (p TrustPolicy) Trusts(contributor domain.Contributor, storeID int) bool {
+	if isManagerOf(contributor, storeID) {
+		return true
+	}
+	return contributor.ApprovedCount >= p.MinApproved && contributor.Reputation >= p.MinReputation
+}
+
+type SubmissionUsecase struct {
+	repo   SubmissionRepository
+	policy TrustPolicy
+}
+
+func NewSubmissionUsecase(repo SubmissionRepository, policy TrustPolicy) *SubmissionUsecase {
+	return &SubmissionUsecase{repo: repo, policy: policy}
+}
+
+// Submit records a price reported by the contributor with externalID,
+// registering them on their first submission. It is approved straight
+// away when the contributor is trusted and left pending otherwise.
+// Validation failures wrap domain.ErrInvalidSubmission, and a nil
+// submission means the store or product does not exist.
+func (u *SubmissionUsecase) Submit(externalID, displayName string, submission domain.PriceSubmission) (*domain.PriceSubmission, error) {
+	externalID = strings.TrimSpace(externalID)
+	displayName = strings.TrimSpace(displayName)
+	if externalID == "" {
+		return nil, fmt.Errorf("%w: contributor id is required", domain.ErrInvalidSubmission)
+	}
+	if utf8.RuneCountInString(externalID) > maxContributorIDLen || utf8.RuneCountInString(displayName) > maxContributorIDLen {
+		return nil, fmt.Errorf("%w: contributor id and name must be at most %d characters", domain.ErrInvalidSubmission, maxContributorIDLen)
+	}
+	if err := u.validateSubmission(&submission); err != nil {
+		return nil, err
+	}
+
+	contributor, err := u.repo.UpsertContributor(externalID, displayName)
+	if err != nil {
+		return nil, err
+	}
+	submission.ContributorID = contributor.ID
+	created, err := u.repo.Create(submission)
+	if err != nil || created == nil {
+		return nil, err
+	}
+	if !u.policy.Trusts(*contributor, submission.StoreID) {
+		return created, nil
+	}
+
+	approved, err := u.repo.Review(created.ID, domain.SubmissionReview{
+		Status:     domain.SubmissionStatusApproved,
+		SourceType: sourceTypeFor(*contributor, submission.StoreID),
+		Auto:       true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if approved == nil {
+		return created, nil
+	}
+	return approved, nil
+}
+
+func (u *SubmissionUsecase) validateSubmission(submission *domain.PriceSubmission) error {
+	if submission.StoreID <= 0 || submission.ProductID <= 0 {
+		return fmt.Errorf("%w: store id and product id must be positive", domain.ErrInvalidSubmission)
+	}
+	if submission.Price <= 0 {
+		return fmt.Errorf("%w: price must be positive", domain.ErrInvalidSubmission)
+	}
+
+	currency, err := normalizeCurrency(submission.Currency)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidSubmission, err)
+	}
+	if currency == "" {
+		currency = "JPY"
+	}
+	submission.Currency = currency
+
+	submission.PhotoURL = strings.TrimSpace(submission.PhotoURL)
+	if submission.PhotoURL != "" {
+		photo, err := url.Parse(submission.PhotoURL)
+		if err != nil || (photo.Scheme != "http" && photo.Scheme != "https") || photo.Host == "" {
+			return fmt.Errorf("%w: photo_url must be an http(s) URL", domain.ErrInvalidSubmission)
+		}
+	}
+	submission.Note = strings.TrimSpace(submission.Note)
+	if utf8.RuneCountInString(submission.Note) > maxSubmissionNoteLen {
+		return fmt.Errorf("%w: note must be at most %d characters", domain.ErrInvalidSubmission, maxSubmissionNoteLen)
+	}
+
+	if !submission.ObservedAt.IsZero() {
+		now := time.Now()
+		if submission.ObservedAt.After(now.Add(maxSubmissionClockRun)) {
+			return fmt.Errorf("%w: observed_at must not be in the future", domain.ErrInvalidSubmission)
+		}
+		if submission.ObservedAt.Before(now.Add(-MaxSubmissionAge)) {
+			return fmt.Errorf("%w: observed_at must be within the last %d days", domain.ErrInvalidSubmission, int(MaxSubmissionAge.Hours()/24))
+		}
+		// Stored without a time zone, like every other recorded time
+		submission.ObservedAt = submission.ObservedAt.UTC()
+	}
+	return nil
+}
+
+// List returns submissions in one moderation state, pending by default
+func (u *SubmissionUsecase) List(opts SubmissionListOptions) ([]domain.PriceSubmission, error) {
+	status := opts.Status
+	if status == "" {
+		status = domain.SubmissionStatusPending
+	}
+	switch status {
+	case domain.SubmissionStatusPending, domain.SubmissionStatusApproved, domain.SubmissionStatusRejected:
+	default:
+		return nil, fmt.Errorf("status must be %s, %s or %s",
+			domain.SubmissionStatusPending, domain.SubmissionStatusApproved, domain.SubmissionStatusRejected)
+	}
+	return u.repo.FindAll(status, opts.StoreID, opts.ContributorID, normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+func (u *SubmissionUsecase) GetByID(id int) (*domain.PriceSubmission, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	return u.repo.FindByID(id)
+}
+
+// Review approves a pending submission into prices or rejects it; either
+// way the decision counts towards the contributor's reputation and is
+// recorded as reviewer's. It returns nil when no pending submission has id.
+func (u *SubmissionUsecase) Review(id int, action, note, reviewer string) (*domain.PriceSubmission, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	if action != ReviewApprove && action != ReviewReject {
+		return nil, fmt.Errorf("action must be %s or %s", ReviewApprove, ReviewReject)
+	}
+
+	submission, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if submission == nil || submission.Status != domain.SubmissionStatusPending {
+		return nil, nil
+	}
+
+	review := domain.SubmissionReview{
+		Status:     domain.SubmissionStatusRejected,
+		Note:       strings.TrimSpace(note),
+		ReviewedBy: reviewer,
+	}
+	if action == ReviewApprove {
+		review.Status = domain.SubmissionStatusApproved
+		review.SourceType = domain.PriceSourceCrowd
+		if submission.Contributor != nil {
+			review.SourceType = sourceTypeFor(*submission.Contributor, submission.StoreID)
+		}
+	}
+	return u.repo.Review(id, review)
+}
+
+func (u *SubmissionUsecase) ListContributors(opts Pagination) ([]domain.Contributor, error) {
+	return u.repo.FindContributors(normalizeLimit(opts.Limit), normalizeOffset(opts.Offset))
+}
+
+func (u *SubmissionUsecase) GetContributor(id int) (*domain.Contributor, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("id must be positive")
+	}
+	return u.repo.FindContributorByID(id)
+}
+
+// SetContributorRole makes a contributor a shopper, or the store manager
+// of storeID. Validation failures wrap domain.ErrInvalidContributorRole,
+// and it returns nil when no contributor has id or no store has storeID.
+func (u *SubmissionUsecase) SetContributorRole(id int, role string, storeID int) (*domain.Contributor, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: id must be positive", domain.ErrInvalidContributorRole)
+	}
+	switch role {
+	case domain.ContributorRoleShopper:
+		return u.repo.UpdateContributorRole(id, role, nil)
+	case domain.ContributorRoleStoreManager:
+		if storeID <= 0 {
+			return nil, fmt.Errorf("%w: store id is required for store managers", domain.ErrInvalidContributorRole)
+		}
+		return u.repo.UpdateContributorRole(id, role, &storeID)
+	default:
+		return nil, fmt.Errorf("%w: role must be %s or %s", domain.ErrInvalidContributorRole, domain.ContributorRoleShopper, domain.ContributorRoleStoreManager)
+	}
+}
+
+func isManagerOf(contributor domain.Contributor, storeID int) bool {
+	return contributor.Role == domain.ContributorRoleStoreManager &&
+		contributor.StoreID != nil && *contributor.StoreID == storeID
+}
+
+// sourceTypeFor is the provenance of a price submitted by contributor
+func sourceTypeFor(contributor domain.Contributor, storeID int) string {
+	if isManagerOf(contributor, storeID) {
+		return domain.PriceSourceStoreManager
+	}
+	return domain.PriceSourceCrowd
+}
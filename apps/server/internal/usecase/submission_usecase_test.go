@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type submissionRepoStub struct {
+	contributor domain.Contributor
+	created     []domain.PriceSubmission
+	reviews     []domain.SubmissionReview
+}
+
+func (s *submissionRepoStub) UpsertContributor(externalID, displayName string) (*domain.Contributor, error) {
+	contributor := s.contributor
+	contributor.ExternalID = externalID
+	return &contributor, nil
+}
+
+func (s *submissionRepoStub) FindContributors(limit, offset int) ([]domain.Contributor, error) {
+	return []domain.Contributor{}, nil
+}
+
+func (s *submissionRepoStub) FindContributorByID(id int) (*domain.Contributor, error) {
+	return nil, nil
+}
+
+func (s *submissionRepoStub) UpdateContributorRole(id int, role string, storeID *int) (*domain.Contributor, error) {
+	return &domain.Contributor{ID: id, Role: role, StoreID: storeID}, nil
+}
+
+func (s *submissionRepoStub) Create(submission domain.PriceSubmission) (*domain.PriceSubmission, error) {
+	submission.ID = len(s.created) + 1
+	submission.Status = domain.SubmissionStatusPending
+	contributor := s.contributor
+	submission.Contributor = &contributor
+	s.created = append(s.created, submission)
+	return &submission, nil
+}
+
+func (s *submissionRepoStub) FindAll(status string, storeID, contributorID int, limit, offset int) ([]domain.PriceSubmission, error) {
+	return []domain.PriceSubmission{}, nil
+}
+
+func (s *submissionRepoStub) FindByID(id int) (*domain.PriceSubmission, error) {
+	if id > len(s.created) {
+		return nil, nil
+	}
+	submission := s.created[id-1]
+	return &submission, nil
+}
+
+func (s *submissionRepoStub) Review(id int, review domain.SubmissionReview) (*domain.PriceSubmission, error) {
+	s.reviews = append(s.reviews, review)
+	submission := s.created[id-1]
+	submission.Status = review.Status
+	submission.AutoApproved = review.Auto
+	return &submission, nil
+}
+
+var testTrustPolicy = TrustPolicy{MinApproved: 10, MinReputation: 0.9}
+
+func TestSubmitLeavesNewcomersPending(t *testing.T) {
+	stub := &submissionRepoStub{contributor: domain.Contributor{ID: 7, Role: domain.ContributorRoleShopper, Reputation: 0.5}}
+	uc := NewSubmissionUsecase(stub, testTrustPolicy)
+
+	created, err := uc.Submit(" app-user-1 ", "", domain.PriceSubmission{StoreID: 1, ProductID: 2, Price: 198, TaxIncluded: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Status != domain.SubmissionStatusPending || len(stub.reviews) != 0 {
+		t.Fatalf("expected a pending submission, got %+v (reviews %+v)", created, stub.reviews)
+	}
+	if created.ContributorID != 7 || created.Currency != "JPY" {
+		t.Fatalf("expected contributor 7 and JPY, got %+v", created)
+	}
+}
+
+func TestSubmitAutoApprovesTrustedContributors(t *testing.T) {
+	storeID := 1
+	cases := []struct {
+		name        string
+		contributor domain.Contributor
+		storeID     int
+		source      string
+	}{
+		{"reputable shopper", domain.Contributor{ID: 1, Role: domain.ContributorRoleShopper, ApprovedCount: 12, Reputation: 0.93}, 3, domain.PriceSourceCrowd},
+		{"manager of the store", domain.Contributor{ID: 2, Role: domain.ContributorRoleStoreManager, StoreID: &storeID, Reputation: 0.5}, 1, domain.PriceSourceStoreManager},
+	}
+
+	for _, tc := range cases {
+		stub := &submissionRepoStub{contributor: tc.contributor}
+		uc := NewSubmissionUsecase(stub, testTrustPolicy)
+
+		created, err := uc.Submit("app-user", "", domain.PriceSubmission{StoreID: tc.storeID, ProductID: 2, Price: 198})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if created.Status != domain.SubmissionStatusApproved || !created.AutoApproved {
+			t.Fatalf("%s: expected auto-approval, got %+v", tc.name, created)
+		}
+		if stub.reviews[0].SourceType != tc.source {
+			t.Fatalf("%s: expected source %s, got %s", tc.name, tc.source, stub.reviews[0].SourceType)
+		}
+	}
+}
+
+func TestTrustPolicyRequiresVolumeAndReputation(t *testing.T) {
+	otherStore := 5
+	untrusted := []domain.Contributor{
+		{Role: domain.ContributorRoleShopper, ApprovedCount: 3, Reputation: 0.95},
+		{Role: domain.ContributorRoleShopper, ApprovedCount: 40, Reputation: 0.8},
+		{Role: domain.ContributorRoleStoreManager, StoreID: &otherStore, Reputation: 0.5},
+	}
+	for _, contributor := range untrusted {
+		if testTrustPolicy.Trusts(contributor, 1) {
+			t.Fatalf("expected %+v not to be trusted at store 1", contributor)
+		}
+	}
+}
+
+func TestSubmitValidates(t *testing.T) {
+	stub := &submissionRepoStub{contributor: domain.Contributor{ID: 1}}
+	uc := NewSubmissionUsecase(stub, testTrustPolicy)
+	valid := domain.PriceSubmission{StoreID: 1, ProductID: 2, Price: 198}
+
+	invalid := []domain.PriceSubmission{
+		{ProductID: 2, Price: 198},
+		{StoreID: 1, ProductID: 2},
+		{StoreID: 1, ProductID: 2, Price: 198, Currency: "JP"},
+		{StoreID: 1, ProductID: 2, Price: 198, PhotoURL: "file:///tmp/photo.jpg"},
+		{StoreID: 1, ProductID: 2, Price: 198, ObservedAt: time.Now().Add(time.Hour)},
+		{StoreID: 1, ProductID: 2, Price: 198, ObservedAt: time.Now().Add(-MaxSubmissionAge - time.Hour)},
+	}
+	for _, submission := range invalid {
+		if _, err := uc.Submit("app-user", "", submission); !errors.Is(err, domain.ErrInvalidSubmission) {
+			t.Fatalf("expected ErrInvalidSubmission for %+v, got %v", submission, err)
+		}
+	}
+	if _, err := uc.Submit("  ", "", valid); err == nil {
+		t.Fatalf("expected error without a contributor id")
+	}
+	if len(stub.created) != 0 {
+		t.Fatalf("expected nothing saved, got %d", len(stub.created))
+	}
+}
+
+func TestSubmitStoresObservedAtInUTC(t *testing.T) {
+	stub := &submissionRepoStub{contributor: domain.Contributor{ID: 1}}
+	uc := NewSubmissionUsecase(stub, testTrustPolicy)
+	observed := time.Now().Add(-time.Hour).In(time.FixedZone("JST", 9*3600))
+
+	if _, err := uc.Submit("app-user", "", domain.PriceSubmission{StoreID: 1, ProductID: 2, Price: 198, ObservedAt: observed}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	saved := stub.created[0].ObservedAt
+	if saved.Location() != time.UTC || !saved.Equal(observed) {
+		t.Fatalf("expected %s in UTC, got %s", observed, saved)
+	}
+}
+
+func TestSetContributorRoleValidates(t *testing.T) {
+	uc := NewSubmissionUsecase(&submissionRepoStub{}, testTrustPolicy)
+	for _, role := range []string{"admin", domain.ContributorRoleStoreManager} {
+		if _, err := uc.SetContributorRole(1, role, 0); !errors.Is(err, domain.ErrInvalidContributorRole) {
+			t.Fatalf("expected ErrInvalidContributorRole for %q, got %v", role, err)
+		}
+	}
+}
+
+func TestReviewSubmissionRecordsProvenance(t *testing.T) {
+	storeID := 1
+	stub := &submissionRepoStub{contributor: domain.Contributor{ID: 1, Role: domain.ContributorRoleStoreManager, StoreID: &storeID}}
+	uc := NewSubmissionUsecase(stub, testTrustPolicy)
+	stub.created = []domain.PriceSubmission{{ID: 1, StoreID: 2, Status: domain.SubmissionStatusPending, Contributor: &stub.contributor}}
+
+	reviewed, err := uc.Review(1, ReviewApprove, "  ok ", "hanako")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reviewed.Status != domain.SubmissionStatusApproved {
+		t.Fatalf("expected approval, got %+v", reviewed)
+	}
+	review := stub.reviews[0]
+	if review.SourceType != domain.PriceSourceCrowd || review.Auto || review.Note != "ok" || review.ReviewedBy != "hanako" {
+		t.Fatalf("expected a moderated crowd price, got %+v", review)
+	}
+
+	stub.created[0].Status = domain.SubmissionStatusApproved
+	if again, err := uc.Review(1, ReviewReject, "", "hanako"); err != nil || again != nil {
+		t.Fatalf("expected nil for a settled submission, got %+v, %v", again, err)
+	}
+}
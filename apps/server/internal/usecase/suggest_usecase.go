@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+const (
+	DefaultSuggestLimit = 8
+	MaxSuggestLimit     = 20
+)
+
+type SuggestionRepository interface {
+	FindByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+}
+
+type SuggestUsecase struct {
+	repo     SuggestionRepository
+	cache    Cache
+	cacheTTL time.Duration
+	timeout  time.Duration
+}
+
+func NewSuggestUsecase(repo SuggestionRepository, cache Cache, cacheTTL, timeout time.Duration) *SuggestUsecase {
+	return &SuggestUsecase{repo: repo, cache: cache, cacheTTL: cacheTTL, timeout: timeout}
+}
+
+// Suggest returns typeahead entries for a prefix. Results are cached per
+// normalized prefix; when the lookup exceeds the latency budget an empty
+// list is returned instead of an error so the search box stays responsive.
+func (u *SuggestUsecase) Suggest(opts SuggestOptions) ([]domain.Suggestion, error) {
+	prefix := normalizeSearchText(opts.Query)
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: query is required", domain.ErrInvalidSearch)
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit {
+		limit = MaxSuggestLimit
+	}
+
+	ctx := context.Background()
+	if u.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.timeout)
+		defer cancel()
+	}
+
+	cacheKey := fmt.Sprintf("suggest:%s:%d", prefix, limit)
+	if u.cache != nil {
+		if cached, err := u.cache.Get(ctx, cacheKey); err == nil {
+			var suggestions []domain.Suggestion
+			if err := json.Unmarshal([]byte(cached), &suggestions); err == nil {
+				return suggestions, nil
+			}
+		}
+	}
+
+	suggestions, err := u.repo.FindByPrefix(ctx, prefix, limit)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return []domain.Suggestion{}, nil
+		}
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []domain.Suggestion{}
+	}
+
+	if u.cache != nil {
+		if payload, err := json.Marshal(suggestions); err == nil {
+			_ = u.cache.Set(context.Background(), cacheKey, string(payload), u.cacheTTL)
+		}
+	}
+
+	return suggestions, nil
+}
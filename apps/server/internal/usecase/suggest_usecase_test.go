@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/domain"
+)
+
+type suggestionRepoStub struct {
+	lastPrefix string
+	lastLimit  int
+	block      bool
+}
+
+func (s *suggestionRepoStub) FindByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	s.lastPrefix = prefix
+	s.lastLimit = limit
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return []domain.Suggestion{{Type: "product", Text: "牛乳 1L"}}, nil
+}
+
+func TestSuggestNormalizesPrefixAndCapsLimit(t *testing.T) {
+	stub := &suggestionRepoStub{}
+	uc := NewSuggestUsecase(stub, nil, 0, 0)
+
+	if _, err := uc.Suggest(SuggestOptions{Query: "ギュウ", Limit: 500}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.lastPrefix != "ぎゅう" {
+		t.Fatalf("expected normalized prefix, got %q", stub.lastPrefix)
+	}
+	if stub.lastLimit != MaxSuggestLimit {
+		t.Fatalf("expected limit %d, got %d", MaxSuggestLimit, stub.lastLimit)
+	}
+}
+
+func TestSuggestReturnsEmptyWhenOverBudget(t *testing.T) {
+	stub := &suggestionRepoStub{block: true}
+	uc := NewSuggestUsecase(stub, nil, 0, 5*time.Millisecond)
+
+	suggestions, err := uc.Suggest(SuggestOptions{Query: "ぎゅう"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got %v", suggestions)
+	}
+}
+
+func TestSuggestRejectsBlankQuery(t *testing.T) {
+	stub := &suggestionRepoStub{}
+	uc := NewSuggestUsecase(stub, nil, 0, 0)
+
+	if _, err := uc.Suggest(SuggestOptions{Query: " 　"}); !errors.Is(err, domain.ErrInvalidSearch) {
+		t.Fatalf("expected ErrInvalidSearch, got %v", err)
+	}
+}
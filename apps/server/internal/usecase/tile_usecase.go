@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
+// MaxTileZoom is the deepest zoom level tiles are served for
+const MaxTileZoom = 22
+
+type TileRepository interface {
+	StoreTile(tile query.TileCoord, filters query.StoreFilters) ([]byte, error)
+}
+
+type TileUsecase struct {
+	repo     TileRepository
+	cache    Cache
+	cacheTTL time.Duration
+}
+
+func NewTileUsecase(repo TileRepository, cache Cache, cacheTTL time.Duration) *TileUsecase {
+	return &TileUsecase{repo: repo, cache: cache, cacheTTL: cacheTTL}
+}
+
+// ValidTile reports whether z/x/y addresses an existing tile
+func ValidTile(tile query.TileCoord) bool {
+	if tile.Z < 0 || tile.Z > MaxTileZoom {
+		return false
+	}
+	size := 1 << tile.Z
+	return tile.X >= 0 && tile.X < size && tile.Y >= 0 && tile.Y < size
+}
+
+// StoreTile returns the encoded vector tile of stores, served from the cache
+// when possible. Tiles are cached as raw bytes.
+func (u *TileUsecase) StoreTile(opts StoreTileOptions) ([]byte, error) {
+	if !ValidTile(opts.Tile) {
+		return nil, fmt.Errorf("tile %d/%d/%d is out of range", opts.Tile.Z, opts.Tile.X, opts.Tile.Y)
+	}
+	if opts.MinPrice != nil && opts.MaxPrice != nil && *opts.MinPrice > *opts.MaxPrice {
+		return nil, fmt.Errorf("min price must not exceed max price")
+	}
+	tax, err := normalizeTaxMode(opts.Tax)
+	if err != nil {
+		return nil, err
+	}
+	minConfidence, err := normalizeMinConfidence(opts.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := query.StoreFilters{
+		Query:         opts.Query,
+		Categories:    normalizeStringSet(opts.Categories),
+		MinPrice:      opts.MinPrice,
+		MaxPrice:      opts.MaxPrice,
+		ChainIDs:      normalizeIDSet(opts.ChainIDs),
+		Tax:           tax,
+		MinConfidence: minConfidence,
+	}
+
+	cacheKey := fmt.Sprintf("tiles:stores:%d:%d:%d:%s:%s:%s:%s:%s:%s:%g",
+		opts.Tile.Z,
+		opts.Tile.X,
+		opts.Tile.Y,
+		filters.Query,
+		strings.Join(filters.Categories, ","),
+		formatOptionalFloat(filters.MinPrice),
+		formatOptionalFloat(filters.MaxPrice),
+		joinIDs(filters.ChainIDs),
+		filters.Tax,
+		filters.MinConfidence,
+	)
+	if u.cache != nil {
+		if cached, err := u.cache.Get(context.Background(), cacheKey); err == nil {
+			return []byte(cached), nil
+		}
+	}
+
+	tile, err := u.repo.StoreTile(opts.Tile, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil {
+		_ = u.cache.Set(context.Background(), cacheKey, string(tile), u.cacheTTL)
+	}
+
+	return tile, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/price-comparison/server/internal/query"
+)
+
+type tileRepoStub struct {
+	calls       int
+	lastFilters query.StoreFilters
+}
+
+func (s *tileRepoStub) StoreTile(tile query.TileCoord, filters query.StoreFilters) ([]byte, error) {
+	s.calls++
+	s.lastFilters = filters
+	return []byte{0x1a, 0x00, 0xff}, nil
+}
+
+type memoryCache struct {
+	values map[string]string
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return "", errors.New("miss")
+	}
+	return value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.values[key] = value
+	return nil
+}
+
+func TestValidTile(t *testing.T) {
+	cases := []struct {
+		tile  query.TileCoord
+		valid bool
+	}{
+		{query.TileCoord{Z: 0, X: 0, Y: 0}, true},
+		{query.TileCoord{Z: 3, X: 7, Y: 7}, true},
+		{query.TileCoord{Z: 3, X: 8, Y: 0}, false},
+		{query.TileCoord{Z: -1, X: 0, Y: 0}, false},
+		{query.TileCoord{Z: MaxTileZoom + 1, X: 0, Y: 0}, false},
+	}
+	for _, tc := range cases {
+		if got := ValidTile(tc.tile); got != tc.valid {
+			t.Errorf("ValidTile(%+v) = %v, want %v", tc.tile, got, tc.valid)
+		}
+	}
+}
+
+func TestStoreTileCachesBytes(t *testing.T) {
+	stub := &tileRepoStub{}
+	uc := NewTileUsecase(stub, &memoryCache{values: map[string]string{}}, time.Minute)
+	opts := StoreTileOptions{Tile: query.TileCoord{Z: 14, X: 14552, Y: 6451}, Categories: []string{"飲料", "飲料"}}
+
+	first, err := uc.StoreTile(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uc.StoreTile(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stub.calls != 1 {
+		t.Fatalf("expected one repository call, got %d", stub.calls)
+	}
+	if string(first) != string(second) || len(second) != 3 {
+		t.Fatalf("expected cached tile bytes to round-trip, got %v", second)
+	}
+	if len(stub.lastFilters.Categories) != 1 {
+		t.Fatalf("expected categories to be deduplicated, got %v", stub.lastFilters.Categories)
+	}
+}
+
+func TestStoreTileRejectsOutOfRange(t *testing.T) {
+	uc := NewTileUsecase(&tileRepoStub{}, nil, 0)
+
+	if _, err := uc.StoreTile(StoreTileOptions{Tile: query.TileCoord{Z: 1, X: 2, Y: 0}}); err == nil {
+		t.Fatalf("expected error for out-of-range tile")
+	}
+}
@@ -1,6 +1,11 @@
 package usecase
 
-import "github.com/price-comparison/server/internal/query"
+import (
+	"time"
+
+	"github.com/price-comparison/server/internal/query"
+	"github.com/price-comparison/server/internal/routing"
+)
 
 const (
 	DefaultLimit = 20
@@ -20,16 +25,40 @@ type Sort struct {
 type StoreListOptions struct {
 	Pagination
 	Sort
-	Query        string
-	Category     string
-	Bounds       *query.Bounds
-	UserLocation *query.GeoPoint
+	Query              string
+	Categories         []string
+	MinPrice           *float64
+	MaxPrice           *float64
+	ProductIDs         []int
+	ChainIDs           []int
+	RecordedWithinDays int
+	Currency           string
+	Tax                query.TaxMode
+	MinConfidence      float64
+	Quantity           int
+	Member             bool
+	OpenAt             *time.Time
+	Bounds             *query.Bounds
+	AreaGeoJSON        string
+	AreaID             int
+	UserLocation       *query.GeoPoint
+}
+
+// StoreClusterOptions reuses the store list filters; Bounds is required
+type StoreClusterOptions struct {
+	StoreListOptions
+	Zoom int
 }
 
 type StoreNearbyOptions struct {
 	Latitude  float64
 	Longitude float64
 	Radius    int
+	OpenAt    *time.Time
+	// TravelMode switches from straight-line radius to stores reachable
+	// within TravelMinutes over the road network
+	TravelMode    routing.Mode
+	TravelMinutes int
 	Pagination
 }
 
@@ -39,13 +68,24 @@ type ProductListOptions struct {
 }
 
 type ProductSearchOptions struct {
-	Keyword string
+	Keyword  string
+	Category string
+	MinPrice *float64
+	MaxPrice *float64
 	Pagination
 	Sort
 }
 
 type PriceListOptions struct {
 	ProductID int
+	Currency  string
+	Tax       query.TaxMode
+	// MinConfidence leaves out prices with a lower confidence
+	MinConfidence float64
+	// Quantity and Member select the promotions applied to the effective
+	// price; Quantity defaults to 1
+	Quantity int
+	Member   bool
 	Pagination
 	Sort
 }
@@ -53,6 +93,10 @@ type PriceListOptions struct {
 type StorePriceListOptions struct {
 	StoreID  int
 	Category string
+	Currency string
+	Tax      query.TaxMode
+	Quantity int
+	Member   bool
 	Pagination
 	Sort
 }
@@ -61,5 +105,122 @@ type StorePriceStatsOptions struct {
 	StoreID  int
 	Category string
 	Query    string
+	Currency string
+	Tax      query.TaxMode
 	Days     int
 }
+
+type SuggestOptions struct {
+	Query string
+	Limit int
+}
+
+type ChainListOptions struct {
+	Pagination
+}
+
+type ChainPriceStatsOptions struct {
+	ChainID  int
+	Category string
+	Query    string
+	Currency string
+	Tax      query.TaxMode
+	Days     int
+}
+
+type AreaListOptions struct {
+	Pagination
+}
+
+type AreaCreateOptions struct {
+	Name    string
+	GeoJSON string
+}
+
+type StoreTileOptions struct {
+	Tile          query.TileCoord
+	Query         string
+	Categories    []string
+	MinPrice      *float64
+	MaxPrice      *float64
+	ChainIDs      []int
+	Tax           query.TaxMode
+	MinConfidence float64
+}
+
+type GeocodeOptions struct {
+	Query string
+	Limit int
+}
+
+type ReverseGeocodeOptions struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type ExchangeRateListOptions struct {
+	Pagination
+	Base  string
+	Quote string
+}
+
+type ConvertOptions struct {
+	Amount float64
+	From   string
+	To     string
+	Date   *time.Time // Defaults to today
+}
+
+type PromotionListOptions struct {
+	StoreID    int
+	ProductID  int
+	ActiveOnly bool
+	Tax        query.TaxMode
+	Pagination
+}
+
+type AnomalyListOptions struct {
+	Status    string // quarantined (default) or rejected
+	ProductID int
+	Pagination
+}
+
+type SubmissionListOptions struct {
+	Status        string // pending (default), approved or rejected
+	StoreID       int
+	ContributorID int
+	Pagination
+}
+
+type ConnectorRunListOptions struct {
+	Connector string
+	Pagination
+}
+
+type JobListOptions struct {
+	Status string // queued, running, succeeded or failed; all when empty
+	Type   string
+	Pagination
+}
+
+// PriceIndexOptions selects a price index. From and To are dates (To
+// defaults to today, From to DefaultPriceIndexDays before To); Base is a
+// date in the period the index is 100 in.
+type PriceIndexOptions struct {
+	Category string
+	AreaID   int
+	Period   string
+	From     time.Time
+	To       time.Time
+	Base     time.Time
+	Tax      query.TaxMode
+}
+
+// PriceForecastOptions selects the series a price forecast is fitted to:
+// a product's prices at a store over the last HistoryDays days
+type PriceForecastOptions struct {
+	StoreID     int
+	ProductID   int
+	HistoryDays int
+	Tax         query.TaxMode
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_search_name_trgm;
+ALTER TABLE products DROP COLUMN IF EXISTS search_name;
+DROP FUNCTION IF EXISTS search_normalize(TEXT);
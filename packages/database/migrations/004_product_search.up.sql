@@ -0,0 +1,14 @@
+-- Japanese-aware search normalization: NFKC folds full-width ASCII and
+-- half-width katakana, then katakana is folded to hiragana.
+CREATE OR REPLACE FUNCTION search_normalize(input TEXT) RETURNS TEXT AS $$
+    SELECT translate(
+        lower(normalize(input, NFKC)),
+        'ァアィイゥウェエォオカガキギクグケゲコゴサザシジスズセゼソゾタダチヂッツヅテデトドナニヌネノハバパヒビピフブプヘベペホボポマミムメモャヤュユョヨラリルレロヮワヰヱヲンヴヵヶ',
+        'ぁあぃいぅうぇえぉおかがきぎくぐけげこごさざしじすずせぜそぞただちぢっつづてでとどなにぬねのはばぱひびぴふぶぷへべぺほぼぽまみむめもゃやゅゆょよらりるれろゎわゐゑをんゔゕゖ'
+    )
+$$ LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS search_name TEXT GENERATED ALWAYS AS (search_normalize(name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_name_trgm ON products USING GIN (search_name gin_trgm_ops);
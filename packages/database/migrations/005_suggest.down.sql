@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_search_name_prefix;
+DROP INDEX IF EXISTS idx_stores_search_name_prefix;
+DROP INDEX IF EXISTS idx_stores_search_name_trgm;
+ALTER TABLE stores DROP COLUMN IF EXISTS search_name;
@@ -0,0 +1,8 @@
+ALTER TABLE stores
+    ADD COLUMN IF NOT EXISTS search_name TEXT GENERATED ALWAYS AS (search_normalize(name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_stores_search_name_trgm ON stores USING GIN (search_name gin_trgm_ops);
+
+-- Prefix lookups for typeahead (LIKE 'abc%') use btree pattern indexes
+CREATE INDEX IF NOT EXISTS idx_stores_search_name_prefix ON stores (search_name text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_products_search_name_prefix ON products (search_name text_pattern_ops);
@@ -0,0 +1,4 @@
+DROP FUNCTION IF EXISTS store_is_open(INTEGER, TIMESTAMPTZ);
+DROP TABLE IF EXISTS store_hours_exceptions;
+DROP TABLE IF EXISTS store_opening_hours;
+ALTER TABLE stores DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,80 @@
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo';
+
+-- Weekly schedule; weekday follows EXTRACT(DOW): 0 = Sunday ... 6 = Saturday.
+-- closes_at <= opens_at means the period runs past midnight (00:00-00:00 is 24h).
+CREATE TABLE IF NOT EXISTS store_opening_hours (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL
+);
+
+-- Date-specific overrides (holidays, special hours). A closed row, or any set
+-- of rows for a date, replaces the weekly schedule for that local date.
+CREATE TABLE IF NOT EXISTS store_hours_exceptions (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    closed BOOLEAN NOT NULL DEFAULT FALSE,
+    opens_at TIME,
+    closes_at TIME,
+    note TEXT,
+    CHECK (closed OR (opens_at IS NOT NULL AND closes_at IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_opening_hours_store ON store_opening_hours(store_id, weekday);
+CREATE INDEX IF NOT EXISTS idx_store_hours_exceptions_store_date ON store_hours_exceptions(store_id, date);
+
+-- store_is_open evaluates a store's schedule at an instant in the store's
+-- own timezone, including periods carried over from the previous day.
+CREATE OR REPLACE FUNCTION store_is_open(target_store_id INTEGER, at_time TIMESTAMPTZ) RETURNS BOOLEAN AS $$
+DECLARE
+    tz TEXT;
+    local_ts TIMESTAMP;
+    local_date DATE;
+    local_time TIME;
+BEGIN
+    SELECT timezone INTO tz FROM stores WHERE id = target_store_id;
+    IF tz IS NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    local_ts := at_time AT TIME ZONE tz;
+    local_date := local_ts::date;
+    local_time := local_ts::time;
+
+    -- Periods starting today
+    IF EXISTS (SELECT 1 FROM store_hours_exceptions WHERE store_id = target_store_id AND date = local_date) THEN
+        IF EXISTS (
+            SELECT 1 FROM store_hours_exceptions
+            WHERE store_id = target_store_id AND date = local_date AND NOT closed
+              AND local_time >= opens_at
+              AND (closes_at <= opens_at OR local_time < closes_at)
+        ) THEN
+            RETURN TRUE;
+        END IF;
+    ELSIF EXISTS (
+        SELECT 1 FROM store_opening_hours
+        WHERE store_id = target_store_id AND weekday = EXTRACT(DOW FROM local_date)
+          AND local_time >= opens_at
+          AND (closes_at <= opens_at OR local_time < closes_at)
+    ) THEN
+        RETURN TRUE;
+    END IF;
+
+    -- Overnight periods that started yesterday
+    IF EXISTS (SELECT 1 FROM store_hours_exceptions WHERE store_id = target_store_id AND date = local_date - 1) THEN
+        RETURN EXISTS (
+            SELECT 1 FROM store_hours_exceptions
+            WHERE store_id = target_store_id AND date = local_date - 1 AND NOT closed
+              AND closes_at <= opens_at AND local_time < closes_at
+        );
+    END IF;
+    RETURN EXISTS (
+        SELECT 1 FROM store_opening_hours
+        WHERE store_id = target_store_id AND weekday = EXTRACT(DOW FROM local_date - 1)
+          AND closes_at <= opens_at AND local_time < closes_at
+    );
+END;
+$$ LANGUAGE plpgsql STABLE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_stores_chain_id;
+ALTER TABLE stores DROP COLUMN IF EXISTS chain_id;
+DROP TABLE IF EXISTS chains;
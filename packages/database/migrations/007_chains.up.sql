@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS chains (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    slug VARCHAR(100) NOT NULL UNIQUE CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    logo_url TEXT,
+    website_url TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS chain_id INTEGER REFERENCES chains(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_stores_chain_id ON stores(chain_id);
+
+-- Chains present in the bundled datasets; stores are linked by name prefix,
+-- ignoring hyphen spelling variants such as セブンイレブン / セブン-イレブン.
+INSERT INTO chains (name, slug, website_url) VALUES
+('セブン-イレブン', 'seven-eleven', 'https://www.sej.co.jp/'),
+('ファミリーマート', 'familymart', 'https://www.family.co.jp/'),
+('ローソン', 'lawson', 'https://www.lawson.co.jp/'),
+('ミニストップ', 'ministop', 'https://www.ministop.co.jp/'),
+('デイリーヤマザキ', 'daily-yamazaki', 'https://www.daily-yamazaki.jp/'),
+('イオン', 'aeon', 'https://www.aeon.com/'),
+('マルエツ', 'maruetsu', 'https://www.maruetsu.co.jp/'),
+('西友', 'seiyu', 'https://www.seiyu.co.jp/'),
+('ライフ', 'life', 'https://www.lifecorp.jp/'),
+('サミット', 'summit', 'https://www.summitstore.co.jp/')
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE stores s
+SET chain_id = c.id
+FROM chains c
+WHERE s.chain_id IS NULL
+  AND replace(s.name, '-', '') LIKE replace(c.name, '-', '') || '%';
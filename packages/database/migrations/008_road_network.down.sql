@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS road_edges;
+DROP TABLE IF EXISTS road_nodes;
@@ -0,0 +1,34 @@
+-- Routable road network for travel-time search. Load it from an OSM extract,
+-- e.g. with osm2pgrouting into its default tables, then:
+--
+--   INSERT INTO road_nodes (id, location)
+--   SELECT id, the_geom::geography FROM ways_vertices_pgr;
+--
+--   INSERT INTO road_edges (source, target, length_m, max_speed_kmh, oneway,
+--                           allows_walking, allows_cycling, allows_driving)
+--   SELECT w.source, w.target, w.length_m, NULLIF(w.maxspeed_forward, 0), w.one_way = 1,
+--          c.tag_value NOT IN ('motorway', 'motorway_link', 'trunk', 'trunk_link'),
+--          c.tag_value NOT IN ('motorway', 'motorway_link', 'steps'),
+--          c.tag_value NOT IN ('footway', 'pedestrian', 'path', 'steps', 'cycleway')
+--   FROM ways w
+--   JOIN configuration c ON c.tag_id = w.tag_id;
+CREATE TABLE IF NOT EXISTS road_nodes (
+    id BIGINT PRIMARY KEY,
+    location GEOGRAPHY(POINT, 4326) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS road_edges (
+    id BIGSERIAL PRIMARY KEY,
+    source BIGINT NOT NULL REFERENCES road_nodes(id) ON DELETE CASCADE,
+    target BIGINT NOT NULL REFERENCES road_nodes(id) ON DELETE CASCADE,
+    length_m DOUBLE PRECISION NOT NULL CHECK (length_m >= 0),
+    max_speed_kmh DOUBLE PRECISION CHECK (max_speed_kmh > 0),
+    oneway BOOLEAN NOT NULL DEFAULT FALSE,
+    allows_walking BOOLEAN NOT NULL DEFAULT TRUE,
+    allows_cycling BOOLEAN NOT NULL DEFAULT TRUE,
+    allows_driving BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_road_nodes_location ON road_nodes USING GIST(location);
+CREATE INDEX IF NOT EXISTS idx_road_edges_source ON road_edges(source);
+CREATE INDEX IF NOT EXISTS idx_road_edges_target ON road_edges(target);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stores_location_geom;
+DROP TABLE IF EXISTS areas;
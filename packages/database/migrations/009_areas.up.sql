@@ -0,0 +1,12 @@
+-- Saved search areas such as wards or delivery zones
+CREATE TABLE IF NOT EXISTS areas (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    geom GEOMETRY(MULTIPOLYGON, 4326) NOT NULL CHECK (ST_IsValid(geom)),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_areas_geom ON areas USING GIST(geom);
+
+-- Point-in-polygon filters cast stores.location to geometry
+CREATE INDEX IF NOT EXISTS idx_stores_location_geom ON stores USING GIST((location::geometry));
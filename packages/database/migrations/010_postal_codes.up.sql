@@ -0,0 +1,32 @@
+-- Offline geocoding dataset: one row per Japanese postal code and town with a
+-- representative point. Build it by joining Japan Post's KEN_ALL.csv (UTF-8
+-- edition) with the MLIT 位置参照情報 (大字・町丁目レベル) town centroids on
+-- prefecture/city/town, e.g. after loading both into staging tables:
+--
+--   INSERT INTO postal_codes (postal_code, prefecture, city, town,
+--                             prefecture_kana, city_kana, town_kana, location)
+--   SELECT k.postal_code, k.prefecture, k.city,
+--          CASE WHEN k.town = '以下に掲載がない場合' THEN '' ELSE k.town END,
+--          k.prefecture_kana, k.city_kana, k.town_kana,
+--          ST_SetSRID(ST_MakePoint(AVG(m.lon), AVG(m.lat)), 4326)::geography
+--   FROM ken_all k
+--   JOIN mlit_towns m ON m.prefecture = k.prefecture AND m.city = k.city
+--                    AND (k.town = '以下に掲載がない場合' OR m.town LIKE k.town || '%')
+--   GROUP BY 1, 2, 3, 4, 5, 6, 7;
+CREATE TABLE IF NOT EXISTS postal_codes (
+    id SERIAL PRIMARY KEY,
+    postal_code CHAR(7) NOT NULL CHECK (postal_code ~ '^[0-9]{7}$'),
+    prefecture VARCHAR(10) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    town VARCHAR(200) NOT NULL DEFAULT '',
+    prefecture_kana VARCHAR(50),
+    city_kana VARCHAR(200),
+    town_kana VARCHAR(400),
+    location GEOGRAPHY(POINT, 4326) NOT NULL,
+    search_address TEXT GENERATED ALWAYS AS (search_normalize(prefecture || city || town)) STORED,
+    UNIQUE (postal_code, prefecture, city, town)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postal_codes_code ON postal_codes (postal_code);
+CREATE INDEX IF NOT EXISTS idx_postal_codes_location ON postal_codes USING GIST (location);
+CREATE INDEX IF NOT EXISTS idx_postal_codes_search_trgm ON postal_codes USING GIN (search_address gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS convert_price(NUMERIC, TEXT, TEXT, DATE);
+DROP TABLE IF EXISTS exchange_rates;
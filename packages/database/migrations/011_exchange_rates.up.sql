@@ -0,0 +1,35 @@
+-- Dated exchange rates: 1 unit of base_currency = rate units of quote_currency
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency VARCHAR(3) NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency VARCHAR(3) NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency, rate_date),
+    CHECK (base_currency <> quote_currency)
+);
+
+-- convert_price converts amount using the latest rate published on or before
+-- on_date, in either direction of the pair. Returns NULL when no rate exists
+-- so callers can refuse to mix unconverted amounts.
+CREATE OR REPLACE FUNCTION convert_price(amount NUMERIC, from_currency TEXT, to_currency TEXT, on_date DATE)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE
+        WHEN from_currency = to_currency THEN amount
+        ELSE (
+            SELECT CASE WHEN r.base_currency = from_currency THEN amount * r.rate ELSE amount / r.rate END
+            FROM exchange_rates r
+            WHERE r.rate_date <= on_date
+              AND (
+                  (r.base_currency = from_currency AND r.quote_currency = to_currency)
+                  OR (r.base_currency = to_currency AND r.quote_currency = from_currency)
+              )
+            ORDER BY r.rate_date DESC, (r.base_currency = from_currency) DESC
+            LIMIT 1
+        )
+    END
+$$;
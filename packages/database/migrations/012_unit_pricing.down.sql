@@ -0,0 +1,6 @@
+DROP FUNCTION IF EXISTS unit_price_basis(TEXT);
+DROP FUNCTION IF EXISTS unit_price(NUMERIC, NUMERIC, TEXT);
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_package_complete;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS package_unit,
+    DROP COLUMN IF EXISTS package_size;
@@ -0,0 +1,54 @@
+-- Package size for unit-price comparison. Mass and volume are normalized to
+-- price per 100g and per litre; counted goods (個/本/枚) to price per piece.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS package_size NUMERIC(12, 3) CHECK (package_size > 0),
+    ADD COLUMN IF NOT EXISTS package_unit VARCHAR(10) CHECK (package_unit IN ('g', 'kg', 'ml', 'l', 'piece'));
+
+ALTER TABLE products
+    ADD CONSTRAINT products_package_complete CHECK ((package_size IS NULL) = (package_unit IS NULL));
+
+CREATE OR REPLACE FUNCTION unit_price(price NUMERIC, package_size NUMERIC, package_unit TEXT)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE package_unit
+        WHEN 'g' THEN price * 100 / package_size
+        WHEN 'kg' THEN price / (package_size * 10)
+        WHEN 'ml' THEN price * 1000 / package_size
+        WHEN 'l' THEN price / package_size
+        WHEN 'piece' THEN price / package_size
+    END
+$$;
+
+CREATE OR REPLACE FUNCTION unit_price_basis(package_unit TEXT)
+RETURNS TEXT
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE package_unit
+        WHEN 'g' THEN '100g'
+        WHEN 'kg' THEN '100g'
+        WHEN 'ml' THEN '1l'
+        WHEN 'l' THEN '1l'
+        WHEN 'piece' THEN 'piece'
+    END
+$$;
+
+-- Best-effort backfill from sizes written in product names ("牛乳 1L", "卵 10個入り")
+WITH parsed AS (
+    SELECT id, regexp_match(normalize(name, NFKC), '([0-9]+(?:\.[0-9]+)?)\s*(kg|g|ml|l|個|本|枚)', 'i') AS m
+    FROM products
+    WHERE package_size IS NULL
+)
+UPDATE products p
+SET package_size = parsed.m[1]::numeric,
+    package_unit = CASE lower(parsed.m[2])
+        WHEN 'kg' THEN 'kg'
+        WHEN 'g' THEN 'g'
+        WHEN 'ml' THEN 'ml'
+        WHEN 'l' THEN 'l'
+        ELSE 'piece'
+    END
+FROM parsed
+WHERE parsed.id = p.id AND parsed.m IS NOT NULL AND parsed.m[1]::numeric > 0;
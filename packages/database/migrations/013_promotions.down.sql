@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS effective_price(NUMERIC, TEXT, INTEGER, INTEGER, INTEGER, BOOLEAN);
+DROP FUNCTION IF EXISTS promotion_total(NUMERIC, NUMERIC, INTEGER, NUMERIC, INTEGER);
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,68 @@
+-- Promotions override the regular (recorded) price of a product at a store
+-- for a validity window: a per-unit sale price, a multi-buy bundle
+-- ("2 for 300"), or both. Member-only promotions apply to loyalty members.
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sale_price DECIMAL(10, 2) CHECK (sale_price >= 0),
+    bundle_quantity INTEGER CHECK (bundle_quantity >= 2),
+    bundle_price DECIMAL(10, 2) CHECK (bundle_price >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'JPY' CHECK (currency ~ '^[A-Z]{3}$'),
+    member_only BOOLEAN NOT NULL DEFAULT false,
+    description TEXT,
+    valid_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    valid_to TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT promotions_bundle_complete CHECK ((bundle_quantity IS NULL) = (bundle_price IS NULL)),
+    CONSTRAINT promotions_has_offer CHECK (sale_price IS NOT NULL OR bundle_quantity IS NOT NULL),
+    CONSTRAINT promotions_valid_window CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_store_product ON promotions(store_id, product_id, valid_from);
+
+-- Total cost of quantity units under one promotion: as many full bundles as
+-- fit at bundle_price, the rest at the sale price (or the regular price when
+-- the promotion has no sale price)
+CREATE OR REPLACE FUNCTION promotion_total(
+    regular NUMERIC,
+    sale_price NUMERIC,
+    bundle_quantity INTEGER,
+    bundle_price NUMERIC,
+    quantity INTEGER
+)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE
+        WHEN bundle_quantity IS NULL THEN quantity * LEAST(COALESCE(sale_price, regular), regular)
+        ELSE (quantity / bundle_quantity) * bundle_price
+            + (quantity % bundle_quantity) * LEAST(COALESCE(sale_price, regular), regular)
+    END
+$$;
+
+-- Per-unit price paid when buying quantity units of a product at a store
+-- now: the cheapest of the regular price and every active promotion in the
+-- same currency. Member-only promotions count only for members.
+CREATE OR REPLACE FUNCTION effective_price(
+    regular NUMERIC,
+    price_currency TEXT,
+    p_store_id INTEGER,
+    p_product_id INTEGER,
+    quantity INTEGER,
+    member BOOLEAN
+)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT LEAST(regular, MIN(promotion_total(regular, pm.sale_price, pm.bundle_quantity, pm.bundle_price, quantity) / quantity))
+    FROM promotions pm
+    WHERE pm.store_id = p_store_id
+      AND pm.product_id = p_product_id
+      AND pm.currency = price_currency
+      AND pm.valid_from <= CURRENT_TIMESTAMP
+      AND (pm.valid_to IS NULL OR pm.valid_to > CURRENT_TIMESTAMP)
+      AND (member OR NOT pm.member_only)
+$$;
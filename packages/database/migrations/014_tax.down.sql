@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS prices_normalize_tax ON prices;
+DROP FUNCTION IF EXISTS normalize_price_tax();
+
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_included,
+    DROP COLUMN IF EXISTS posted_price;
+
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class;
+
+DROP FUNCTION IF EXISTS exclude_tax(NUMERIC, NUMERIC);
+DROP FUNCTION IF EXISTS tax_rate_on(TEXT, DATE);
+DROP TABLE IF EXISTS tax_rates;
@@ -0,0 +1,106 @@
+-- Japanese consumption tax. Products carry a tax class: food and
+-- non-alcoholic drinks use the reduced rate, everything else the standard
+-- rate. Rates change over time, so they are kept with an effective date.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    tax_class VARCHAR(20) NOT NULL,
+    valid_from DATE NOT NULL,
+    rate NUMERIC(5, 4) NOT NULL CHECK (rate >= 0 AND rate < 1),
+    PRIMARY KEY (tax_class, valid_from)
+);
+
+INSERT INTO tax_rates (tax_class, valid_from, rate) VALUES
+('standard', '1989-04-01', 0.03),
+('reduced', '1989-04-01', 0.03),
+('standard', '1997-04-01', 0.05),
+('reduced', '1997-04-01', 0.05),
+('standard', '2014-04-01', 0.08),
+('reduced', '2014-04-01', 0.08),
+('standard', '2019-10-01', 0.10),
+('reduced', '2019-10-01', 0.08)
+ON CONFLICT (tax_class, valid_from) DO NOTHING;
+
+-- Rate in effect for a tax class on a date; NULL before the first rate
+CREATE OR REPLACE FUNCTION tax_rate_on(p_tax_class TEXT, on_date DATE)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT rate
+    FROM tax_rates
+    WHERE tax_class = p_tax_class AND valid_from <= on_date
+    ORDER BY valid_from DESC
+    LIMIT 1
+$$;
+
+-- Tax-exclusive amount of a tax-inclusive amount
+CREATE OR REPLACE FUNCTION exclude_tax(amount NUMERIC, tax_rate NUMERIC)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT ROUND(amount / (1 + tax_rate), 2)
+$$;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS tax_class VARCHAR(20) NOT NULL DEFAULT 'standard'
+        CHECK (tax_class IN ('standard', 'reduced'));
+
+-- Food and drinks are reduced-rate unless alcoholic (料理酒 and 本みりん
+-- count as alcohol); baby formula and baby food are food
+UPDATE products
+SET tax_class = 'reduced'
+WHERE (
+        category IN ('飲料', '食品', '乳製品', 'パン', '生鮮食品', '調味料', 'スナック', '冷凍食品')
+        AND name !~ '(酒|ビール|ワイン|本みりん)'
+    )
+    OR (category = 'ベビー' AND name ~ '(ミルク|ベビーフード)');
+
+-- prices.price is normalized to the tax-inclusive amount. posted_price and
+-- tax_included record what the source showed (税込 or 税抜), tax_rate the
+-- rate used to normalize it.
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS posted_price DECIMAL(10, 2) CHECK (posted_price >= 0),
+    ADD COLUMN IF NOT EXISTS tax_included BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS tax_rate NUMERIC(5, 4) CHECK (tax_rate >= 0 AND tax_rate < 1);
+
+UPDATE prices p
+SET posted_price = p.price,
+    tax_rate = tax_rate_on(pr.tax_class, p.recorded_at::date)
+FROM products pr
+WHERE pr.id = p.product_id AND p.posted_price IS NULL;
+
+ALTER TABLE prices
+    ALTER COLUMN posted_price SET NOT NULL,
+    ALTER COLUMN tax_rate SET NOT NULL;
+
+-- Writers insert the posted amount as price (or posted_price) with
+-- tax_included = false for 税抜 prices; tax_rate defaults to the product's
+-- rate on recorded_at. Updating price alone re-posts it.
+CREATE OR REPLACE FUNCTION normalize_price_tax()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.posted_price := COALESCE(NEW.posted_price, NEW.price);
+    ELSIF NEW.price IS DISTINCT FROM OLD.price AND NEW.posted_price IS NOT DISTINCT FROM OLD.posted_price THEN
+        NEW.posted_price := NEW.price;
+    END IF;
+
+    IF NEW.tax_rate IS NULL OR (TG_OP = 'UPDATE' AND NEW.product_id IS DISTINCT FROM OLD.product_id AND NEW.tax_rate IS NOT DISTINCT FROM OLD.tax_rate) THEN
+        NEW.tax_rate := tax_rate_on((SELECT tax_class FROM products WHERE id = NEW.product_id), NEW.recorded_at::date);
+    END IF;
+
+    IF NEW.tax_included THEN
+        NEW.price := NEW.posted_price;
+    ELSE
+        NEW.price := ROUND(NEW.posted_price * (1 + NEW.tax_rate), 2);
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS prices_normalize_tax ON prices;
+CREATE TRIGGER prices_normalize_tax
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate, product_id ON prices
+    FOR EACH ROW EXECUTE FUNCTION normalize_price_tax();
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS prices_screen_anomaly ON prices;
+DROP FUNCTION IF EXISTS screen_price_anomaly();
+DROP FUNCTION IF EXISTS price_anomaly_threshold();
+DROP FUNCTION IF EXISTS price_anomaly_score(NUMERIC, NUMERIC, NUMERIC, INTEGER);
+DROP FUNCTION IF EXISTS price_baseline(INTEGER, TEXT, INTEGER);
+DROP INDEX IF EXISTS idx_prices_status;
+
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS baseline_price,
+    DROP COLUMN IF EXISTS anomaly_score,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,94 @@
+-- Anomaly screening for incoming prices. Each new price is compared with
+-- the product's recent active prices across stores using the median and
+-- the median absolute deviation (MAD); outliers are quarantined until a
+-- reviewer approves (active) or rejects them. Only active prices appear in
+-- listings and aggregates.
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'quarantined', 'rejected')),
+    ADD COLUMN IF NOT EXISTS anomaly_score NUMERIC(10, 2),
+    ADD COLUMN IF NOT EXISTS baseline_price DECIMAL(10, 2),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_prices_status ON prices(status) WHERE status <> 'active';
+
+-- Median and MAD of the product's active prices in one currency over the
+-- last 90 days, excluding one row (the price being screened)
+CREATE OR REPLACE FUNCTION price_baseline(p_product_id INTEGER, p_currency TEXT, p_exclude_id INTEGER)
+RETURNS TABLE (median NUMERIC, mad NUMERIC, sample_size INTEGER)
+LANGUAGE sql
+STABLE
+AS $$
+    WITH recent AS (
+        SELECT price
+        FROM prices
+        WHERE product_id = p_product_id
+          AND currency = p_currency
+          AND status = 'active'
+          AND id <> p_exclude_id
+          AND recorded_at >= NOW() - INTERVAL '90 days'
+    ),
+    center AS (
+        SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY price) AS median, COUNT(*) AS n
+        FROM recent
+    )
+    SELECT
+        center.median::numeric,
+        (SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY abs(recent.price - center.median)) FROM recent)::numeric,
+        center.n::integer
+    FROM center
+$$;
+
+-- Modified z-score (Iglewicz and Hoaglin). The MAD is floored at 5% of the
+-- median so products priced identically everywhere still tolerate small
+-- changes. NULL with fewer than 5 baseline prices.
+CREATE OR REPLACE FUNCTION price_anomaly_score(price NUMERIC, median NUMERIC, mad NUMERIC, sample_size INTEGER)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE
+        WHEN sample_size < 5 OR median IS NULL THEN NULL
+        ELSE ROUND(0.6745 * (price - median) / GREATEST(mad, median * 0.05, 0.01), 2)
+    END
+$$;
+
+-- Scores beyond this (in either direction) are quarantined
+CREATE OR REPLACE FUNCTION price_anomaly_threshold()
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT 3.5::numeric
+$$;
+
+CREATE OR REPLACE FUNCTION screen_price_anomaly()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    baseline RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.price IS NOT DISTINCT FROM OLD.price THEN
+        RETURN NEW;
+    END IF;
+    IF NEW.status <> 'active' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT * INTO baseline FROM price_baseline(NEW.product_id, NEW.currency, NEW.id);
+    NEW.baseline_price := ROUND(baseline.median, 2);
+    NEW.anomaly_score := price_anomaly_score(NEW.price, baseline.median, baseline.mad, baseline.sample_size);
+    IF abs(NEW.anomaly_score) > price_anomaly_threshold() THEN
+        NEW.status := 'quarantined';
+        NEW.reviewed_at := NULL;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+-- Named to run after prices_normalize_tax so tax-inclusive prices are compared
+DROP TRIGGER IF EXISTS prices_screen_anomaly ON prices;
+CREATE TRIGGER prices_screen_anomaly
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate ON prices
+    FOR EACH ROW EXECUTE FUNCTION screen_price_anomaly();
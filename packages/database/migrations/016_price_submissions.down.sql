@@ -0,0 +1,7 @@
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS contributor_id,
+    DROP COLUMN IF EXISTS source_type;
+
+DROP TABLE IF EXISTS price_submissions;
+DROP FUNCTION IF EXISTS contributor_reputation(INTEGER, INTEGER);
+DROP TABLE IF EXISTS contributors;
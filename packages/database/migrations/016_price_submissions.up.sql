@@ -0,0 +1,58 @@
+-- Crowdsourced prices. Shoppers and store managers report shelf prices from
+-- the mobile app as submissions; a submission becomes a price once a
+-- moderator approves it, or immediately when its contributor is trusted.
+CREATE TABLE IF NOT EXISTS contributors (
+    id SERIAL PRIMARY KEY,
+    external_id VARCHAR(100) NOT NULL UNIQUE,
+    display_name VARCHAR(100),
+    role VARCHAR(20) NOT NULL DEFAULT 'shopper' CHECK (role IN ('shopper', 'store_manager')),
+    store_id INTEGER REFERENCES stores(id) ON DELETE SET NULL,
+    approved_count INTEGER NOT NULL DEFAULT 0,
+    rejected_count INTEGER NOT NULL DEFAULT 0,
+    reputation NUMERIC(4, 3) NOT NULL DEFAULT 0.5,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT contributors_manager_store CHECK (role <> 'store_manager' OR store_id IS NOT NULL)
+);
+
+-- Share of a contributor's moderated submissions that were approved, with
+-- one approval and one rejection assumed up front (Laplace smoothing) so a
+-- newcomer starts at 0.5 and a single review cannot reach 0 or 1
+CREATE OR REPLACE FUNCTION contributor_reputation(approved INTEGER, rejected INTEGER)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT ROUND((approved + 1)::numeric / (approved + rejected + 2), 3)
+$$;
+
+-- price is as posted on the shelf, tax-inclusive unless tax_included is
+-- false; it is normalized by prices_normalize_tax once approved
+CREATE TABLE IF NOT EXISTS price_submissions (
+    id SERIAL PRIMARY KEY,
+    contributor_id INTEGER NOT NULL REFERENCES contributors(id) ON DELETE CASCADE,
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'JPY' CHECK (currency ~ '^[A-Z]{3}$'),
+    tax_included BOOLEAN NOT NULL DEFAULT true,
+    photo_url TEXT,
+    note TEXT,
+    observed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    auto_approved BOOLEAN NOT NULL DEFAULT false,
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    price_id INTEGER REFERENCES prices(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_submissions_status ON price_submissions(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_price_submissions_contributor ON price_submissions(contributor_id);
+
+-- Provenance of each price: scraped from a store's website or flyer,
+-- posted by the store's own manager, or reported by a shopper
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS source_type VARCHAR(20) NOT NULL DEFAULT 'scraper'
+        CHECK (source_type IN ('scraper', 'store_manager', 'crowd')),
+    ADD COLUMN IF NOT EXISTS contributor_id INTEGER REFERENCES contributors(id) ON DELETE SET NULL;
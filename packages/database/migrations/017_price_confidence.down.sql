@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS prices_default_confidence ON prices;
+DROP FUNCTION IF EXISTS default_price_confidence();
+DROP FUNCTION IF EXISTS price_confidence(NUMERIC, TIMESTAMP);
+DROP FUNCTION IF EXISTS price_confidence_half_life_days();
+DROP FUNCTION IF EXISTS source_base_confidence(TEXT);
+
+ALTER TABLE prices
+    DROP COLUMN IF EXISTS base_confidence,
+    DROP COLUMN IF EXISTS source;
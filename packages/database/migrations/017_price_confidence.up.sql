@@ -0,0 +1,72 @@
+-- Provenance and confidence of each price. source names where the number
+-- came from: the scraper feed (e.g. 'aeon-web'), 'user:<external_id>' for
+-- crowdsourced prices, or 'import:<batch>' for bulk imports.
+-- base_confidence is how far the source is trusted when the price is
+-- recorded; confidence then halves every price_confidence_half_life_days().
+ALTER TABLE prices
+    ADD COLUMN IF NOT EXISTS source VARCHAR(200),
+    ADD COLUMN IF NOT EXISTS base_confidence NUMERIC(4, 3)
+        CHECK (base_confidence >= 0 AND base_confidence <= 1);
+
+-- Initial confidence when the writer does not set one: store managers post
+-- their own shelf prices, scrapers can misparse, shoppers can mistype
+CREATE OR REPLACE FUNCTION source_base_confidence(p_source_type TEXT)
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT CASE p_source_type
+        WHEN 'store_manager' THEN 0.95
+        WHEN 'scraper' THEN 0.9
+        ELSE 0.8
+    END::numeric
+$$;
+
+CREATE OR REPLACE FUNCTION price_confidence_half_life_days()
+RETURNS NUMERIC
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT 14::numeric
+$$;
+
+-- Confidence now of a price recorded at recorded_at, between 0 and 1
+CREATE OR REPLACE FUNCTION price_confidence(base NUMERIC, recorded_at TIMESTAMP)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT ROUND(
+        base * power(0.5, GREATEST(EXTRACT(EPOCH FROM (LOCALTIMESTAMP - recorded_at)), 0)
+            / 86400 / price_confidence_half_life_days()),
+        3
+    )
+$$;
+
+UPDATE prices p
+SET source = 'user:' || c.external_id
+FROM contributors c
+WHERE c.id = p.contributor_id AND p.source IS NULL;
+
+UPDATE prices
+SET base_confidence = source_base_confidence(source_type)
+WHERE base_confidence IS NULL;
+
+CREATE OR REPLACE FUNCTION default_price_confidence()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.base_confidence IS NULL THEN
+        NEW.base_confidence := source_base_confidence(NEW.source_type);
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS prices_default_confidence ON prices;
+CREATE TRIGGER prices_default_confidence
+    BEFORE INSERT ON prices
+    FOR EACH ROW EXECUTE FUNCTION default_price_confidence();
+
+ALTER TABLE prices ALTER COLUMN base_confidence SET NOT NULL;
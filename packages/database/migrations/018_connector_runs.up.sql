@@ -0,0 +1,16 @@
+-- History of price feed connector runs (see internal/connector). Prices
+-- ingested by a run carry the connector name as prices.source.
+CREATE TABLE IF NOT EXISTS connector_runs (
+    id SERIAL PRIMARY KEY,
+    connector VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    rows_read INTEGER NOT NULL DEFAULT 0,
+    rows_inserted INTEGER NOT NULL DEFAULT 0,
+    rows_skipped INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_connector_runs_connector ON connector_runs(connector, started_at DESC);
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS products_queue_rollup ON products;
+DROP FUNCTION IF EXISTS queue_product_rollup();
+DROP TRIGGER IF EXISTS prices_queue_rollup ON prices;
+DROP FUNCTION IF EXISTS queue_price_rollup();
+DROP FUNCTION IF EXISTS refresh_price_rollups(INTEGER);
+
+DROP TABLE IF EXISTS price_rollup_pending;
+DROP TABLE IF EXISTS price_daily_category;
+DROP TABLE IF EXISTS price_daily_product;
@@ -0,0 +1,211 @@
+-- Daily aggregates of active prices behind the price statistics endpoints,
+-- per store/product/day and per store/category/day, each split by
+-- currency. Measures are kept both tax-inclusive (price_*) and
+-- tax-exclusive (excl_*) so either tax mode reads straight from the rollup.
+--
+-- Writes to prices only queue the affected store/product/day in
+-- price_rollup_pending; refresh_price_rollups() recomputes queued days and
+-- is run every PRICE_ROLLUP_REFRESH_SECONDS by the rollup.refresh job.
+CREATE TABLE IF NOT EXISTS price_daily_product (
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    price_count INTEGER NOT NULL,
+    price_sum NUMERIC NOT NULL,
+    price_min DECIMAL(10, 2) NOT NULL,
+    price_max DECIMAL(10, 2) NOT NULL,
+    excl_sum NUMERIC NOT NULL,
+    excl_min DECIMAL(10, 2) NOT NULL,
+    excl_max DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (store_id, product_id, day, currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_daily_product_store_day ON price_daily_product(store_id, day);
+
+CREATE TABLE IF NOT EXISTS price_daily_category (
+    store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    category VARCHAR(100) NOT NULL,
+    day DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    price_count INTEGER NOT NULL,
+    price_sum NUMERIC NOT NULL,
+    price_min DECIMAL(10, 2) NOT NULL,
+    price_max DECIMAL(10, 2) NOT NULL,
+    excl_sum NUMERIC NOT NULL,
+    excl_min DECIMAL(10, 2) NOT NULL,
+    excl_max DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (store_id, category, day, currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_daily_category_store_day ON price_daily_category(store_id, day);
+
+CREATE TABLE IF NOT EXISTS price_rollup_pending (
+    store_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    day DATE NOT NULL,
+    queued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (store_id, product_id, day)
+);
+
+CREATE OR REPLACE FUNCTION queue_price_rollup()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        INSERT INTO price_rollup_pending (store_id, product_id, day)
+        VALUES (OLD.store_id, OLD.product_id, OLD.recorded_at::date)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO price_rollup_pending (store_id, product_id, day)
+        VALUES (NEW.store_id, NEW.product_id, NEW.recorded_at::date)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS prices_queue_rollup ON prices;
+CREATE TRIGGER prices_queue_rollup
+    AFTER INSERT OR DELETE OR UPDATE OF store_id, product_id, recorded_at, price, currency, tax_rate, status ON prices
+    FOR EACH ROW EXECUTE FUNCTION queue_price_rollup();
+
+-- A product moving category moves its days between category rollups
+CREATE OR REPLACE FUNCTION queue_product_rollup()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    INSERT INTO price_rollup_pending (store_id, product_id, day)
+    SELECT DISTINCT store_id, product_id, day
+    FROM price_daily_product
+    WHERE product_id = NEW.id
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS products_queue_rollup ON products;
+CREATE TRIGGER products_queue_rollup
+    AFTER UPDATE OF category ON products
+    FOR EACH ROW
+    WHEN (OLD.category IS DISTINCT FROM NEW.category)
+    EXECUTE FUNCTION queue_product_rollup();
+
+-- Recomputes up to max_keys queued store/product/days, and the category
+-- days they belong to (before and after a category change), returning how
+-- many were taken. Refreshes are serialized; a call made while another is
+-- running returns 0 and leaves the queue to it.
+CREATE OR REPLACE FUNCTION refresh_price_rollups(max_keys INTEGER DEFAULT 10000)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    taken INTEGER;
+BEGIN
+    IF NOT pg_try_advisory_xact_lock(hashtext('refresh_price_rollups')) THEN
+        RETURN 0;
+    END IF;
+
+    CREATE TEMP TABLE IF NOT EXISTS rollup_keys (
+        store_id INTEGER, product_id INTEGER, day DATE
+    ) ON COMMIT DELETE ROWS;
+    CREATE TEMP TABLE IF NOT EXISTS rollup_categories (
+        store_id INTEGER, category VARCHAR(100), day DATE
+    ) ON COMMIT DELETE ROWS;
+    TRUNCATE rollup_keys, rollup_categories;
+
+    WITH batch AS (
+        DELETE FROM price_rollup_pending
+        WHERE (store_id, product_id, day) IN (
+            SELECT store_id, product_id, day
+            FROM price_rollup_pending
+            ORDER BY queued_at
+            LIMIT max_keys
+        )
+        RETURNING store_id, product_id, day
+    )
+    INSERT INTO rollup_keys SELECT store_id, product_id, day FROM batch;
+    GET DIAGNOSTICS taken = ROW_COUNT;
+    IF taken = 0 THEN
+        RETURN 0;
+    END IF;
+
+    WITH removed AS (
+        DELETE FROM price_daily_product d
+        USING rollup_keys k
+        WHERE d.store_id = k.store_id AND d.product_id = k.product_id AND d.day = k.day
+        RETURNING d.store_id, d.category, d.day
+    )
+    INSERT INTO rollup_categories SELECT store_id, category, day FROM removed;
+
+    WITH added AS (
+        INSERT INTO price_daily_product (
+            store_id, product_id, day, currency, category,
+            price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+        )
+        SELECT
+            k.store_id, k.product_id, k.day, p.currency, COALESCE(pr.category, ''),
+            COUNT(*), SUM(p.price), MIN(p.price), MAX(p.price),
+            SUM(exclude_tax(p.price, p.tax_rate)), MIN(exclude_tax(p.price, p.tax_rate)), MAX(exclude_tax(p.price, p.tax_rate))
+        FROM rollup_keys k
+        JOIN prices p
+            ON p.store_id = k.store_id AND p.product_id = k.product_id
+            AND p.recorded_at >= k.day AND p.recorded_at < k.day + 1
+        JOIN products pr ON pr.id = k.product_id
+        WHERE p.status = 'active'
+        GROUP BY k.store_id, k.product_id, k.day, p.currency, pr.category
+        RETURNING store_id, category, day
+    )
+    INSERT INTO rollup_categories SELECT store_id, category, day FROM added;
+
+    DELETE FROM price_daily_category c
+    USING (SELECT DISTINCT store_id, category, day FROM rollup_categories) r
+    WHERE c.store_id = r.store_id AND c.category = r.category AND c.day = r.day;
+
+    INSERT INTO price_daily_category (
+        store_id, category, day, currency,
+        price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+    )
+    SELECT
+        d.store_id, d.category, d.day, d.currency,
+        SUM(d.price_count), SUM(d.price_sum), MIN(d.price_min), MAX(d.price_max),
+        SUM(d.excl_sum), MIN(d.excl_min), MAX(d.excl_max)
+    FROM price_daily_product d
+    JOIN (SELECT DISTINCT store_id, category, day FROM rollup_categories) r
+        ON r.store_id = d.store_id AND r.category = d.category AND r.day = d.day
+    GROUP BY d.store_id, d.category, d.day, d.currency;
+
+    RETURN taken;
+END
+$$;
+
+-- Backfill from existing prices
+INSERT INTO price_daily_product (
+    store_id, product_id, day, currency, category,
+    price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+)
+SELECT
+    p.store_id, p.product_id, p.recorded_at::date, p.currency, COALESCE(pr.category, ''),
+    COUNT(*), SUM(p.price), MIN(p.price), MAX(p.price),
+    SUM(exclude_tax(p.price, p.tax_rate)), MIN(exclude_tax(p.price, p.tax_rate)), MAX(exclude_tax(p.price, p.tax_rate))
+FROM prices p
+JOIN products pr ON pr.id = p.product_id
+WHERE p.status = 'active'
+GROUP BY p.store_id, p.product_id, p.recorded_at::date, p.currency, pr.category
+ON CONFLICT DO NOTHING;
+
+INSERT INTO price_daily_category (
+    store_id, category, day, currency,
+    price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+)
+SELECT
+    store_id, category, day, currency,
+    SUM(price_count), SUM(price_sum), MIN(price_min), MAX(price_max),
+    SUM(excl_sum), MIN(excl_min), MAX(excl_max)
+FROM price_daily_product
+GROUP BY store_id, category, day, currency
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,64 @@
+DROP TRIGGER IF EXISTS prices_default_confidence ON prices;
+DROP TRIGGER IF EXISTS prices_normalize_tax ON prices;
+DROP TRIGGER IF EXISTS prices_screen_anomaly ON prices;
+DROP TRIGGER IF EXISTS prices_queue_rollup ON prices;
+
+ALTER TABLE prices RENAME TO prices_partitioned;
+ALTER TABLE prices_partitioned RENAME CONSTRAINT prices_pkey TO prices_partitioned_pkey;
+ALTER TABLE prices_partitioned RENAME CONSTRAINT prices_unique_store_product_time TO prices_partitioned_unique_store_product_time;
+DROP INDEX IF EXISTS idx_prices_store_id;
+DROP INDEX IF EXISTS idx_prices_product_id;
+DROP INDEX IF EXISTS idx_prices_recorded_at;
+DROP INDEX IF EXISTS idx_prices_status;
+
+CREATE TABLE prices (
+    LIKE prices_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+);
+
+ALTER SEQUENCE prices_id_seq OWNED BY prices.id;
+
+-- Archived months are not restored
+INSERT INTO prices SELECT * FROM prices_partitioned;
+DROP TABLE prices_partitioned;
+
+ALTER TABLE prices
+    ADD CONSTRAINT prices_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT prices_unique_store_product_time UNIQUE (store_id, product_id, recorded_at),
+    ADD CONSTRAINT prices_store_id_fkey FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE,
+    ADD CONSTRAINT prices_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    ADD CONSTRAINT prices_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES contributors(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_prices_store_id ON prices(store_id);
+CREATE INDEX IF NOT EXISTS idx_prices_product_id ON prices(product_id);
+CREATE INDEX IF NOT EXISTS idx_prices_recorded_at ON prices(recorded_at);
+CREATE INDEX IF NOT EXISTS idx_prices_status ON prices(status) WHERE status <> 'active';
+
+UPDATE price_submissions ps
+SET price_id = NULL
+WHERE price_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM prices p WHERE p.id = ps.price_id);
+
+ALTER TABLE price_submissions
+    ADD CONSTRAINT price_submissions_price_id_fkey FOREIGN KEY (price_id) REFERENCES prices(id) ON DELETE SET NULL;
+
+CREATE TRIGGER prices_default_confidence
+    BEFORE INSERT ON prices
+    FOR EACH ROW EXECUTE FUNCTION default_price_confidence();
+
+CREATE TRIGGER prices_normalize_tax
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate, product_id ON prices
+    FOR EACH ROW EXECUTE FUNCTION normalize_price_tax();
+
+CREATE TRIGGER prices_screen_anomaly
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate ON prices
+    FOR EACH ROW EXECUTE FUNCTION screen_price_anomaly();
+
+CREATE TRIGGER prices_queue_rollup
+    AFTER INSERT OR DELETE OR UPDATE OF store_id, product_id, recorded_at, price, currency, tax_rate, status ON prices
+    FOR EACH ROW EXECUTE FUNCTION queue_price_rollup();
+
+DROP TABLE IF EXISTS price_archives;
+DROP FUNCTION IF EXISTS price_confidence_horizon(NUMERIC);
+DROP FUNCTION IF EXISTS price_partitions();
+DROP FUNCTION IF EXISTS ensure_price_partitions(INTEGER);
+DROP FUNCTION IF EXISTS create_price_partition(DATE);
+DROP FUNCTION IF EXISTS price_partition_name(DATE);
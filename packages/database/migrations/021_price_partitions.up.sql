@@ -0,0 +1,182 @@
+-- Range-partition prices by month of recorded_at. Each month lives in
+-- prices_yYYYYmMM; rows outside every monthly partition land in
+-- prices_default until ensure_price_partitions() gives them a month.
+-- The prices.maintain background job keeps partitions created ahead of
+-- time and archives months older than PRICE_RETENTION_MONTHS to gzipped
+-- JSON lines files, recording each in price_archives.
+--
+-- The primary key now includes recorded_at (partitioned tables need the
+-- partition key in every unique constraint), so price_submissions.price_id
+-- can no longer be a foreign key; it may point at an archived price.
+ALTER TABLE price_submissions DROP CONSTRAINT IF EXISTS price_submissions_price_id_fkey;
+
+DROP TRIGGER IF EXISTS prices_default_confidence ON prices;
+DROP TRIGGER IF EXISTS prices_normalize_tax ON prices;
+DROP TRIGGER IF EXISTS prices_screen_anomaly ON prices;
+DROP TRIGGER IF EXISTS prices_queue_rollup ON prices;
+
+ALTER TABLE prices RENAME TO prices_unpartitioned;
+ALTER TABLE prices_unpartitioned RENAME CONSTRAINT prices_pkey TO prices_unpartitioned_pkey;
+ALTER TABLE prices_unpartitioned RENAME CONSTRAINT prices_unique_store_product_time TO prices_unpartitioned_unique_store_product_time;
+DROP INDEX IF EXISTS idx_prices_store_id;
+DROP INDEX IF EXISTS idx_prices_product_id;
+DROP INDEX IF EXISTS idx_prices_recorded_at;
+DROP INDEX IF EXISTS idx_prices_status;
+
+CREATE TABLE prices (
+    LIKE prices_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+) PARTITION BY RANGE (recorded_at);
+
+ALTER SEQUENCE prices_id_seq OWNED BY prices.id;
+
+ALTER TABLE prices
+    ADD CONSTRAINT prices_pkey PRIMARY KEY (id, recorded_at),
+    ADD CONSTRAINT prices_unique_store_product_time UNIQUE (store_id, product_id, recorded_at),
+    ADD CONSTRAINT prices_store_id_fkey FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE,
+    ADD CONSTRAINT prices_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    ADD CONSTRAINT prices_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES contributors(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_prices_store_id ON prices(store_id);
+CREATE INDEX IF NOT EXISTS idx_prices_product_id ON prices(product_id);
+CREATE INDEX IF NOT EXISTS idx_prices_recorded_at ON prices(recorded_at);
+CREATE INDEX IF NOT EXISTS idx_prices_status ON prices(status) WHERE status <> 'active';
+
+CREATE TABLE IF NOT EXISTS prices_default PARTITION OF prices DEFAULT;
+
+CREATE OR REPLACE FUNCTION price_partition_name(p_month DATE)
+RETURNS TEXT
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT 'prices_' || to_char(p_month, '"y"YYYY"m"MM')
+$$;
+
+-- Creates the partition for the month containing p_month unless it exists,
+-- moving that month's rows out of prices_default. Returns whether it was
+-- created.
+CREATE OR REPLACE FUNCTION create_price_partition(p_month DATE)
+RETURNS BOOLEAN
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    range_start DATE := date_trunc('month', p_month)::date;
+    range_end DATE := (date_trunc('month', p_month) + INTERVAL '1 month')::date;
+    partition_name TEXT := price_partition_name(range_start);
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN false;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE prices INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', partition_name);
+    EXECUTE format(
+        'WITH moved AS (DELETE FROM prices_default WHERE recorded_at >= %L AND recorded_at < %L RETURNING *) '
+        'INSERT INTO %I SELECT * FROM moved',
+        range_start, range_end, partition_name
+    );
+    EXECUTE format(
+        'ALTER TABLE prices ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name, range_start, range_end
+    );
+    RETURN true;
+END
+$$;
+
+-- Creates the partitions for this month and months_ahead following ones,
+-- and for any month with rows waiting in prices_default. Returns how many
+-- partitions were created.
+CREATE OR REPLACE FUNCTION ensure_price_partitions(months_ahead INTEGER)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    created INTEGER := 0;
+    partition_month DATE;
+BEGIN
+    FOR partition_month IN
+        SELECT generate_series(date_trunc('month', LOCALTIMESTAMP), date_trunc('month', LOCALTIMESTAMP) + make_interval(months => months_ahead), INTERVAL '1 month')::date
+        UNION
+        SELECT DISTINCT date_trunc('month', recorded_at)::date FROM prices_default
+    LOOP
+        IF create_price_partition(partition_month) THEN
+            created := created + 1;
+        END IF;
+    END LOOP;
+    RETURN created;
+END
+$$;
+
+-- The monthly partitions of prices and the range each holds
+CREATE OR REPLACE FUNCTION price_partitions()
+RETURNS TABLE (partition_name TEXT, range_start DATE, range_end DATE)
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT c.relname::text, m.month, (m.month + INTERVAL '1 month')::date
+    FROM pg_inherits i
+    JOIN pg_class c ON c.oid = i.inhrelid
+    CROSS JOIN LATERAL (
+        SELECT CASE
+            WHEN c.relname ~ '^prices_y[0-9]{4}m[0-9]{2}$' THEN to_date(substr(c.relname, 8), '"y"YYYY"m"MM')
+        END AS month
+    ) m
+    WHERE i.inhparent = 'prices'::regclass AND m.month IS NOT NULL
+    ORDER BY m.month
+$$;
+
+-- Oldest recorded_at a price can have and still reach min_confidence:
+-- confidence starts at most at 1 and halves every half-life, and is
+-- rounded to 3 places. Filtering on it lets queries skip old partitions.
+CREATE OR REPLACE FUNCTION price_confidence_horizon(min_confidence NUMERIC)
+RETURNS TIMESTAMP
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE
+        WHEN min_confidence <= 0.0005 THEN '-infinity'::timestamp
+        ELSE LOCALTIMESTAMP - (log(2, 1 / (min_confidence - 0.0005)) * price_confidence_half_life_days())::float8 * INTERVAL '1 day'
+    END
+$$;
+
+CREATE TABLE IF NOT EXISTS price_archives (
+    id SERIAL PRIMARY KEY,
+    partition_name VARCHAR(63) NOT NULL,
+    range_start DATE NOT NULL,
+    range_end DATE NOT NULL,
+    row_count BIGINT NOT NULL,
+    path TEXT NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+DECLARE
+    partition_month DATE;
+BEGIN
+    FOR partition_month IN SELECT DISTINCT date_trunc('month', recorded_at)::date FROM prices_unpartitioned LOOP
+        PERFORM create_price_partition(partition_month);
+    END LOOP;
+END
+$$;
+SELECT ensure_price_partitions(3);
+
+-- Copied before the triggers exist so rows are not renormalized, rescreened
+-- or queued for rollups again
+INSERT INTO prices SELECT * FROM prices_unpartitioned;
+DROP TABLE prices_unpartitioned;
+
+CREATE TRIGGER prices_default_confidence
+    BEFORE INSERT ON prices
+    FOR EACH ROW EXECUTE FUNCTION default_price_confidence();
+
+CREATE TRIGGER prices_normalize_tax
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate, product_id ON prices
+    FOR EACH ROW EXECUTE FUNCTION normalize_price_tax();
+
+CREATE TRIGGER prices_screen_anomaly
+    BEFORE INSERT OR UPDATE OF price, posted_price, tax_included, tax_rate ON prices
+    FOR EACH ROW EXECUTE FUNCTION screen_price_anomaly();
+
+CREATE TRIGGER prices_queue_rollup
+    AFTER INSERT OR DELETE OR UPDATE OF store_id, product_id, recorded_at, price, currency, tax_rate, status ON prices
+    FOR EACH ROW EXECUTE FUNCTION queue_price_rollup();
+
+ANALYZE prices;
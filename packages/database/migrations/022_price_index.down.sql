@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_price_daily_product_category_day;
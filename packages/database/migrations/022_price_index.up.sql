@@ -0,0 +1,2 @@
+-- Price indexes read a category's product rollups over a date range
+CREATE INDEX IF NOT EXISTS idx_price_daily_product_category_day ON price_daily_product(category, day);
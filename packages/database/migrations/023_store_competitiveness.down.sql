@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS refresh_store_competitiveness(DOUBLE PRECISION, INTEGER, INTEGER, INTEGER);
+DROP TABLE IF EXISTS store_competitiveness;
@@ -0,0 +1,69 @@
+-- How a store's prices compare with nearby stores. For each product a store
+-- priced within the window, its average price is divided by the median of
+-- the averages at stores within the radius (the store included) that price
+-- the product in the same currency, counting only products at least
+-- min_stores of those stores price. score is 100 times the geometric mean
+-- of those ratios: 100 is the local median, 95 means 5% cheaper. Stores
+-- comparing fewer than min_products products get no score.
+CREATE TABLE IF NOT EXISTS store_competitiveness (
+    store_id INTEGER PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    score NUMERIC(8, 2) NOT NULL,
+    products_compared INTEGER NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_competitiveness_score ON store_competitiveness(score);
+
+-- Recomputes every score from the daily product rollups of the last
+-- window_days days and returns how many stores were scored
+CREATE OR REPLACE FUNCTION refresh_store_competitiveness(
+    radius_meters DOUBLE PRECISION,
+    window_days INTEGER,
+    min_stores INTEGER,
+    min_products INTEGER
+)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    scored INTEGER;
+BEGIN
+    -- Runs are serialized; a concurrent run would compute the same scores
+    IF NOT pg_try_advisory_xact_lock(hashtext('refresh_store_competitiveness')) THEN
+        RETURN 0;
+    END IF;
+
+    CREATE TEMP TABLE competitiveness_prices ON COMMIT DROP AS
+    SELECT d.store_id, d.product_id, d.currency, (SUM(d.price_sum) / SUM(d.price_count))::float8 AS avg_price
+    FROM price_daily_product d
+    WHERE d.day > CURRENT_DATE - window_days
+    GROUP BY d.store_id, d.product_id, d.currency
+    HAVING SUM(d.price_sum) > 0;
+
+    CREATE INDEX ON competitiveness_prices (product_id, currency);
+    ANALYZE competitiveness_prices;
+
+    DELETE FROM store_competitiveness;
+
+    INSERT INTO store_competitiveness (store_id, score, products_compared)
+    SELECT own.store_id, 100 * exp(avg(ln(own.avg_price / local.median_price))), COUNT(*)
+    FROM competitiveness_prices own
+    JOIN stores s ON s.id = own.store_id
+    CROSS JOIN LATERAL (
+        SELECT
+            percentile_cont(0.5) WITHIN GROUP (ORDER BY other.avg_price) AS median_price,
+            COUNT(*) AS store_count
+        FROM competitiveness_prices other
+        JOIN stores o ON o.id = other.store_id
+        WHERE other.product_id = own.product_id
+          AND other.currency = own.currency
+          AND ST_DWithin(o.location, s.location, radius_meters)
+    ) local
+    WHERE local.store_count >= min_stores
+    GROUP BY own.store_id
+    HAVING COUNT(*) >= min_products;
+
+    GET DIAGNOSTICS scored = ROW_COUNT;
+    RETURN scored;
+END
+$$;
@@ -0,0 +1,12 @@
+-- products.category keeps the canonical names it was given
+DROP TRIGGER IF EXISTS products_file_category ON products;
+DROP FUNCTION IF EXISTS file_product_category();
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TRIGGER IF EXISTS categories_sync_name ON categories;
+DROP FUNCTION IF EXISTS sync_category_name();
+DROP FUNCTION IF EXISTS category_subtree(TEXT[]);
+DROP FUNCTION IF EXISTS resolve_category(TEXT);
+DROP FUNCTION IF EXISTS set_category_alias(TEXT, INTEGER);
+DROP TABLE IF EXISTS category_aliases;
+DROP TABLE IF EXISTS categories;
+DROP FUNCTION IF EXISTS category_alias_key(TEXT);
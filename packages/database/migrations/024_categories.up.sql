@@ -0,0 +1,207 @@
+-- Product categories as a tree. name is the Japanese display name, names
+-- holds translations keyed by language tag ({"en": "Dairy"}), and slug is
+-- the stable identifier filters and clients use.
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    slug VARCHAR(100) NOT NULL UNIQUE CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name VARCHAR(100) NOT NULL,
+    names JSONB NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(names) = 'object'),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_id IS DISTINCT FROM id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Free-text category strings ("乳製品", "Dairy", "乳制品") resolve to one
+-- category through their alias key
+CREATE TABLE IF NOT EXISTS category_aliases (
+    alias VARCHAR(100) PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_aliases_category_id ON category_aliases(category_id);
+
+CREATE OR REPLACE FUNCTION category_alias_key(category TEXT)
+RETURNS TEXT
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT NULLIF(lower(btrim(normalize(category, NFKC))), '')
+$$;
+
+-- Points a category string at target_id and moves the products filed
+-- under it, e.g. to merge a misspelled category into the right one
+CREATE OR REPLACE FUNCTION set_category_alias(alias_name TEXT, target_id INTEGER)
+RETURNS VOID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    key TEXT := category_alias_key(alias_name);
+BEGIN
+    IF key IS NULL THEN
+        RETURN;
+    END IF;
+    INSERT INTO category_aliases (alias, category_id)
+    VALUES (key, target_id)
+    ON CONFLICT (alias) DO UPDATE SET category_id = EXCLUDED.category_id;
+
+    UPDATE products
+    SET category_id = target_id
+    WHERE category_alias_key(category) = key
+        AND category_id IS DISTINCT FROM target_id;
+END
+$$;
+
+-- The category a free-text string names, matched by alias and then slug.
+-- Strings nothing matches get a new top-level category, so no product is
+-- left unfiled; merge it into the right one with set_category_alias.
+CREATE OR REPLACE FUNCTION resolve_category(category TEXT)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    key TEXT := category_alias_key(category);
+    matched INTEGER;
+BEGIN
+    IF key IS NULL THEN
+        RETURN NULL;
+    END IF;
+    SELECT a.category_id INTO matched FROM category_aliases a WHERE a.alias = key;
+    IF matched IS NULL THEN
+        SELECT c.id INTO matched FROM categories c WHERE c.slug = key;
+    END IF;
+    IF matched IS NULL THEN
+        INSERT INTO categories (slug, name)
+        VALUES ('category-' || left(md5(key), 10), btrim(category))
+        ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+        RETURNING id INTO matched;
+        INSERT INTO category_aliases (alias, category_id)
+        VALUES (key, matched)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN matched;
+END
+$$;
+
+-- Matching ids for slugs or category strings, with all their descendants
+CREATE OR REPLACE FUNCTION category_subtree(keys TEXT[])
+RETURNS SETOF INTEGER
+LANGUAGE sql
+STABLE
+AS $$
+    WITH RECURSIVE tree AS (
+        SELECT c.id
+        FROM categories c
+        WHERE c.slug = ANY(keys)
+            OR c.id IN (
+                SELECT a.category_id
+                FROM category_aliases a
+                WHERE a.alias IN (SELECT category_alias_key(k) FROM unnest(keys) k)
+            )
+        UNION
+        SELECT child.id
+        FROM categories child
+        JOIN tree ON child.parent_id = tree.id
+    )
+    SELECT id FROM tree
+$$;
+
+-- A new category's name resolves to it unless another category already
+-- claims that string. Renaming a category claims the new name and renames
+-- its products' category strings.
+CREATE OR REPLACE FUNCTION sync_category_name()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO category_aliases (alias, category_id)
+        SELECT category_alias_key(NEW.name), NEW.id
+        WHERE category_alias_key(NEW.name) IS NOT NULL
+        ON CONFLICT DO NOTHING;
+    ELSIF NEW.name IS DISTINCT FROM OLD.name THEN
+        PERFORM set_category_alias(NEW.name, NEW.id);
+        UPDATE products SET category = NEW.name WHERE category_id = NEW.id;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS categories_sync_name ON categories;
+CREATE TRIGGER categories_sync_name
+    AFTER INSERT OR UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION sync_category_name();
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
+
+-- products.category is kept as the filed category's name, so the rollups
+-- and stats keyed on it see one category per category_id
+CREATE OR REPLACE FUNCTION file_product_category()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.category_id IS NULL THEN
+            NEW.category_id := resolve_category(NEW.category);
+        END IF;
+    ELSIF NEW.category_id IS DISTINCT FROM OLD.category_id THEN
+        -- Filed directly, as set_category_alias does
+        NULL;
+    ELSIF NEW.category IS DISTINCT FROM OLD.category OR NEW.category_id IS NULL THEN
+        NEW.category_id := resolve_category(NEW.category);
+    END IF;
+    IF NEW.category_id IS NOT NULL THEN
+        SELECT name INTO NEW.category FROM categories WHERE id = NEW.category_id;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS products_file_category ON products;
+CREATE TRIGGER products_file_category
+    BEFORE INSERT OR UPDATE OF category, category_id ON products
+    FOR EACH ROW EXECUTE FUNCTION file_product_category();
+
+-- The categories the seed and importers use, with food groups under 食品
+INSERT INTO categories (slug, name, names) VALUES
+    ('food', '食品', '{"en": "Food"}'),
+    ('beverages', '飲料', '{"en": "Beverages"}'),
+    ('baby', 'ベビー', '{"en": "Baby"}'),
+    ('household', '日用品', '{"en": "Household"}')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO categories (parent_id, slug, name, names)
+SELECT parent.id, v.slug, v.name, v.names::jsonb
+FROM (VALUES
+    ('dairy', '乳製品', '{"en": "Dairy"}'),
+    ('bread', 'パン', '{"en": "Bread"}'),
+    ('fresh-food', '生鮮食品', '{"en": "Fresh food"}'),
+    ('seasonings', '調味料', '{"en": "Seasonings"}'),
+    ('snacks', 'スナック', '{"en": "Snacks"}'),
+    ('frozen-food', '冷凍食品', '{"en": "Frozen food"}')
+) AS v(slug, name, names)
+JOIN categories parent ON parent.slug = 'food'
+ON CONFLICT (slug) DO NOTHING;
+
+SELECT set_category_alias(v.alias, c.id)
+FROM (VALUES
+    ('Food', 'food'), ('食料品', 'food'),
+    ('Beverages', 'beverages'), ('Beverage', 'beverages'), ('Drinks', 'beverages'), ('飲み物', 'beverages'), ('ドリンク', 'beverages'),
+    ('Baby', 'baby'), ('ベビー用品', 'baby'),
+    ('Household', 'household'), ('日用雑貨', 'household'),
+    ('Dairy', 'dairy'), ('乳製品類', 'dairy'),
+    ('Bread', 'bread'), ('Bakery', 'bread'), ('ベーカリー', 'bread'),
+    ('Fresh food', 'fresh-food'), ('Produce', 'fresh-food'), ('生鮮', 'fresh-food'),
+    ('Seasonings', 'seasonings'), ('Condiments', 'seasonings'),
+    ('Snacks', 'snacks'), ('お菓子', 'snacks'), ('菓子', 'snacks'),
+    ('Frozen food', 'frozen-food'), ('Frozen', 'frozen-food'), ('冷凍', 'frozen-food')
+) AS v(alias, slug)
+JOIN categories c ON c.slug = v.slug;
+
+-- Files every existing product, creating categories for unmatched strings
+UPDATE products SET category = category WHERE category_id IS NULL;
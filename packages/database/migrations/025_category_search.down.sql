@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_category_aliases_search_alias_prefix;
+DROP INDEX IF EXISTS idx_category_aliases_search_alias_trgm;
+DROP INDEX IF EXISTS idx_categories_search_name_prefix;
+DROP INDEX IF EXISTS idx_categories_search_name_trgm;
+ALTER TABLE category_aliases DROP COLUMN IF EXISTS search_alias;
+ALTER TABLE categories DROP COLUMN IF EXISTS search_name;
@@ -0,0 +1,12 @@
+-- Typeahead matches categories and their aliases through trigram and
+-- prefix indexes instead of normalizing every product's category string
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS search_name TEXT GENERATED ALWAYS AS (search_normalize(name)) STORED;
+
+ALTER TABLE category_aliases
+    ADD COLUMN IF NOT EXISTS search_alias TEXT GENERATED ALWAYS AS (search_normalize(alias)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_categories_search_name_trgm ON categories USING GIN (search_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_search_name_prefix ON categories (search_name text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_category_aliases_search_alias_trgm ON category_aliases USING GIN (search_alias gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_category_aliases_search_alias_prefix ON category_aliases (search_alias text_pattern_ops);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_postal_codes_search_address;
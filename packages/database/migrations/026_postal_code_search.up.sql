@@ -0,0 +1,3 @@
+-- Address geocoding looks up each prefix of the input by equality; the
+-- existing trigram index serves the contains fallback
+CREATE INDEX IF NOT EXISTS idx_postal_codes_search_address ON postal_codes (search_address);
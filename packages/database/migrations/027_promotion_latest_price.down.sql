@@ -0,0 +1,24 @@
+DROP FUNCTION IF EXISTS effective_price(NUMERIC, TEXT, INTEGER, INTEGER, TIMESTAMP, INTEGER, BOOLEAN);
+DROP FUNCTION IF EXISTS price_is_latest(INTEGER, INTEGER, TIMESTAMP);
+
+CREATE OR REPLACE FUNCTION effective_price(
+    regular NUMERIC,
+    price_currency TEXT,
+    p_store_id INTEGER,
+    p_product_id INTEGER,
+    quantity INTEGER,
+    member BOOLEAN
+)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT LEAST(regular, MIN(promotion_total(regular, pm.sale_price, pm.bundle_quantity, pm.bundle_price, quantity) / quantity))
+    FROM promotions pm
+    WHERE pm.store_id = p_store_id
+      AND pm.product_id = p_product_id
+      AND pm.currency = price_currency
+      AND pm.valid_from <= CURRENT_TIMESTAMP
+      AND (pm.valid_to IS NULL OR pm.valid_to > CURRENT_TIMESTAMP)
+      AND (member OR NOT pm.member_only)
+$$;
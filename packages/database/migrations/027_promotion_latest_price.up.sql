@@ -0,0 +1,51 @@
+-- Promotions describe what a product costs now, so they apply only to the
+-- latest active price of each store and product. Older prices keep their
+-- recorded amount instead of picking up today's offers.
+CREATE OR REPLACE FUNCTION price_is_latest(p_store_id INTEGER, p_product_id INTEGER, p_recorded_at TIMESTAMP)
+RETURNS BOOLEAN
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT NOT EXISTS (
+        SELECT 1
+        FROM prices newer
+        WHERE newer.store_id = p_store_id
+          AND newer.product_id = p_product_id
+          AND newer.recorded_at > p_recorded_at
+          AND newer.status = 'active'
+    )
+$$;
+
+DROP FUNCTION IF EXISTS effective_price(NUMERIC, TEXT, INTEGER, INTEGER, INTEGER, BOOLEAN);
+
+-- Per-unit price paid when buying quantity units at the price recorded at
+-- p_recorded_at: for the latest price, the cheapest of it and every
+-- promotion active now in the same currency; otherwise the price itself.
+-- Member-only promotions count only for members.
+CREATE OR REPLACE FUNCTION effective_price(
+    regular NUMERIC,
+    price_currency TEXT,
+    p_store_id INTEGER,
+    p_product_id INTEGER,
+    p_recorded_at TIMESTAMP,
+    quantity INTEGER,
+    member BOOLEAN
+)
+RETURNS NUMERIC
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE
+        WHEN price_is_latest(p_store_id, p_product_id, p_recorded_at) THEN (
+            SELECT LEAST(regular, MIN(promotion_total(regular, pm.sale_price, pm.bundle_quantity, pm.bundle_price, quantity) / quantity))
+            FROM promotions pm
+            WHERE pm.store_id = p_store_id
+              AND pm.product_id = p_product_id
+              AND pm.currency = price_currency
+              AND pm.valid_from <= CURRENT_TIMESTAMP
+              AND (pm.valid_to IS NULL OR pm.valid_to > CURRENT_TIMESTAMP)
+              AND (member OR NOT pm.member_only)
+        )
+        ELSE regular
+    END
+$$;
@@ -0,0 +1,27 @@
+CREATE OR REPLACE FUNCTION screen_price_anomaly()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    baseline RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.price IS NOT DISTINCT FROM OLD.price THEN
+        RETURN NEW;
+    END IF;
+    IF NEW.status <> 'active' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT * INTO baseline FROM price_baseline(NEW.product_id, NEW.currency, NEW.id);
+    NEW.baseline_price := ROUND(baseline.median, 2);
+    NEW.anomaly_score := price_anomaly_score(NEW.price, baseline.median, baseline.mad, baseline.sample_size);
+    IF abs(NEW.anomaly_score) > price_anomaly_threshold() THEN
+        NEW.status := 'quarantined';
+        NEW.reviewed_at := NULL;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+ALTER TABLE price_submissions DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE prices DROP COLUMN IF EXISTS reviewed_by;
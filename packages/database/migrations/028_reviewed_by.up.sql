@@ -0,0 +1,31 @@
+-- Who settled each moderated price and submission: the name of the admin
+-- key the review was made with. Automatic approvals leave it NULL.
+ALTER TABLE prices ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(100);
+ALTER TABLE price_submissions ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(100);
+
+-- A price quarantined again after an edit awaits a new review
+CREATE OR REPLACE FUNCTION screen_price_anomaly()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    baseline RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.price IS NOT DISTINCT FROM OLD.price THEN
+        RETURN NEW;
+    END IF;
+    IF NEW.status <> 'active' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT * INTO baseline FROM price_baseline(NEW.product_id, NEW.currency, NEW.id);
+    NEW.baseline_price := ROUND(baseline.median, 2);
+    NEW.anomaly_score := price_anomaly_score(NEW.price, baseline.median, baseline.mad, baseline.sample_size);
+    IF abs(NEW.anomaly_score) > price_anomaly_threshold() THEN
+        NEW.status := 'quarantined';
+        NEW.reviewed_at := NULL;
+        NEW.reviewed_by := NULL;
+    END IF;
+    RETURN NEW;
+END
+$$;
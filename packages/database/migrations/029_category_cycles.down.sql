@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS categories_check_parent ON categories;
+DROP FUNCTION IF EXISTS check_category_parent();
@@ -0,0 +1,31 @@
+-- A category cannot be filed under itself or one of its descendants; the
+-- tree would lose the whole cycle. Parent changes are serialized so two
+-- concurrent moves cannot form a cycle between them.
+CREATE OR REPLACE FUNCTION check_category_parent()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+    PERFORM pg_advisory_xact_lock(hashtext('categories.parent_id'));
+    IF EXISTS (
+        WITH RECURSIVE ancestors AS (
+            SELECT c.id, c.parent_id FROM categories c WHERE c.id = NEW.parent_id
+            UNION
+            SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+        )
+        SELECT 1 FROM ancestors WHERE id = NEW.id
+    ) THEN
+        RAISE EXCEPTION 'category % cannot be filed under its descendant %', NEW.id, NEW.parent_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS categories_check_parent ON categories;
+CREATE TRIGGER categories_check_parent
+    BEFORE INSERT OR UPDATE OF parent_id ON categories
+    FOR EACH ROW EXECUTE FUNCTION check_category_parent();
@@ -0,0 +1,119 @@
+ALTER TABLE price_daily_product ADD COLUMN IF NOT EXISTS category VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE price_daily_product d
+SET category = c.name
+FROM categories c
+WHERE c.id = d.category_id;
+
+DROP INDEX IF EXISTS idx_price_daily_product_category_id_day;
+ALTER TABLE price_daily_product DROP COLUMN IF EXISTS category_id;
+CREATE INDEX IF NOT EXISTS idx_price_daily_product_category_day ON price_daily_product(category, day);
+
+TRUNCATE price_daily_category;
+ALTER TABLE price_daily_category DROP CONSTRAINT IF EXISTS price_daily_category_pkey;
+ALTER TABLE price_daily_category DROP COLUMN IF EXISTS category_id;
+ALTER TABLE price_daily_category ADD COLUMN IF NOT EXISTS category VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE price_daily_category ALTER COLUMN category DROP DEFAULT;
+ALTER TABLE price_daily_category ADD PRIMARY KEY (store_id, category, day, currency);
+
+INSERT INTO price_daily_category (
+    store_id, category, day, currency,
+    price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+)
+SELECT
+    store_id, category, day, currency,
+    SUM(price_count), SUM(price_sum), MIN(price_min), MAX(price_max),
+    SUM(excl_sum), MIN(excl_min), MAX(excl_max)
+FROM price_daily_product
+GROUP BY store_id, category, day, currency;
+
+DROP TRIGGER IF EXISTS products_queue_rollup ON products;
+CREATE TRIGGER products_queue_rollup
+    AFTER UPDATE OF category ON products
+    FOR EACH ROW
+    WHEN (OLD.category IS DISTINCT FROM NEW.category)
+    EXECUTE FUNCTION queue_product_rollup();
+
+CREATE OR REPLACE FUNCTION refresh_price_rollups(max_keys INTEGER DEFAULT 10000)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    taken INTEGER;
+BEGIN
+    IF NOT pg_try_advisory_xact_lock(hashtext('refresh_price_rollups')) THEN
+        RETURN 0;
+    END IF;
+
+    CREATE TEMP TABLE IF NOT EXISTS rollup_keys (
+        store_id INTEGER, product_id INTEGER, day DATE
+    ) ON COMMIT DELETE ROWS;
+    CREATE TEMP TABLE IF NOT EXISTS rollup_categories (
+        store_id INTEGER, category VARCHAR(100), day DATE
+    ) ON COMMIT DELETE ROWS;
+    TRUNCATE rollup_keys, rollup_categories;
+
+    WITH batch AS (
+        DELETE FROM price_rollup_pending
+        WHERE (store_id, product_id, day) IN (
+            SELECT store_id, product_id, day
+            FROM price_rollup_pending
+            ORDER BY queued_at
+            LIMIT max_keys
+        )
+        RETURNING store_id, product_id, day
+    )
+    INSERT INTO rollup_keys SELECT store_id, product_id, day FROM batch;
+    GET DIAGNOSTICS taken = ROW_COUNT;
+    IF taken = 0 THEN
+        RETURN 0;
+    END IF;
+
+    WITH removed AS (
+        DELETE FROM price_daily_product d
+        USING rollup_keys k
+        WHERE d.store_id = k.store_id AND d.product_id = k.product_id AND d.day = k.day
+        RETURNING d.store_id, d.category, d.day
+    )
+    INSERT INTO rollup_categories SELECT store_id, category, day FROM removed;
+
+    WITH added AS (
+        INSERT INTO price_daily_product (
+            store_id, product_id, day, currency, category,
+            price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+        )
+        SELECT
+            k.store_id, k.product_id, k.day, p.currency, COALESCE(pr.category, ''),
+            COUNT(*), SUM(p.price), MIN(p.price), MAX(p.price),
+            SUM(exclude_tax(p.price, p.tax_rate)), MIN(exclude_tax(p.price, p.tax_rate)), MAX(exclude_tax(p.price, p.tax_rate))
+        FROM rollup_keys k
+        JOIN prices p
+            ON p.store_id = k.store_id AND p.product_id = k.product_id
+            AND p.recorded_at >= k.day AND p.recorded_at < k.day + 1
+        JOIN products pr ON pr.id = k.product_id
+        WHERE p.status = 'active'
+        GROUP BY k.store_id, k.product_id, k.day, p.currency, pr.category
+        RETURNING store_id, category, day
+    )
+    INSERT INTO rollup_categories SELECT store_id, category, day FROM added;
+
+    DELETE FROM price_daily_category c
+    USING (SELECT DISTINCT store_id, category, day FROM rollup_categories) r
+    WHERE c.store_id = r.store_id AND c.category = r.category AND c.day = r.day;
+
+    INSERT INTO price_daily_category (
+        store_id, category, day, currency,
+        price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+    )
+    SELECT
+        d.store_id, d.category, d.day, d.currency,
+        SUM(d.price_count), SUM(d.price_sum), MIN(d.price_min), MAX(d.price_max),
+        SUM(d.excl_sum), MIN(d.excl_min), MAX(d.excl_max)
+    FROM price_daily_product d
+    JOIN (SELECT DISTINCT store_id, category, day FROM rollup_categories) r
+        ON r.store_id = d.store_id AND r.category = d.category AND r.day = d.day
+    GROUP BY d.store_id, d.category, d.day, d.currency;
+
+    RETURN taken;
+END
+$$;
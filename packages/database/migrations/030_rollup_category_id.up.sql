@@ -0,0 +1,125 @@
+-- The daily rollups are keyed on the product's category_id instead of the
+-- category name, which categories do not have to keep unique. Products
+-- without a category roll up under 0, as they did under '' before.
+ALTER TABLE price_daily_product ADD COLUMN IF NOT EXISTS category_id INTEGER NOT NULL DEFAULT 0;
+
+UPDATE price_daily_product d
+SET category_id = COALESCE(pr.category_id, 0)
+FROM products pr
+WHERE pr.id = d.product_id;
+
+DROP INDEX IF EXISTS idx_price_daily_product_category_day;
+ALTER TABLE price_daily_product DROP COLUMN IF EXISTS category;
+CREATE INDEX IF NOT EXISTS idx_price_daily_product_category_id_day ON price_daily_product(category_id, day);
+
+TRUNCATE price_daily_category;
+ALTER TABLE price_daily_category DROP CONSTRAINT IF EXISTS price_daily_category_pkey;
+ALTER TABLE price_daily_category DROP COLUMN IF EXISTS category;
+ALTER TABLE price_daily_category ADD COLUMN IF NOT EXISTS category_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE price_daily_category ADD PRIMARY KEY (store_id, category_id, day, currency);
+
+INSERT INTO price_daily_category (
+    store_id, category_id, day, currency,
+    price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+)
+SELECT
+    store_id, category_id, day, currency,
+    SUM(price_count), SUM(price_sum), MIN(price_min), MAX(price_max),
+    SUM(excl_sum), MIN(excl_min), MAX(excl_max)
+FROM price_daily_product
+GROUP BY store_id, category_id, day, currency;
+
+-- A product filed under another category moves its days between category
+-- rollups. Renaming a category no longer touches them. The column list
+-- includes category because a new category string is filed by a BEFORE
+-- trigger, which column-specific triggers do not see.
+DROP TRIGGER IF EXISTS products_queue_rollup ON products;
+CREATE TRIGGER products_queue_rollup
+    AFTER UPDATE OF category, category_id ON products
+    FOR EACH ROW
+    WHEN (OLD.category_id IS DISTINCT FROM NEW.category_id)
+    EXECUTE FUNCTION queue_product_rollup();
+
+CREATE OR REPLACE FUNCTION refresh_price_rollups(max_keys INTEGER DEFAULT 10000)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    taken INTEGER;
+BEGIN
+    IF NOT pg_try_advisory_xact_lock(hashtext('refresh_price_rollups')) THEN
+        RETURN 0;
+    END IF;
+
+    CREATE TEMP TABLE IF NOT EXISTS rollup_keys (
+        store_id INTEGER, product_id INTEGER, day DATE
+    ) ON COMMIT DELETE ROWS;
+    CREATE TEMP TABLE IF NOT EXISTS rollup_category_ids (
+        store_id INTEGER, category_id INTEGER, day DATE
+    ) ON COMMIT DELETE ROWS;
+    TRUNCATE rollup_keys, rollup_category_ids;
+
+    WITH batch AS (
+        DELETE FROM price_rollup_pending
+        WHERE (store_id, product_id, day) IN (
+            SELECT store_id, product_id, day
+            FROM price_rollup_pending
+            ORDER BY queued_at
+            LIMIT max_keys
+        )
+        RETURNING store_id, product_id, day
+    )
+    INSERT INTO rollup_keys SELECT store_id, product_id, day FROM batch;
+    GET DIAGNOSTICS taken = ROW_COUNT;
+    IF taken = 0 THEN
+        RETURN 0;
+    END IF;
+
+    WITH removed AS (
+        DELETE FROM price_daily_product d
+        USING rollup_keys k
+        WHERE d.store_id = k.store_id AND d.product_id = k.product_id AND d.day = k.day
+        RETURNING d.store_id, d.category_id, d.day
+    )
+    INSERT INTO rollup_category_ids SELECT store_id, category_id, day FROM removed;
+
+    WITH added AS (
+        INSERT INTO price_daily_product (
+            store_id, product_id, day, currency, category_id,
+            price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+        )
+        SELECT
+            k.store_id, k.product_id, k.day, p.currency, COALESCE(pr.category_id, 0),
+            COUNT(*), SUM(p.price), MIN(p.price), MAX(p.price),
+            SUM(exclude_tax(p.price, p.tax_rate)), MIN(exclude_tax(p.price, p.tax_rate)), MAX(exclude_tax(p.price, p.tax_rate))
+        FROM rollup_keys k
+        JOIN prices p
+            ON p.store_id = k.store_id AND p.product_id = k.product_id
+            AND p.recorded_at >= k.day AND p.recorded_at < k.day + 1
+        JOIN products pr ON pr.id = k.product_id
+        WHERE p.status = 'active'
+        GROUP BY k.store_id, k.product_id, k.day, p.currency, pr.category_id
+        RETURNING store_id, category_id, day
+    )
+    INSERT INTO rollup_category_ids SELECT store_id, category_id, day FROM added;
+
+    DELETE FROM price_daily_category c
+    USING (SELECT DISTINCT store_id, category_id, day FROM rollup_category_ids) r
+    WHERE c.store_id = r.store_id AND c.category_id = r.category_id AND c.day = r.day;
+
+    INSERT INTO price_daily_category (
+        store_id, category_id, day, currency,
+        price_count, price_sum, price_min, price_max, excl_sum, excl_min, excl_max
+    )
+    SELECT
+        d.store_id, d.category_id, d.day, d.currency,
+        SUM(d.price_count), SUM(d.price_sum), MIN(d.price_min), MAX(d.price_max),
+        SUM(d.excl_sum), MIN(d.excl_min), MAX(d.excl_max)
+    FROM price_daily_product d
+    JOIN (SELECT DISTINCT store_id, category_id, day FROM rollup_category_ids) r
+        ON r.store_id = d.store_id AND r.category_id = d.category_id AND r.day = d.day
+    GROUP BY d.store_id, d.category_id, d.day, d.currency;
+
+    RETURN taken;
+END
+$$;